@@ -1,11 +1,11 @@
 package middleware
 
 import (
+	"Mango/app/apperror"
 	model "Mango/app/Model"
+	"Mango/app/jwtkeys"
 	"Mango/app/repository"
 	"context"
-	"net/http"
-	"os"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -17,54 +17,53 @@ func AuthMiddleware(userRepo *repository.UserRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header missing"})
-			c.Abort()
+			WriteProblem(c, apperror.Unauthorized("missing_token", "Authorization header missing"))
 			return
 		}
 
 		tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
-		secret := os.Getenv("JWT_SECRET")
+		keys, err := jwtkeys.Current()
+		if err != nil {
+			WriteProblem(c, apperror.Internal(err))
+			return
+		}
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(secret), nil
-		})
+		// ✅ Verifikasi tanda tangan dengan public key sesuai kid
+		claims := jwt.MapClaims{}
+		token, err := keys.Parse(tokenString, claims)
 		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
+			WriteProblem(c, apperror.Unauthorized("invalid_token", "Invalid token"))
 			return
 		}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
-			c.Abort()
+		// ✅ Token khusus (misalnya challenge MFA) bukan token akses
+		if _, ok := claims["typ"]; ok {
+			WriteProblem(c, apperror.Unauthorized("invalid_token", "Invalid token type"))
 			return
 		}
 
 		userID, _ := claims["sub"].(string)
 		objID, err := primitive.ObjectIDFromHex(userID)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
-			c.Abort()
+			WriteProblem(c, apperror.Unauthorized("invalid_token", "Invalid user ID"))
 			return
 		}
 
 		user, err := userRepo.FindByID(context.Background(), objID)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
-			c.Abort()
+			if apperror.Is(err, apperror.KindNotFound) {
+				err = apperror.Unauthorized("invalid_token", "User not found")
+			}
+			WriteProblem(c, err)
 			return
 		}
 
 		// ✅ Simpan user ke context
 		c.Set("user", user)
 
-		// ✅ Simpan alumni_id ke context (jika ada)
-		if !user.AlumniID.IsZero() {
-			c.Set("alumni_id", user.AlumniID)
+		// ✅ Simpan alumni_id ke context (jika tautannya terverifikasi)
+		if alumniID := user.LinkedAlumni(); !alumniID.IsZero() {
+			c.Set("alumni_id", alumniID)
 		} else {
 			c.Set("alumni_id", user.ID)
 		}
@@ -79,15 +78,13 @@ func RoleMiddleware(requiredRole string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		user, exists := c.Get("user")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-			c.Abort()
+			WriteProblem(c, apperror.Unauthorized("unauthorized", "Unauthorized"))
 			return
 		}
 
 		u := user.(*model.User) // Sesuai model kamu
 		if u.Role != requiredRole {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: insufficient permissions"})
-			c.Abort()
+			WriteProblem(c, apperror.Forbidden("forbidden", "Insufficient permissions"))
 			return
 		}
 
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler memetakan error yang didaftarkan handler lewat c.Error
+// menjadi response application/problem+json (RFC 7807).
+// Harus dipasang sebelum middleware dan route lainnya.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		WriteProblem(c, c.Errors.Last().Err)
+	}
+}
+
+// Recovery mengubah panic di handler menjadi 500 problem+json. Stack
+// trace dicatat gin; client hanya menerima pesan umum.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		WriteProblem(c, apperror.Internal(fmt.Errorf("panic: %v", recovered)))
+	})
+}
+
+// NoRoute menjawab path yang tidak dikenal dengan 404 problem+json.
+func NoRoute() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		WriteProblem(c, apperror.NotFound("route_not_found", "No route for "+c.Request.URL.Path))
+	}
+}
+
+// NoMethod menjawab method yang tidak didukung path-nya dengan 405
+// problem+json. Router harus memasang HandleMethodNotAllowed.
+func NoMethod() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		WriteProblem(c, apperror.MethodNotAllowed("method_not_allowed", "Method "+c.Request.Method+" is not allowed for "+c.Request.URL.Path))
+	}
+}
+
+// WriteProblem menulis satu error sebagai problem+json dan menghentikan chain.
+func WriteProblem(c *gin.Context, err error) {
+	appErr := apperror.From(err)
+	status := appErr.Status()
+
+	if status == http.StatusInternalServerError {
+		log.Printf("❌ %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	}
+
+	problem := model.Problem{
+		Type:     "/problems/" + appErr.Code,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   appErr.Message,
+		Instance: c.Request.URL.Path,
+		Code:     appErr.Code,
+		Errors:   appErr.Fields,
+	}
+
+	if appErr.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(appErr.RetryAfter.Seconds()))))
+	}
+	c.Header("Content-Type", "application/problem+json")
+	c.AbortWithStatusJSON(status, problem)
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.HandleMethodNotAllowed = true
+	router.Use(Recovery(), ErrorHandler())
+	router.NoRoute(NoRoute())
+	router.NoMethod(NoMethod())
+
+	router.GET("/ok", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) })
+	router.GET("/panic", func(c *gin.Context) { panic("boom") })
+	router.GET("/conflict", func(c *gin.Context) { c.Error(apperror.Conflict("nim_taken", "NIM already exists")) })
+	return router
+}
+
+func TestProblemResponses(t *testing.T) {
+	router := newTestRouter()
+	tests := []struct {
+		method, path string
+		status       int
+		code         string
+	}{
+		{http.MethodGet, "/ok", http.StatusOK, ""},
+		{http.MethodGet, "/conflict", http.StatusConflict, "nim_taken"},
+		{http.MethodGet, "/panic", http.StatusInternalServerError, "internal_error"},
+		{http.MethodGet, "/missing", http.StatusNotFound, "route_not_found"},
+		{http.MethodDelete, "/ok", http.StatusMethodNotAllowed, "method_not_allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if tt.code == "" {
+				return
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("Content-Type = %q, want application/problem+json", ct)
+			}
+			var p model.Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+				t.Fatalf("body %q: %v", w.Body.String(), err)
+			}
+			if p.Code != tt.code || p.Status != tt.status || p.Instance != tt.path {
+				t.Errorf("problem = %+v", p)
+			}
+			if tt.status == http.StatusInternalServerError && p.Detail != "An unexpected error occurred" {
+				t.Errorf("panic detail leaked: %q", p.Detail)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"Mango/app/apperror"
+	"Mango/app/ratelimit"
+	"log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit membatasi laju permintaan per IP client dengan bucket
+// bernama name. Jika store gagal, permintaan tetap dilayani dan error
+// dicatat agar gangguan database tidak mengunci semua user.
+func RateLimit(store ratelimit.Store, name string, limit ratelimit.Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		res, err := store.Take(c.Request.Context(), name+":ip:"+c.ClientIP(), limit)
+		if err != nil {
+			log.Printf("⚠️  rate limit %s: %v", name, err)
+		} else if !res.Allowed {
+			WriteProblem(c, apperror.TooManyRequests("rate_limited", "Too many requests, try again later", res.RetryAfter))
+			return
+		}
+
+		c.Next()
+	}
+}
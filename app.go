@@ -0,0 +1,213 @@
+package main
+
+import (
+	"Mango/app/crypt"
+	"Mango/app/jwtkeys"
+	"Mango/app/ratelimit"
+	"Mango/app/repository"
+	"Mango/app/validation"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// application menyimpan konfigurasi dan repository yang dipakai bersama
+// oleh semua subcommand.
+type application struct {
+	client      *mongo.Client
+	db          *mongo.Database
+	port        string
+	keyringPath string
+	jwtKeyDir   string
+
+	userRepo      *repository.UserRepository
+	alumniRepo    *repository.AlumniRepository
+	pekerjaanRepo *repository.PekerjaanRepository
+	companyRepo   *repository.CompanyRepository
+	vocabRepo     *repository.VocabularyRepository
+	uploadRepo    *repository.Filerepository
+	auditRepo     *repository.AuditRepository
+	erasureRepo   *repository.ErasureRepository
+	rateLimits    *ratelimit.MongoStore
+	tokenRepo     *repository.TokenRepository
+	outboxRepo    *repository.OutboxRepository
+}
+
+// bootstrap membaca .env, memuat keyring, terhubung ke MongoDB dan
+// menyiapkan repository. Keyring yang belum ada hanya dilewati jika
+// needKeyring false (untuk `keyring init`).
+func bootstrap(needKeyring bool) *application {
+	// 🔹 Load .env
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("❌ Error loading .env file")
+	}
+
+	// 🔹 Ambil variabel dari .env
+	mongoURI := os.Getenv("MONGO_URI")
+	dbName := os.Getenv("MONGO_DB")
+	port := os.Getenv("SERVER_PORT")
+
+	if mongoURI == "" || dbName == "" {
+		log.Fatal("❌ MONGO_URI or MONGO_DB not found in .env")
+	}
+
+	// 🔹 Daftarkan aturan validasi request
+	if err := validation.Register(); err != nil {
+		log.Fatal("❌ Failed to register validators:", err)
+	}
+
+	// 🔹 Keyring untuk enkripsi field sensitif; dibuat sekali lewat `keyring init`
+	keyringPath := os.Getenv("KEYRING_FILE")
+	if keyringPath == "" {
+		keyringPath = "keyring.json"
+	}
+	jwtKeyDir := os.Getenv("JWT_KEY_DIR")
+	if jwtKeyDir == "" {
+		jwtKeyDir = "jwt-keys"
+	}
+	keyring, err := crypt.LoadKeyring(keyringPath)
+	switch {
+	case err == nil:
+		crypt.Use(keyring)
+	case errors.Is(err, os.ErrNotExist) && !needKeyring:
+	case errors.Is(err, os.ErrNotExist):
+		log.Fatalf("❌ Keyring %s not found; restore it from backup (check KEYRING_FILE and the working directory), or run `keyring init` once for a new deployment", keyringPath)
+	default:
+		log.Fatal("❌ Cannot load keyring:", err)
+	}
+
+	// 🔹 Koneksi ke MongoDB
+	clientOptions := options.Client().ApplyURI(mongoURI)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, clientOptions)
+	if err != nil {
+		log.Fatal("❌ MongoDB connection error:", err)
+	}
+
+	// 🔹 Tes koneksi
+	if err := client.Ping(ctx, nil); err != nil {
+		log.Fatal("❌ Cannot connect to MongoDB:", err)
+	}
+	fmt.Println("✅ Connected to MongoDB!")
+
+	// 🔹 Inisialisasi database dan repository
+	db := client.Database(dbName)
+	return &application{
+		client:        client,
+		db:            db,
+		port:          port,
+		keyringPath:   keyringPath,
+		jwtKeyDir:     jwtKeyDir,
+		userRepo:      repository.NewUserRepository(db),
+		alumniRepo:    repository.NewAlumniRepository(db),
+		pekerjaanRepo: repository.NewPekerjaanRepository(db),
+		companyRepo:   repository.NewCompanyRepository(db),
+		vocabRepo:     repository.NewVocabularyRepository(db),
+		uploadRepo:    repository.NewUploadRepository(db),
+		auditRepo:     repository.NewAuditRepository(db),
+		erasureRepo:   repository.NewErasureRepository(db),
+		rateLimits:    ratelimit.NewMongoStore(db),
+		tokenRepo:     repository.NewTokenRepository(db),
+		outboxRepo:    repository.NewOutboxRepository(db),
+	}
+}
+
+// indexedRepositories adalah repository yang index-nya dipastikan oleh
+// serve dan ensure-indexes.
+func (a *application) indexedRepositories() []repository.IndexedRepository {
+	return []repository.IndexedRepository{a.userRepo, a.alumniRepo, a.pekerjaanRepo, a.companyRepo, a.vocabRepo, a.uploadRepo, a.auditRepo, a.alumniRepo.Revisions(), a.erasureRepo, a.rateLimits, a.tokenRepo, a.outboxRepo}
+}
+
+// loadJWTKeys membaca key tanda tangan JWT dari JWT_KEY_DIR. Issuer dari
+// JWT_ISSUER (default "mango"); key baru baru dipakai setelah
+// JWT_KEY_PUBLISH_DELAY (default 1 jam) di JWKS. Token HS256 lama hanya
+// diterima jika JWT_SECRET dan JWT_LEGACY_UNTIL (RFC 3339, batas akhir
+// masa transisi) sama-sama diisi.
+func (a *application) loadJWTKeys() (*jwtkeys.Set, error) {
+	keys, err := jwtkeys.Open(a.jwtKeyDir)
+	if err != nil {
+		return nil, fmt.Errorf("load JWT keys: %w", err)
+	}
+	keys.Issuer = os.Getenv("JWT_ISSUER")
+	if keys.Issuer == "" {
+		keys.Issuer = "mango"
+	}
+	keys.PublishDelay = envDuration("JWT_KEY_PUBLISH_DELAY", time.Hour)
+	secret, until := os.Getenv("JWT_SECRET"), os.Getenv("JWT_LEGACY_UNTIL")
+	if until != "" {
+		if secret == "" {
+			return nil, fmt.Errorf("JWT_LEGACY_UNTIL is set without JWT_SECRET")
+		}
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT_LEGACY_UNTIL %q: %w", until, err)
+		}
+		keys.LegacySecret, keys.LegacyUntil = []byte(secret), t
+	}
+	return keys, nil
+}
+
+// jwtAlg adalah algoritma key JWT baru (JWT_ALG, default RS256).
+func jwtAlg() string {
+	if alg := os.Getenv("JWT_ALG"); alg != "" {
+		return alg
+	}
+	return jwtkeys.RS256
+}
+
+func (a *application) close() {
+	_ = a.client.Disconnect(context.Background())
+}
+
+// envDuration membaca durasi (misalnya "5m") dari environment.
+func envDuration(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("⚠️  invalid %s=%q, using %s", key, v, fallback)
+		return fallback
+	}
+	return d
+}
+
+// envLimit membaca rate limit "N/durasi" (misalnya "5/1m") dari
+// environment; "off" mematikan limit.
+func envLimit(key, fallback string) ratelimit.Limit {
+	v := os.Getenv(key)
+	if v == "" {
+		v = fallback
+	}
+	limit, err := ratelimit.ParseLimit(v)
+	if err != nil {
+		log.Printf("⚠️  invalid %s=%q, using %s", key, v, fallback)
+		limit, _ = ratelimit.ParseLimit(fallback)
+	}
+	return limit
+}
+
+// envInt membaca bilangan bulat dari environment.
+func envInt(key string, fallback int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("⚠️  invalid %s=%q, using %d", key, v, fallback)
+		return fallback
+	}
+	return n
+}
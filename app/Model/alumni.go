@@ -1,22 +1,70 @@
 package model
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type Alumni struct {
 	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	NIM         string             `bson:"nim,omitempty" json:"nim,omitempty"`
 	Nama        string             `bson:"nama" json:"nama"`
 	Jurusan     string             `bson:"jurusan,omitempty" json:"jurusan,omitempty"`
+	KodeJurusan string             `bson:"kode_jurusan,omitempty" json:"kode_jurusan,omitempty"`
+	Fakultas    string             `bson:"fakultas,omitempty" json:"fakultas,omitempty"`
 	Angkatan    int                `bson:"angkatan,omitempty" json:"angkatan,omitempty"`
 	Tahun_lulus int                `bson:"tahun_lulus,omitempty" json:"tahun_lulus,omitempty"`
-	Email       string             `bson:"email" json:"email"`
-	No_telp     string             `bson:"no_telp" json:"no_telp"`
-	Alamat      string             `bson:"alamat" json:"alamat"`
+	Email       Secret             `bson:"email" json:"email"`
+	EmailIndex  string             `bson:"email_bidx,omitempty" json:"-"` // blind index email, lihat crypt.EmailIndex
+	No_telp     Secret             `bson:"no_telp" json:"no_telp"`
+	Alamat      Secret             `bson:"alamat" json:"alamat"`
+	Search      *AlumniSearch      `bson:"search,omitempty" json:"-"`
+	Privacy     *AlumniPrivacy     `bson:"privacy,omitempty" json:"privacy,omitempty"`
 	CreatedAt   int64              `bson:"created_at" json:"created_at"`
-	UpdateddAt  int64              `bson:"Update_at" json:"Update_at"`
+	UpdatedAt   int64              `bson:"updated_at" json:"updated_at"`
+	Revision    int                `bson:"revision,omitempty" json:"revision,omitempty"`
+	ErasedAt    int64              `bson:"erased_at,omitempty" json:"erased_at,omitempty"` // data pribadi dihapus (UU PDP)
+	Deletion    `bson:",inline"`
 }
 
 type AlumniResponse struct {
-	Data []Alumni `json: "data"`
-	Meta MetaInfo `json: "meta"`
-}
\ No newline at end of file
+	Data []Alumni `json:"data"`
+	Meta MetaInfo `json:"meta"`
+}
+
+// AlumniRequest adalah payload untuk membuat dan memperbarui alumni.
+// ID, created_at dan updated_at tidak bisa diisi oleh client.
+type AlumniRequest struct {
+	NIM         string `json:"nim" binding:"required,nim"`
+	Nama        string `json:"nama" binding:"required,min=2,max=100"`
+	Jurusan     string `json:"jurusan" binding:"required,max=100"`
+	Angkatan    int    `json:"angkatan" binding:"required,year"`
+	Tahun_lulus int    `json:"tahun_lulus" binding:"omitempty,year,gtefield=Angkatan"`
+	Email       string `json:"email" binding:"required,email"`
+	No_telp     string `json:"no_telp" binding:"omitempty,phone_id"`
+	Alamat      string `json:"alamat" binding:"max=255"`
+}
+
+// ToAlumni menyalin payload ke model dengan NIM dan email dinormalisasi.
+func (r AlumniRequest) ToAlumni() Alumni {
+	return Alumni{
+		NIM:         strings.ToUpper(strings.TrimSpace(r.NIM)),
+		Nama:        strings.TrimSpace(r.Nama),
+		Jurusan:     strings.TrimSpace(r.Jurusan),
+		Angkatan:    r.Angkatan,
+		Tahun_lulus: r.Tahun_lulus,
+		Email:       Secret(strings.ToLower(strings.TrimSpace(r.Email))),
+		No_telp:     Secret(r.No_telp),
+		Alamat:      Secret(strings.TrimSpace(r.Alamat)),
+	}
+}
+
+// AlumniFilter adalah parameter query yang dipakai bersama oleh daftar
+// alumni dan export.
+type AlumniFilter struct {
+	Q          string `form:"q" json:"q" binding:"max=100"`
+	Jurusan    string `form:"jurusan" json:"jurusan"`
+	Angkatan   int    `form:"angkatan" json:"angkatan" binding:"omitempty,year"`
+	TahunLulus int    `form:"tahun_lulus" json:"tahun_lulus" binding:"omitempty,year"`
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditEntry adalah satu catatan perubahan data di audit_log. Actor
+// kosong berarti perubahan tanpa login (misalnya registrasi).
+type AuditEntry struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	At        time.Time           `bson:"at" json:"at"`
+	ActorID   *primitive.ObjectID `bson:"actor_id,omitempty" json:"actor_id,omitempty"`
+	Actor     string              `bson:"actor,omitempty" json:"actor,omitempty"`
+	Role      string              `bson:"role,omitempty" json:"role,omitempty"`
+	Action    string              `bson:"action" json:"action"`
+	Entity    string              `bson:"entity" json:"entity"`
+	EntityID  *primitive.ObjectID `bson:"entity_id,omitempty" json:"entity_id,omitempty"`
+	Changes   []FieldChange       `bson:"changes,omitempty" json:"changes,omitempty"`
+	Note      string              `bson:"note,omitempty" json:"note,omitempty"`
+	IP        string              `bson:"ip,omitempty" json:"ip,omitempty"`
+	RequestID string              `bson:"request_id,omitempty" json:"request_id,omitempty"`
+}
+
+// FieldChange adalah nilai satu field sebelum dan sesudah perubahan.
+// Field bersarang ditulis dengan titik, misalnya "gaji.min".
+type FieldChange struct {
+	Field  string `bson:"field" json:"field"`
+	Before any    `bson:"before,omitempty" json:"before,omitempty"`
+	After  any    `bson:"after,omitempty" json:"after,omitempty"`
+}
+
+// Aksi audit.
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditMerge   = "merge"
+	AuditImport  = "import"
+	AuditRevert  = "revert"
+	AuditExport  = "export"
+	AuditErase   = "erase"
+)
+
+// Entitas audit.
+const (
+	EntityAlumni    = "alumni"
+	EntityPekerjaan = "pekerjaan"
+	EntityUser      = "user"
+	EntityUpload    = "upload"
+	EntityCompany   = "company"
+	EntityTerm      = "term"
+)
+
+// AuditQuery adalah filter pencarian audit log. Actor boleh berupa ID
+// user atau username; from dan to berupa RFC 3339, tanggal (YYYY-MM-DD)
+// atau unix detik.
+type AuditQuery struct {
+	Entity   string `form:"entity" binding:"omitempty,oneof=alumni pekerjaan user upload company term"`
+	EntityID string `form:"entity_id" binding:"omitempty,mongodb"`
+	Actor    string `form:"actor" binding:"max=100"`
+	Action   string `form:"action" binding:"omitempty,oneof=create update delete restore merge import revert export erase"`
+	From     string `form:"from"`
+	To       string `form:"to"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+// AuditFilter adalah AuditQuery yang sudah diurai.
+type AuditFilter struct {
+	Entity   string
+	EntityID primitive.ObjectID
+	ActorID  primitive.ObjectID
+	Actor    string
+	Action   string
+	From     time.Time
+	To       time.Time
+	Limit    int
+}
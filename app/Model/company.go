@@ -0,0 +1,100 @@
+package model
+
+import (
+	"Mango/app/fuzzy"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Company adalah perusahaan tempat alumni bekerja. NameKey dan AliasKeys
+// adalah bentuk ternormalisasi (lihat CompanyKey) untuk pencocokan.
+type Company struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name      string             `bson:"name" json:"name"`
+	Aliases   []string           `bson:"aliases,omitempty" json:"aliases,omitempty"`
+	Industry  string             `bson:"industry,omitempty" json:"industry,omitempty"`
+	Location  string             `bson:"location,omitempty" json:"location,omitempty"`
+	Website   string             `bson:"website,omitempty" json:"website,omitempty"`
+	NameKey   string             `bson:"name_key" json:"-"`
+	AliasKeys []string           `bson:"alias_keys,omitempty" json:"-"`
+	CreatedAt int64              `bson:"created_at" json:"created_at"`
+	UpdatedAt int64              `bson:"updated_at" json:"updated_at"`
+}
+
+// CompanyRequest adalah payload create/update perusahaan.
+type CompanyRequest struct {
+	Name     string   `json:"name" binding:"required,min=2,max=150"`
+	Aliases  []string `json:"aliases" binding:"max=20,dive,min=2,max=150"`
+	Industry string   `json:"industry" binding:"max=100"`
+	Location string   `json:"location" binding:"max=100"`
+	Website  string   `json:"website" binding:"omitempty,url,max=255"`
+}
+
+func (r CompanyRequest) ToCompany() Company {
+	c := Company{
+		Name:     strings.TrimSpace(r.Name),
+		Industry: strings.TrimSpace(r.Industry),
+		Location: strings.TrimSpace(r.Location),
+		Website:  strings.TrimSpace(r.Website),
+	}
+	for _, a := range r.Aliases {
+		if a = strings.TrimSpace(a); a != "" {
+			c.Aliases = append(c.Aliases, a)
+		}
+	}
+	return c
+}
+
+// MergeCompaniesRequest memindahkan pekerjaan dari perusahaan duplikat ke
+// perusahaan tujuan.
+type MergeCompaniesRequest struct {
+	SourceIDs []string `json:"source_ids" binding:"required,min=1,dive,mongodb"`
+}
+
+// CompanySuggestion adalah hasil pencarian fuzzy perusahaan.
+type CompanySuggestion struct {
+	ID        primitive.ObjectID `json:"id"`
+	Name      string             `json:"name"`
+	MatchedOn string             `json:"matched_on"`
+	Score     float64            `json:"score"`
+}
+
+// TopEmployer adalah perusahaan beserta jumlah alumni yang bekerja di sana.
+type TopEmployer struct {
+	CompanyID primitive.ObjectID `bson:"_id" json:"company_id"`
+	Name      string             `bson:"name" json:"name"`
+	Alumni    int                `bson:"alumni" json:"alumni"`
+}
+
+// legalForms adalah kata bentuk badan usaha yang diabaikan saat
+// membandingkan nama perusahaan.
+var legalForms = map[string]bool{
+	"pt": true, "cv": true, "tbk": true, "persero": true, "ud": true, "pd": true,
+	"perum": true, "inc": true, "ltd": true, "llc": true, "corp": true, "co": true,
+}
+
+// CompanyKey menormalkan nama perusahaan sehingga "PT. Telkom (Persero)
+// Tbk" dan "telkom" menghasilkan key yang sama.
+func CompanyKey(name string) string {
+	var words []string
+	for _, w := range strings.Fields(fuzzy.Fold(name)) {
+		if !legalForms[w] {
+			words = append(words, w)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// SetKeys mengisi NameKey dan AliasKeys dari Name dan Aliases.
+func (c *Company) SetKeys() {
+	c.NameKey = CompanyKey(c.Name)
+	c.AliasKeys = nil
+	seen := map[string]bool{c.NameKey: true}
+	for _, a := range c.Aliases {
+		if k := CompanyKey(a); k != "" && !seen[k] {
+			seen[k] = true
+			c.AliasKeys = append(c.AliasKeys, k)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompanyKey(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"PT. Telkom Indonesia (Persero) Tbk", "telkom indonesia"},
+		{"telkom indonesia", "telkom indonesia"},
+		{"CV Maju-Jaya", "maju jaya"},
+		{"Google LLC", "google"},
+		{"Bukalapak.com", "bukalapak com"},
+		{"PT Tbk", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := CompanyKey(tt.in); got != tt.want {
+			t.Errorf("CompanyKey(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCompanySetKeys(t *testing.T) {
+	tests := []struct {
+		name      string
+		company   Company
+		nameKey   string
+		aliasKeys []string
+	}{
+		{
+			name:    "no aliases",
+			company: Company{Name: "PT Gojek Indonesia"},
+			nameKey: "gojek indonesia",
+		},
+		{
+			name:      "aliases normalised",
+			company:   Company{Name: "PT Telekomunikasi Indonesia Tbk", Aliases: []string{"Telkom", "TLKM"}},
+			nameKey:   "telekomunikasi indonesia",
+			aliasKeys: []string{"telkom", "tlkm"},
+		},
+		{
+			name:      "duplicate and empty aliases dropped",
+			company:   Company{Name: "Bank Mandiri", Aliases: []string{"PT Bank Mandiri", "Mandiri", "mandiri", "PT"}},
+			nameKey:   "bank mandiri",
+			aliasKeys: []string{"mandiri"},
+		},
+		{
+			name:    "stale alias keys replaced",
+			company: Company{Name: "Tokopedia", AliasKeys: []string{"old"}},
+			nameKey: "tokopedia",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.company
+			c.SetKeys()
+			if c.NameKey != tt.nameKey {
+				t.Errorf("NameKey = %q, want %q", c.NameKey, tt.nameKey)
+			}
+			if !reflect.DeepEqual(c.AliasKeys, tt.aliasKeys) {
+				t.Errorf("AliasKeys = %q, want %q", c.AliasKeys, tt.aliasKeys)
+			}
+		})
+	}
+}
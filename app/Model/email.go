@@ -0,0 +1,69 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tujuan token sekali pakai yang dikirim lewat email.
+const (
+	TokenVerifyEmail   = "verify_email"
+	TokenPasswordReset = "password_reset"
+)
+
+// UserToken adalah token sekali pakai untuk verifikasi email atau reset
+// password. Yang disimpan hanya hash token; tokennya sendiri hanya ada di
+// email. Token verifikasi terikat ke email saat token dibuat.
+type UserToken struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `bson:"user_id"`
+	Purpose    string             `bson:"purpose"`
+	Hash       string             `bson:"hash"`
+	EmailIndex string             `bson:"email_bidx,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at"`
+	ExpiresAt  time.Time          `bson:"expires_at"`
+	UsedAt     *time.Time         `bson:"used_at,omitempty"`
+}
+
+// Status email di outbox.
+const (
+	EmailPending = "pending"
+	EmailSent    = "sent"
+	EmailFailed  = "failed"
+)
+
+// OutboxEmail adalah email yang antre di email_outbox. Subject dan isi
+// sudah dirender saat diantrekan; isi disimpan terenkripsi karena berisi
+// link dengan token.
+type OutboxEmail struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID        primitive.ObjectID `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	To            Secret             `bson:"to" json:"to"`
+	Template      string             `bson:"template" json:"template"`
+	Locale        string             `bson:"locale" json:"locale"`
+	Subject       string             `bson:"subject" json:"subject"`
+	Body          Secret             `bson:"body" json:"-"`
+	Status        string             `bson:"status" json:"status"`
+	Attempts      int                `bson:"attempts" json:"attempts"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at" json:"next_attempt_at"`
+	LastError     string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	SentAt        *time.Time         `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
+}
+
+// VerifyEmailInput adalah payload konfirmasi verifikasi email.
+type VerifyEmailInput struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ForgotPasswordInput adalah payload permintaan reset password.
+type ForgotPasswordInput struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordInput adalah payload penggantian password dengan token.
+type ResetPasswordInput struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,max=72"`
+}
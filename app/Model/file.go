@@ -8,7 +8,7 @@ import (
 
 type Files struct {
 	ID primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UserID primitive.ObjectID `bson: "user_id,omitempty" json:"user_id"`
+	UserID primitive.ObjectID `bson:"user_id,omitempty" json:"user_id"`
 	Type string `bson:"type" json:"type"`
 	Filepath string `bson:"file_path" json:"file_path"`
 	Filename string `bson:"file_name"  json:"file_name"`
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AlumniRevision adalah salinan lengkap dokumen alumni setelah satu kali
+// penulisan. Version sama dengan Alumni.Revision saat salinan dibuat.
+type AlumniRevision struct {
+	ID       primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	AlumniID primitive.ObjectID  `bson:"alumni_id" json:"alumni_id"`
+	Version  int                 `bson:"version" json:"version"`
+	At       time.Time           `bson:"at" json:"at"`
+	Action   string              `bson:"action" json:"action"` // AuditCreate, AuditUpdate, ...
+	By       *primitive.ObjectID `bson:"by,omitempty" json:"by,omitempty"`
+	Snapshot Alumni              `bson:"snapshot" json:"snapshot"`
+}
+
+// AlumniVersion adalah satu baris riwayat alumni: siapa mengubah apa
+// dibanding versi sebelumnya.
+type AlumniVersion struct {
+	Version int                 `json:"version"`
+	At      time.Time           `json:"at"`
+	Action  string              `json:"action"`
+	By      *primitive.ObjectID `json:"by,omitempty"`
+	Changes []FieldChange       `json:"changes,omitempty"`
+}
+
+// AlumniAsOfQuery meminta profil alumni pada waktu tertentu (RFC 3339,
+// YYYY-MM-DD untuk akhir hari tersebut, atau unix detik).
+type AlumniAsOfQuery struct {
+	AsOf string `form:"asOf"`
+}
+
+// RevertAlumniRequest adalah payload untuk mengembalikan alumni ke versi
+// sebelumnya.
+type RevertAlumniRequest struct {
+	Version int `json:"version" binding:"required,min=1"`
+}
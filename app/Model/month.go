@@ -0,0 +1,94 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+const monthLayout = "2006-01"
+
+// Month adalah tanggal dengan presisi bulan. Disimpan di MongoDB sebagai
+// date (tanggal 1, UTC) dan di JSON sebagai "YYYY-MM".
+type Month struct {
+	time.Time
+}
+
+func NewMonth(year int, month time.Month) Month {
+	return Month{time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+// ParseMonth membaca string "YYYY-MM".
+func ParseMonth(s string) (Month, error) {
+	t, err := time.Parse(monthLayout, s)
+	if err != nil {
+		return Month{}, fmt.Errorf("invalid month %q (want YYYY-MM)", s)
+	}
+	return Month{t}, nil
+}
+
+// CurrentMonth adalah bulan berjalan.
+func CurrentMonth() Month {
+	now := time.Now().UTC()
+	return NewMonth(now.Year(), now.Month())
+}
+
+func (m Month) String() string {
+	return m.Format(monthLayout)
+}
+
+// MonthsUntil mengembalikan selisih bulan dari m ke other.
+func (m Month) MonthsUntil(other Month) int {
+	return (other.Year()-m.Year())*12 + int(other.Month()) - int(m.Month())
+}
+
+func (m Month) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+func (m *Month) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := ParseMonth(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+func (m Month) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue(m.Time)
+}
+
+// UnmarshalBSONValue juga menerima tahun berupa angka (format lama
+// sebelum migration pekerjaan_month_dates) sebagai Januari tahun itu.
+func (m *Month) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	switch t {
+	case bsontype.Int32, bsontype.Int64, bsontype.Double:
+		var year int64
+		if err := bson.UnmarshalValue(t, data, &year); err != nil {
+			return err
+		}
+		if year > 0 {
+			*m = NewMonth(int(year), time.January)
+		}
+		return nil
+	case bsontype.Null, bsontype.Undefined:
+		*m = Month{}
+		return nil
+	}
+
+	var tm time.Time
+	if err := bson.UnmarshalValue(t, data, &tm); err != nil {
+		return err
+	}
+	tm = tm.UTC()
+	*m = NewMonth(tm.Year(), tm.Month())
+	return nil
+}
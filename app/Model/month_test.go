@@ -0,0 +1,124 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type monthDoc struct {
+	Mulai   Month  `bson:"mulai" json:"mulai"`
+	Selesai *Month `bson:"selesai,omitempty" json:"selesai,omitempty"`
+}
+
+func TestMonthJSON(t *testing.T) {
+	end := NewMonth(2023, time.March)
+	doc := monthDoc{Mulai: NewMonth(2020, time.August), Selesai: &end}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"mulai":"2020-08","selesai":"2023-03"}`; string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+
+	var back monthDoc
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !back.Mulai.Equal(doc.Mulai.Time) || back.Selesai == nil || !back.Selesai.Equal(end.Time) {
+		t.Errorf("round trip = %+v, want %+v", back, doc)
+	}
+}
+
+func TestMonthUnmarshalJSONInvalid(t *testing.T) {
+	for _, in := range []string{`"2020-13"`, `"2020-8-1"`, `"Agustus 2020"`, `2020`, `""`} {
+		var m Month
+		if err := json.Unmarshal([]byte(in), &m); err == nil {
+			t.Errorf("Unmarshal(%s) = %v, want error", in, m)
+		}
+	}
+}
+
+func TestMonthBSON(t *testing.T) {
+	end := NewMonth(2023, time.March)
+	doc := monthDoc{Mulai: NewMonth(2020, time.August), Selesai: &end}
+
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw bson.M
+	if err := bson.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := raw["mulai"].(interface{ Time() time.Time }); !ok {
+		t.Errorf("mulai stored as %T, want date", raw["mulai"])
+	}
+
+	var back monthDoc
+	if err := bson.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.Mulai.String() != "2020-08" || back.Selesai == nil || back.Selesai.String() != "2023-03" {
+		t.Errorf("round trip = %v %v, want 2020-08 2023-03", back.Mulai, back.Selesai)
+	}
+}
+
+func TestMonthUnmarshalBSONLegacy(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"year int32", int32(2019), "2019-01"},
+		{"year int64", int64(2021), "2021-01"},
+		{"year double", 2018.0, "2018-01"},
+		{"mid-month date", time.Date(2022, time.May, 17, 13, 0, 0, 0, time.UTC), "2022-05"},
+		{"date in another zone", time.Date(2022, time.June, 1, 3, 0, 0, 0, time.FixedZone("WIB", 7*3600)), "2022-05"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := bson.Marshal(bson.M{"mulai": tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var doc monthDoc
+			if err := bson.Unmarshal(data, &doc); err != nil {
+				t.Fatal(err)
+			}
+			if got := doc.Mulai.String(); got != tt.want {
+				t.Errorf("Mulai = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	data, _ := bson.Marshal(bson.M{"mulai": nil, "selesai": int32(0)})
+	var doc monthDoc
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if !doc.Mulai.IsZero() || (doc.Selesai != nil && !doc.Selesai.IsZero()) {
+		t.Errorf("null and year 0 = %v %v, want zero months", doc.Mulai, doc.Selesai)
+	}
+}
+
+func TestMonthsUntil(t *testing.T) {
+	tests := []struct {
+		from, to Month
+		want     int
+	}{
+		{NewMonth(2020, time.August), NewMonth(2020, time.August), 0},
+		{NewMonth(2020, time.August), NewMonth(2021, time.February), 6},
+		{NewMonth(2020, time.December), NewMonth(2021, time.January), 1},
+		{NewMonth(2021, time.March), NewMonth(2020, time.March), -12},
+	}
+	for _, tt := range tests {
+		if got := tt.from.MonthsUntil(tt.to); got != tt.want {
+			t.Errorf("%v.MonthsUntil(%v) = %d, want %d", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
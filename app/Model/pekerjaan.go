@@ -5,15 +5,82 @@ import "go.mongodb.org/mongo-driver/bson/primitive"
 type Pekerjaan struct {
 	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	AlumniID        primitive.ObjectID `bson:"alumni_id,omitempty" json:"alumni_id,omitempty"`
+	CompanyID       primitive.ObjectID `bson:"company_id,omitempty" json:"company_id,omitempty"`
 	Nama_perusahaan string             `bson:"nama_perusahaan,omitempty" json:"nama_perusahaan,omitempty"`
 	Posisi_jabatan  string             `bson:"posisi_jabatan,omitempty" json:"posisi_jabatan,omitempty"`
 	Bidang_Industri string             `bson:"bidang_industri,omitempty" json:"bidang_industri,omitempty"`
-	Lokasi_kerja    string             `bson: "lokasi_kerja,omitempty" json: "lokasi_kerja,omitempty"`
-	Gaji_range      string             `bson: "gaji_range,omitempty" json: "gaji_range,omitempty"`
-	Tanggal_Kerja   int64              `bson: "tanggal_kerja" json: "tanggal_kerja"`
-	Tanggal_selesai int64              `bson: "tanggal_selesai" json: "tanggal_selesai"`
-	Status          string             `bson: "status" json: "status"`
+	Lokasi_kerja    string             `bson:"lokasi_kerja,omitempty" json:"lokasi_kerja,omitempty"`
+	KodeIndustri    string             `bson:"kode_industri,omitempty" json:"kode_industri,omitempty"`
+	KodeLokasi      string             `bson:"kode_lokasi,omitempty" json:"kode_lokasi,omitempty"`
+	Gaji            *Salary            `bson:"gaji,omitempty" json:"gaji,omitempty"`
+	Tanggal_Kerja   Month              `bson:"tanggal_kerja" json:"tanggal_kerja"`
+	Tanggal_selesai *Month             `bson:"tanggal_selesai,omitempty" json:"tanggal_selesai,omitempty"`
+	Status          string             `bson:"status" json:"status"`
 	Description     string             `bson:"deskripsi,omitempty" json:"deskripsi,omitempty"`
 	CreatedAt       int64              `bson:"created_at" json:"created_at"`
-	UpdatedAt       int64              `bson:"Updated_at" json:"Updated_at"`
+	UpdatedAt       int64              `bson:"updated_at" json:"updated_at"`
+	Deletion        `bson:",inline"`
+}
+
+// Status pekerjaan. StatusEnded berarti tanggal_selesai sudah lewat;
+// status lain berlaku untuk periode yang masih berjalan.
+const (
+	StatusCurrent      = "current"
+	StatusEnded        = "ended"
+	StatusFreelance    = "freelance"
+	StatusEntrepreneur = "entrepreneur"
+	StatusFurtherStudy = "further_study"
+)
+
+// IsFullTime melaporkan apakah status termasuk pekerjaan penuh waktu yang
+// tidak boleh tumpang tindih.
+func IsFullTime(status string) bool {
+	return status == StatusCurrent || status == StatusEnded
+}
+
+// CreatePekerjaanRequest adalah payload untuk menambah pekerjaan alumni.
+// Tanggal memakai format "YYYY-MM". Status boleh kosong dan akan
+// diturunkan dari tanggal_selesai. Dengan company_id, nama_perusahaan
+// diisi dari direktori perusahaan.
+type CreatePekerjaanRequest struct {
+	AlumniID        string         `json:"alumni_id" binding:"omitempty,mongodb"`
+	CompanyID       string         `json:"company_id" binding:"omitempty,mongodb"`
+	Nama_perusahaan string         `json:"nama_perusahaan" binding:"required_without=CompanyID,max=150"`
+	Posisi_jabatan  string         `json:"posisi_jabatan" binding:"required,max=100"`
+	Bidang_Industri string         `json:"bidang_industri" binding:"required,max=100"`
+	Lokasi_kerja    string         `json:"lokasi_kerja" binding:"required,max=100"`
+	Gaji            *SalaryRequest `json:"gaji"`
+	Tanggal_Kerja   string         `json:"tanggal_kerja" binding:"required,month"`
+	Tanggal_selesai string         `json:"tanggal_selesai" binding:"omitempty,month"`
+	Status          string         `json:"status" binding:"omitempty,oneof=current ended freelance entrepreneur further_study"`
+	Description     string         `json:"deskripsi" binding:"max=1000"`
+	// AllowOverlap mengizinkan periode yang tumpang tindih dengan
+	// pekerjaan penuh waktu lain milik alumni yang sama.
+	AllowOverlap bool `json:"allow_overlap"`
+}
+
+// UpdatePekerjaanRequest adalah payload update sebagian; field kosong
+// tidak diubah. Mengirim status "current" tanpa tanggal_selesai menghapus
+// tanggal_selesai.
+type UpdatePekerjaanRequest struct {
+	CompanyID       string         `json:"company_id" binding:"omitempty,mongodb"`
+	Nama_perusahaan string         `json:"nama_perusahaan" binding:"max=150"`
+	Posisi_jabatan  string         `json:"posisi_jabatan" binding:"max=100"`
+	Bidang_Industri string         `json:"bidang_industri" binding:"max=100"`
+	Lokasi_kerja    string         `json:"lokasi_kerja" binding:"max=100"`
+	Gaji            *SalaryRequest `json:"gaji"`
+	Tanggal_Kerja   string         `json:"tanggal_kerja" binding:"omitempty,month"`
+	Tanggal_selesai string         `json:"tanggal_selesai" binding:"omitempty,month"`
+	Status          string         `json:"status" binding:"omitempty,oneof=current ended freelance entrepreneur further_study"`
+	Description     string         `json:"deskripsi" binding:"max=1000"`
+	AllowOverlap    bool           `json:"allow_overlap"`
+}
+
+// PekerjaanFilter adalah parameter query yang dipakai bersama oleh daftar
+// pekerjaan dan export.
+type PekerjaanFilter struct {
+	AlumniID       string `form:"alumni_id" json:"alumni_id" binding:"omitempty,mongodb"`
+	Perusahaan     string `form:"perusahaan" json:"perusahaan" binding:"max=100"`
+	BidangIndustri string `form:"bidang_industri" json:"bidang_industri"`
+	LokasiKerja    string `form:"lokasi_kerja" json:"lokasi_kerja"`
 }
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DataSubject adalah pemilik data pribadi menurut UU PDP: akun user
+// dan/atau alumni yang tertaut. Salah satunya boleh kosong.
+type DataSubject struct {
+	UserID   primitive.ObjectID
+	AlumniID primitive.ObjectID
+}
+
+// ErasedName menggantikan nama alumni yang datanya sudah dihapus.
+const ErasedName = "Anonim"
+
+// Status permintaan penghapusan data.
+const (
+	ErasurePending   = "pending"
+	ErasureRejected  = "rejected"
+	ErasureCompleted = "completed"
+)
+
+// ErasureRequest adalah permintaan penghapusan data pribadi dari user
+// yang harus disetujui admin.
+type ErasureRequest struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	AlumniID    primitive.ObjectID  `bson:"alumni_id,omitempty" json:"alumni_id,omitempty"`
+	Reason      string              `bson:"reason,omitempty" json:"reason,omitempty"`
+	Status      string              `bson:"status" json:"status"`
+	RequestedAt time.Time           `bson:"requested_at" json:"requested_at"`
+	DecidedAt   *time.Time          `bson:"decided_at,omitempty" json:"decided_at,omitempty"`
+	DecidedBy   *primitive.ObjectID `bson:"decided_by,omitempty" json:"decided_by,omitempty"`
+	Note        string              `bson:"note,omitempty" json:"note,omitempty"`
+	Report      *ErasureReport      `bson:"report,omitempty" json:"report,omitempty"`
+}
+
+// ErasureReport adalah hasil penghapusan data. Alumni dan pekerjaan
+// dianonimkan (tetap terhitung di statistik); akun, upload dan riwayat
+// versi dihapus; audit log dibersihkan dari nilai field dan username.
+type ErasureReport struct {
+	Alumni       int64 `bson:"alumni" json:"alumni"`
+	Pekerjaan    int64 `bson:"pekerjaan" json:"pekerjaan"`
+	Users        int64 `bson:"users" json:"users"`
+	Uploads      int64 `bson:"uploads" json:"uploads"`
+	Revisions    int64 `bson:"revisions" json:"revisions"`
+	AuditEntries int64 `bson:"audit_entries" json:"audit_entries"`
+}
+
+// ErasureRequestBody adalah payload permintaan penghapusan data.
+type ErasureRequestBody struct {
+	Reason string `json:"reason" binding:"max=1000"`
+}
+
+// ErasureDecision adalah catatan admin saat menyetujui atau menolak.
+type ErasureDecision struct {
+	Note string `json:"note" binding:"max=1000"`
+}
+
+// ErasureQuery adalah filter daftar permintaan penghapusan data.
+type ErasureQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending rejected completed"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+// Tingkat visibilitas field kontak alumni. Admin dan alumni pemilik data
+// selalu melihat semua field.
+//   - public: semua user yang login
+//   - alumni: user yang tertaut ke alumni (sesama lulusan)
+//   - admin: hanya admin
+const (
+	VisibilityPublic = "public"
+	VisibilityAlumni = "alumni"
+	VisibilityAdmin  = "admin"
+)
+
+// AlumniPrivacy adalah pengaturan privasi field kontak alumni. Field
+// kosong berarti memakai default (DefaultPrivacy). Nomor telepon yang
+// terlihat tetap disamarkan kecuali ShowFullPhone.
+type AlumniPrivacy struct {
+	Email         string `bson:"email,omitempty" json:"email"`
+	NoTelp        string `bson:"no_telp,omitempty" json:"no_telp"`
+	Alamat        string `bson:"alamat,omitempty" json:"alamat"`
+	ShowFullPhone bool   `bson:"show_full_phone,omitempty" json:"show_full_phone"`
+}
+
+// DefaultPrivacy berlaku untuk alumni yang belum mengatur privasinya.
+var DefaultPrivacy = AlumniPrivacy{
+	Email:  VisibilityAlumni,
+	NoTelp: VisibilityAlumni,
+	Alamat: VisibilityAdmin,
+}
+
+// Resolve melengkapi pengaturan yang kosong dengan default. Aman untuk
+// receiver nil.
+func (p *AlumniPrivacy) Resolve() AlumniPrivacy {
+	r := DefaultPrivacy
+	if p == nil {
+		return r
+	}
+	if p.Email != "" {
+		r.Email = p.Email
+	}
+	if p.NoTelp != "" {
+		r.NoTelp = p.NoTelp
+	}
+	if p.Alamat != "" {
+		r.Alamat = p.Alamat
+	}
+	r.ShowFullPhone = p.ShowFullPhone
+	return r
+}
+
+// PrivacyRequest adalah payload untuk mengubah pengaturan privasi.
+// Field yang tidak dikirim tidak berubah.
+type PrivacyRequest struct {
+	Email         string `json:"email" binding:"omitempty,oneof=public alumni admin"`
+	NoTelp        string `json:"no_telp" binding:"omitempty,oneof=public alumni admin"`
+	Alamat        string `json:"alamat" binding:"omitempty,oneof=public alumni admin"`
+	ShowFullPhone *bool  `json:"show_full_phone"`
+}
+
+// Apply menggabungkan request ke pengaturan yang ada.
+func (r PrivacyRequest) Apply(p AlumniPrivacy) AlumniPrivacy {
+	if r.Email != "" {
+		p.Email = r.Email
+	}
+	if r.NoTelp != "" {
+		p.NoTelp = r.NoTelp
+	}
+	if r.Alamat != "" {
+		p.Alamat = r.Alamat
+	}
+	if r.ShowFullPhone != nil {
+		p.ShowFullPhone = *r.ShowFullPhone
+	}
+	return p
+}
@@ -1,5 +1,7 @@
 package model
 
+import "Mango/app/apperror"
+
 type MetaInfo struct {
 	Page   int  `json:"page"`
 	Limit  int  `json:"limit"`
@@ -14,3 +16,14 @@ type UserResponse struct {
 	Data []User  `json:"data"`
 	Meta MetaInfo `json:"meta"`
 }
+
+// Problem adalah body error standar RFC 7807 (application/problem+json).
+type Problem struct {
+	Type     string                `json:"type"`
+	Title    string                `json:"title"`
+	Status   int                   `json:"status"`
+	Detail   string                `json:"detail,omitempty"`
+	Instance string                `json:"instance,omitempty"`
+	Code     string                `json:"code"`
+	Errors   []apperror.FieldError `json:"errors,omitempty"`
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"Mango/app/crypt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+const (
+	CurrencyIDR = "IDR"
+	PeriodMonth = "month"
+	PeriodYear  = "year"
+)
+
+// Salary adalah rentang gaji terstruktur. Max 0 berarti tanpa batas atas
+// (misalnya "> 10 juta").
+type Salary struct {
+	Min      int64  `bson:"min" json:"min"`
+	Max      int64  `bson:"max,omitempty" json:"max,omitempty"`
+	Currency string `bson:"currency" json:"currency"`
+	Period   string `bson:"period" json:"period"`
+}
+
+// plainSalary adalah Salary tanpa hook BSON, untuk (de)serialisasi isi
+// yang dienkripsi.
+type plainSalary Salary
+
+// MarshalBSONValue menyimpan gaji sebagai satu nilai terenkripsi. Akibatnya
+// gaji tidak bisa di-query di MongoDB; statistik gaji dihitung di aplikasi.
+func (s Salary) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	t, data, err := bson.MarshalValue(plainSalary(s))
+	if err != nil {
+		return t, data, err
+	}
+	return crypt.Seal(t, data)
+}
+
+// UnmarshalBSONValue juga menerima dokumen plaintext (data sebelum
+// migration encrypt_sensitive_fields).
+func (s *Salary) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	t, data, err := crypt.Open(t, data)
+	if err != nil {
+		return err
+	}
+	switch t {
+	case bsontype.Null, bsontype.Undefined:
+		*s = Salary{}
+		return nil
+	}
+	return bson.UnmarshalValue(t, data, (*plainSalary)(s))
+}
+
+// SalaryRequest adalah input gaji. Currency default IDR dan period
+// default month.
+type SalaryRequest struct {
+	Min      int64  `json:"min" binding:"required,gt=0"`
+	Max      int64  `json:"max" binding:"omitempty,gtefield=Min"`
+	Currency string `json:"currency" binding:"omitempty,len=3"`
+	Period   string `json:"period" binding:"omitempty,oneof=month year"`
+}
+
+func (r SalaryRequest) ToSalary() Salary {
+	s := Salary{
+		Min:      r.Min,
+		Max:      r.Max,
+		Currency: strings.ToUpper(strings.TrimSpace(r.Currency)),
+		Period:   r.Period,
+	}
+	if s.Currency == "" {
+		s.Currency = CurrencyIDR
+	}
+	if s.Period == "" {
+		s.Period = PeriodMonth
+	}
+	return s
+}
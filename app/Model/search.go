@@ -0,0 +1,27 @@
+package model
+
+// AlumniSearch adalah field turunan di dokumen alumni untuk pencarian:
+// kata nama ternormalisasi (kandidat salah ketik) serta nama perusahaan
+// dan jabatan dari riwayat pekerjaan. Diperbarui oleh repository setiap
+// alumni atau pekerjaannya berubah.
+type AlumniSearch struct {
+	NameKeys  []string `bson:"name_keys,omitempty"`
+	Employers []string `bson:"employers,omitempty"`
+	Positions []string `bson:"positions,omitempty"`
+}
+
+// SearchQuery adalah parameter pencarian alumni.
+type SearchQuery struct {
+	Q     string `form:"q" binding:"required,min=2,max=100"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// SearchHit adalah satu hasil pencarian. Highlights berisi fragmen field
+// yang cocok dengan kata query dibungkus <em>, per nama field (nama, nim,
+// email, jurusan, employers, positions).
+type SearchHit struct {
+	Alumni     Alumni              `json:"alumni"`
+	Score      float64             `json:"score"`
+	Highlights map[string][]string `json:"highlights"`
+	Employers  []string            `json:"employers,omitempty"`
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"Mango/app/crypt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// Secret adalah string yang disimpan terenkripsi di MongoDB (lihat paket
+// crypt). Di JSON tetap berupa string biasa. String kosong disimpan apa
+// adanya agar "sudah diisi atau belum" tetap bisa di-query.
+type Secret string
+
+func (s Secret) String() string {
+	return string(s)
+}
+
+func (s Secret) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	t, data, err := bson.MarshalValue(string(s))
+	if err != nil || s == "" {
+		return t, data, err
+	}
+	return crypt.Seal(t, data)
+}
+
+// UnmarshalBSONValue juga menerima string plaintext (data sebelum
+// migration encrypt_sensitive_fields).
+func (s *Secret) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	t, data, err := crypt.Open(t, data)
+	if err != nil {
+		return err
+	}
+	switch t {
+	case bsontype.Null, bsontype.Undefined:
+		*s = ""
+		return nil
+	}
+	var str string
+	if err := bson.UnmarshalValue(t, data, &str); err != nil {
+		return err
+	}
+	*s = Secret(str)
+	return nil
+}
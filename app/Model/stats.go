@@ -0,0 +1,37 @@
+package model
+
+// EmploymentRate adalah tingkat keterserapan kerja untuk satu kelompok
+// (angkatan, tahun lulus atau jurusan).
+type EmploymentRate struct {
+	Group    any     `json:"group"`
+	Total    int     `json:"total"`
+	Employed int     `json:"employed"`
+	Rate     float64 `json:"rate"`
+}
+
+// DistributionItem adalah jumlah untuk satu nilai (misalnya satu bidang
+// industri) beserta persentasenya dari total.
+type DistributionItem struct {
+	Label      string  `json:"label"`
+	Count      int     `json:"count"`
+	Percentage float64 `json:"percentage"`
+}
+
+// WaitingTime adalah rata-rata masa tunggu dari lulus sampai pekerjaan
+// pertama untuk satu kelompok.
+type WaitingTime struct {
+	Group         any     `json:"group"`
+	Alumni        int     `json:"alumni"`
+	AverageMonths float64 `json:"average_months"`
+}
+
+// SalaryBucket adalah jumlah pekerjaan saat ini pada satu bracket gaji
+// (rupiah per bulan). Max 0 berarti tanpa batas atas; bracket "unknown"
+// berisi pekerjaan tanpa data gaji.
+type SalaryBucket struct {
+	Label      string  `json:"label"`
+	Min        int64   `json:"min"`
+	Max        int64   `json:"max,omitempty"`
+	Count      int     `json:"count"`
+	Percentage float64 `json:"percentage"`
+}
@@ -0,0 +1,67 @@
+package model
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Deletion menandai dokumen yang dihapus sementara (ada di tempat sampah).
+// Dokumen tanpa deleted_at dianggap aktif. DeletedWith diisi jika dokumen
+// ikut terhapus bersama induknya (pekerjaan bersama alumninya) agar bisa
+// dipulihkan bersama.
+type Deletion struct {
+	DeletedAt   int64               `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy   *primitive.ObjectID `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
+	DeletedWith *primitive.ObjectID `bson:"deleted_with,omitempty" json:"deleted_with,omitempty"`
+}
+
+// IsDeleted melaporkan apakah dokumen ada di tempat sampah.
+func (d Deletion) IsDeleted() bool {
+	return d.DeletedAt != 0
+}
+
+// TrashQuery adalah parameter daftar tempat sampah.
+type TrashQuery struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+// PurgeReport adalah jumlah dokumen dan file yang dihapus permanen oleh
+// purge.
+type PurgeReport struct {
+	Alumni    int64 `json:"alumni"`
+	Pekerjaan int64 `json:"pekerjaan"`
+	Users     int64 `json:"users"`
+	Uploads   int64 `json:"uploads"`
+}
+
+// Kebijakan untuk data yang bergantung pada alumni (pekerjaan, akun user
+// yang tertaut dan file upload milik alumni atau akunnya) saat alumni
+// dihapus:
+//   - cascade: pekerjaan dan akun user ikut ke tempat sampah; upload
+//     dihapus bersama alumni saat purge
+//   - block: penghapusan ditolak (409) selama masih ada data bergantung
+//   - detach: akun user dilepas dari alumni dan tetap aktif beserta
+//     uploadnya; pekerjaan tetap ikut ke tempat sampah karena tidak
+//     bermakna tanpa pemiliknya
+const (
+	DeleteCascade = "cascade"
+	DeleteBlock   = "block"
+	DeleteDetach  = "detach"
+)
+
+// DeletePolicies adalah nilai kebijakan yang valid.
+var DeletePolicies = []string{DeleteCascade, DeleteBlock, DeleteDetach}
+
+// DeleteAlumniQuery adalah parameter penghapusan alumni.
+type DeleteAlumniQuery struct {
+	Policy string `form:"policy" binding:"omitempty,oneof=cascade block detach"`
+}
+
+// Dependents adalah jumlah data aktif yang bergantung pada satu alumni.
+type Dependents struct {
+	Pekerjaan int64 `json:"pekerjaan"`
+	Users     int64 `json:"users"`
+	Uploads   int64 `json:"uploads"`
+}
+
+// Any melaporkan apakah ada data yang bergantung.
+func (d Dependents) Any() bool {
+	return d.Pekerjaan > 0 || d.Users > 0 || d.Uploads > 0
+}
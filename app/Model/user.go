@@ -4,14 +4,176 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type User struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Username  string             `bson:"username" json:"username"`
-	Email     string             `bson:"email" json:"email"`
-	Password  string             `bson:"password" json:"password"`
-	Role      string             `bson:"role" json:"role"`
-	AlumniID  primitive.ObjectID `bson:"alumni_id,omitempty" json:"alumni_id,omitempty"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Username   string             `bson:"username" json:"username"`
+	Email      Secret             `bson:"email" json:"email"`
+	EmailIndex string             `bson:"email_bidx,omitempty" json:"-"` // blind index email, lihat crypt.EmailIndex
+	Password   string             `bson:"password" json:"-"` // hash bcrypt, lihat HashPassword
+	Role       string             `bson:"role" json:"role"`
+	AlumniID   primitive.ObjectID `bson:"alumni_id,omitempty" json:"alumni_id,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	// Bukti tautan ke AlumniID; tanpa ini AlumniID tidak dipercaya (lihat LinkedAlumni)
+	AlumniLink *AlumniLink `bson:"alumni_link,omitempty" json:"-"`
+	// Diisi saat user membuka link verifikasi atau reset password
+	EmailVerifiedAt *time.Time `bson:"email_verified_at,omitempty" json:"email_verified_at,omitempty"`
+	// Penguncian akun setelah login gagal berulang (lihat LockoutPolicy)
+	FailedLogins int        `bson:"failed_logins,omitempty" json:"-"`
+	Lockouts     int        `bson:"lockouts,omitempty" json:"-"`
+	LockedUntil  *time.Time `bson:"locked_until,omitempty" json:"-"`
+	MFA          *MFA       `bson:"mfa,omitempty" json:"-"`
+	// Akun di identity provider OIDC; user tanpa password hanya bisa login lewat SSO
+	SSO      *SSOIdentity `bson:"sso,omitempty" json:"-"`
+	Deletion `bson:",inline"`
+}
+
+// Cara user dihubungkan dengan alumni
+const (
+	AlumniLinkSSO   = "sso"   // NIM atau email terverifikasi IdP cocok dengan alumni
+	AlumniLinkAdmin = "admin" // ditautkan admin setelah memeriksa identitas
+)
+
+// AlumniLink mencatat siapa dan kapan user dihubungkan dengan alumni.
+// By adalah admin yang menautkan (kosong untuk SSO).
+type AlumniLink struct {
+	Via      string             `bson:"via"`
+	By       primitive.ObjectID `bson:"by,omitempty"`
+	LinkedAt time.Time          `bson:"linked_at"`
+}
+
+// LinkedAlumni adalah alumni milik user, atau NilObjectID jika user belum
+// terhubung lewat tautan yang diverifikasi server. Pemeriksaan
+// kepemilikan data alumni harus memakai ini, bukan AlumniID.
+func (u *User) LinkedAlumni() primitive.ObjectID {
+	if u.AlumniLink == nil {
+		return primitive.NilObjectID
+	}
+	return u.AlumniID
+}
+
+// RegisterRequest adalah data pendaftaran publik. Role, tautan alumni dan
+// status akun tidak bisa diisi client.
+type RegisterRequest struct {
+	Username string `json:"username" binding:"required,max=50"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,max=72"`
+}
+
+// AlumniLinkRequest menautkan user dengan alumni; alumni_id kosong
+// melepas tautan.
+type AlumniLinkRequest struct {
+	AlumniID string `json:"alumni_id"`
+}
+
+// SSOIdentity menghubungkan user dengan akun di identity provider OIDC
+// (pasangan iss dan sub ID token). Role user yang dibuat otomatis saat
+// login SSO pertama (Provisioned) mengikuti grup IdP setiap login; akun
+// lokal yang dihubungkan lewat email mempertahankan role-nya.
+type SSOIdentity struct {
+	Issuer      string     `bson:"issuer"`
+	Subject     string     `bson:"subject"`
+	Provisioned bool       `bson:"provisioned,omitempty"`
+	LinkedAt    time.Time  `bson:"linked_at"`
+	LastLoginAt *time.Time `bson:"last_login_at,omitempty"`
+}
+
+// MFA adalah pengaturan TOTP user. Secret disimpan terenkripsi;
+// PendingSecret menunggu dikonfirmasi dengan kode pertama. Kode pemulihan
+// disimpan sebagai hash dan dihapus setelah dipakai.
+type MFA struct {
+	Secret        Secret     `bson:"secret,omitempty"`
+	PendingSecret Secret     `bson:"pending_secret,omitempty"`
+	EnabledAt     *time.Time `bson:"enabled_at,omitempty"`
+	LastStep      int64      `bson:"last_step,omitempty"` // periode TOTP terakhir yang dipakai
+	RecoveryCodes []string   `bson:"recovery_codes,omitempty"`
+}
+
+// MFAEnabled melaporkan apakah user sudah mengaktifkan TOTP.
+func (u *User) MFAEnabled() bool {
+	return u.MFA != nil && u.MFA.EnabledAt != nil
+}
+
+// MFAStatus adalah status TOTP user yang ditampilkan ke user sendiri.
+type MFAStatus struct {
+	Enabled           bool       `json:"enabled"`
+	Required          bool       `json:"required"`
+	EnabledAt         *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesLeft int        `json:"recovery_codes_left"`
+}
+
+// MFAEnrollment adalah secret baru yang harus dimasukkan ke aplikasi
+// authenticator (langsung atau lewat QR code dari OtpauthURI).
+type MFAEnrollment struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+// MFACodeInput berisi kode TOTP 6 digit atau kode pemulihan.
+type MFACodeInput struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// MFAChallengeInput menukar challenge token dari login dengan JWT.
+type MFAChallengeInput struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// MFAChallengeEnrollInput memulai pendaftaran TOTP saat login untuk user
+// yang wajib MFA tetapi belum mendaftar.
+type MFAChallengeEnrollInput struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
+
+// LockoutPolicy mengatur penguncian akun: setiap Threshold login gagal
+// berturut-turut akun dikunci, mulai Base lalu dua kali lipat setiap
+// penguncian berikutnya sampai Max. Login berhasil mengembalikan
+// hitungan ke awal. Threshold 0 mematikan penguncian.
+type LockoutPolicy struct {
+	Threshold int
+	Base      time.Duration
+	Max       time.Duration
+}
+
+// Duration adalah lama penguncian ke-n (mulai dari 1).
+func (p LockoutPolicy) Duration(n int) time.Duration {
+	d := p.Base
+	for i := 1; i < n && d < p.Max; i++ {
+		d *= 2
+	}
+	return min(d, p.Max)
+}
+
+// Locked melaporkan apakah akun sedang terkunci pada waktu now.
+func (u *User) Locked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
+// MaxPasswordLength adalah panjang password maksimum dalam byte; bcrypt
+// mengabaikan byte setelahnya.
+const MaxPasswordLength = 72
+
+// HashPassword meng-hash password dengan bcrypt untuk disimpan di
+// User.Password.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// IsPasswordHash melaporkan apakah s sudah berupa hash bcrypt.
+func IsPasswordHash(s string) bool {
+	_, err := bcrypt.Cost([]byte(s))
+	return err == nil
+}
+
+// CheckPassword membandingkan password dengan hash yang disimpan. User
+// tanpa password (hanya SSO) selalu ditolak.
+func (u *User) CheckPassword(password string) bool {
+	return u.Password != "" && bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password)) == nil
 }
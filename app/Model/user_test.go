@@ -0,0 +1,87 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLockoutPolicyDuration(t *testing.T) {
+	p := LockoutPolicy{Threshold: 5, Base: time.Minute, Max: 10 * time.Minute}
+	tests := []struct {
+		n    int
+		want time.Duration
+	}{
+		{0, time.Minute},
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{4, 8 * time.Minute},
+		{5, 10 * time.Minute},
+		{50, 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := p.Duration(tt.n); got != tt.want {
+			t.Errorf("Duration(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+
+	if got := (LockoutPolicy{Base: time.Hour, Max: time.Minute}).Duration(1); got != time.Minute {
+		t.Errorf("Base above Max: Duration(1) = %v, want %v", got, time.Minute)
+	}
+}
+
+func TestUserLocked(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Second), now.Add(time.Second)
+	tests := []struct {
+		name  string
+		until *time.Time
+		want  bool
+	}{
+		{"never locked", nil, false},
+		{"lock expired", &past, false},
+		{"locked", &future, true},
+		{"expires now", &now, false},
+	}
+	for _, tt := range tests {
+		if got := (&User{LockedUntil: tt.until}).Locked(now); got != tt.want {
+			t.Errorf("%s: Locked = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPassword(t *testing.T) {
+	hash, err := HashPassword("rahasia123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash == "rahasia123" || !IsPasswordHash(hash) {
+		t.Fatalf("HashPassword = %q, want a bcrypt hash", hash)
+	}
+	if IsPasswordHash("rahasia123") {
+		t.Error("plaintext reported as a hash")
+	}
+
+	tests := []struct {
+		name     string
+		stored   string
+		password string
+		want     bool
+	}{
+		{"correct", hash, "rahasia123", true},
+		{"wrong", hash, "rahasia124", false},
+		{"empty input", hash, "", false},
+		{"plaintext stored", "rahasia123", "rahasia123", false},
+		{"sso only", "", "", false},
+	}
+	for _, tt := range tests {
+		if got := (&User{Password: tt.stored}).CheckPassword(tt.password); got != tt.want {
+			t.Errorf("%s: CheckPassword = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := HashPassword(strings.Repeat("a", MaxPasswordLength+1)); err == nil {
+		t.Error("password over MaxPasswordLength hashed")
+	}
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"Mango/app/fuzzy"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Jenis kosakata referensi.
+const (
+	VocabJurusan  = "jurusan"  // program studi, dengan fakultas
+	VocabIndustri = "industri" // bidang industri, kode seksi KBLI
+	VocabProvinsi = "provinsi" // kode wilayah provinsi Kemendagri
+	VocabKota     = "kota"     // kabupaten/kota, Parent = kode provinsi
+)
+
+// VocabTypes adalah semua jenis kosakata yang dikenal.
+var VocabTypes = []string{VocabJurusan, VocabIndustri, VocabProvinsi, VocabKota}
+
+// Term adalah satu entri kosakata referensi. NameKey dan AliasKeys adalah
+// bentuk ternormalisasi untuk pencocokan input.
+type Term struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Type      string             `bson:"type" json:"type"`
+	Code      string             `bson:"code" json:"code"`
+	Name      string             `bson:"name" json:"name"`
+	Parent    string             `bson:"parent,omitempty" json:"parent,omitempty"`
+	Faculty   string             `bson:"faculty,omitempty" json:"faculty,omitempty"`
+	Aliases   []string           `bson:"aliases,omitempty" json:"aliases,omitempty"`
+	NameKey   string             `bson:"name_key" json:"-"`
+	AliasKeys []string           `bson:"alias_keys,omitempty" json:"-"`
+	CreatedAt int64              `bson:"created_at" json:"created_at"`
+	UpdatedAt int64              `bson:"updated_at" json:"updated_at"`
+}
+
+// TermRequest adalah payload create/update kosakata. Faculty wajib untuk
+// jurusan dan Parent (kode provinsi) wajib untuk kota.
+type TermRequest struct {
+	Code    string   `json:"code" binding:"required,max=20"`
+	Name    string   `json:"name" binding:"required,min=2,max=200"`
+	Parent  string   `json:"parent" binding:"max=20"`
+	Faculty string   `json:"faculty" binding:"max=150"`
+	Aliases []string `json:"aliases" binding:"max=30,dive,min=2,max=150"`
+}
+
+func (r TermRequest) ToTerm(typ string) Term {
+	t := Term{
+		Type:    typ,
+		Code:    strings.ToUpper(strings.TrimSpace(r.Code)),
+		Name:    strings.TrimSpace(r.Name),
+		Parent:  strings.ToUpper(strings.TrimSpace(r.Parent)),
+		Faculty: strings.TrimSpace(r.Faculty),
+	}
+	for _, a := range r.Aliases {
+		if a = strings.TrimSpace(a); a != "" {
+			t.Aliases = append(t.Aliases, a)
+		}
+	}
+	return t
+}
+
+// SetKeys mengisi NameKey dan AliasKeys dari Name dan Aliases.
+func (t *Term) SetKeys() {
+	t.NameKey = fuzzy.Fold(t.Name)
+	t.AliasKeys = nil
+	seen := map[string]bool{t.NameKey: true}
+	for _, a := range t.Aliases {
+		if k := fuzzy.Fold(a); k != "" && !seen[k] {
+			seen[k] = true
+			t.AliasKeys = append(t.AliasKeys, k)
+		}
+	}
+}
+
+// Matches melaporkan apakah input sama dengan kode, nama atau alias term.
+func (t Term) Matches(input string) bool {
+	if strings.EqualFold(strings.TrimSpace(input), t.Code) {
+		return true
+	}
+	key := fuzzy.Fold(input)
+	if key == t.NameKey {
+		return true
+	}
+	for _, k := range t.AliasKeys {
+		if key == k {
+			return true
+		}
+	}
+	return false
+}
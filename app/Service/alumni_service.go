@@ -1,23 +1,30 @@
 package service
 
 import (
+	"Mango/app/apperror"
+	"Mango/app/audit"
 	model "Mango/app/Model"
 	"Mango/app/repository"
+	"Mango/app/validation"
+	"Mango/app/vocab"
 	"context"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type AlumniService struct {
-	repo *repository.AlumniRepository
+	repo         *repository.AlumniRepository
+	vocab        *vocab.Resolver
+	deletePolicy string
+	audit        *audit.Recorder
 }
 
-func NewAlumniService(r *repository.AlumniRepository) *AlumniService {
-	return &AlumniService{repo: r}
+// NewAlumniService membuat AlumniService. deletePolicy adalah policy
+// penghapusan jika request tidak menyebutkannya (model.DeleteCascade, ...).
+func NewAlumniService(r *repository.AlumniRepository, resolver *vocab.Resolver, deletePolicy string, recorder *audit.Recorder) *AlumniService {
+	return &AlumniService{repo: r, vocab: resolver, deletePolicy: deletePolicy, audit: recorder}
 }
 
 
@@ -26,78 +33,259 @@ func NewAlumniService(r *repository.AlumniRepository) *AlumniService {
 // @Description Mengambil semua data alumni dari database
 // @Tags Alumni
 // @Produce json
+// @Param q query string false "Cari nama, NIM atau email"
+// @Param jurusan query string false "Jurusan"
+// @Param angkatan query int false "Angkatan"
+// @Param tahun_lulus query int false "Tahun lulus"
 // @Success 200 {array} model.Alumni
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} model.Problem
+// @Failure 500 {object} model.Problem
 // @Router /alumni [get]
-
 func (s *AlumniService) GetAllAlumni(c *gin.Context) {
+	var filter model.AlumniFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	alumni, err := s.repo.GetAllAlumni(ctx)
+	alumni, err := s.repo.GetAllAlumni(ctx, filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
+	viewer := viewerOf(c)
+	for i := range alumni {
+		viewer.Alumni(&alumni[i])
+	}
+
 	c.JSON(http.StatusOK, alumni)
 }
 
+// @Summary Search alumni
+// @Description Pencarian alumni berdasarkan nama, NIM, email, jurusan serta perusahaan dan jabatan dalam riwayat pekerjaan. Hasil diurutkan berdasarkan relevansi, nama toleran salah ketik dan fragmen yang cocok dibungkus <em>
+// @Tags Alumni
+// @Produce json
+// @Param q query string true "Kata kunci, misalnya \"budi bank\""
+// @Param limit query int false "Jumlah hasil maksimal (1-100)" default(20)
+// @Success 200 {array} model.SearchHit
+// @Failure 400 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /alumni/search [get]
+func (s *AlumniService) SearchAlumni(c *gin.Context) {
+	var query model.SearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	hits, err := s.repo.Search(ctx, query.Q, query.Limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	viewer := viewerOf(c)
+	for i := range hits {
+		viewer.Hit(&hits[i])
+	}
+
+	c.JSON(http.StatusOK, hits)
+}
+
 // @Summary Get alumni by ID
-// @Description Mengambil data alumni berdasarkan ID
+// @Description Mengambil data alumni berdasarkan ID; email, telepon dan alamat disaring sesuai pengaturan privasi alumni. Dengan asOf, mengembalikan profil seperti pada waktu tersebut (untuk laporan tracer study yang harus bisa direproduksi)
 // @Tags Alumni
 // @Produce json
 // @Param id path string true "Alumni ID"
+// @Param asOf query string false "Waktu (RFC 3339, YYYY-MM-DD untuk akhir hari, atau unix detik)"
 // @Success 200 {object} model.Alumni
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
 // @Router /alumni/{id} [get]
 func (s *AlumniService) GetAlumniByID(c *gin.Context) {
-	id := c.Param("id")
-
-	objID, err := primitive.ObjectIDFromHex(id)
+	objID, err := parseObjectID("id", c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		c.Error(err)
+		return
+	}
+
+	var query model.AlumniAsOfQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(apperror.FromBinding(err))
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	if query.AsOf != "" {
+		at, err := parseTime("asOf", query.AsOf, true)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		alumni, err := s.repo.Revisions().AsOf(ctx, objID, at)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		// Versi lama disaring dengan pengaturan privasi yang berlaku sekarang
+		if current, err := s.repo.FindByID(ctx, objID); err == nil {
+			alumni.Privacy = current.Privacy
+		}
+		viewerOf(c).Alumni(alumni)
+		c.JSON(http.StatusOK, alumni)
+		return
+	}
+
 	alumni, err := s.repo.FindByID(ctx, objID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Alumni not found"})
+		c.Error(err)
 		return
 	}
 
+	viewerOf(c).Alumni(alumni)
 	c.JSON(http.StatusOK, alumni)
 }
 
+// @Summary Alumni change history
+// @Description Daftar versi profil alumni, yang terbaru lebih dulu, beserta field yang berubah dibanding versi sebelumnya
+// @Tags Alumni
+// @Produce json
+// @Param id path string true "Alumni ID"
+// @Success 200 {array} model.AlumniVersion
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Router /alumni/{id}/history [get]
+func (s *AlumniService) GetAlumniHistory(c *gin.Context) {
+	objID, err := parseObjectID("id", c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	revisions, err := s.repo.Revisions().List(ctx, objID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if len(revisions) == 0 {
+		c.Error(repository.ErrAlumniNotFound())
+		return
+	}
+
+	versions := make([]model.AlumniVersion, len(revisions))
+	for i, rev := range revisions {
+		var prev any
+		if i+1 < len(revisions) {
+			prev = revisions[i+1].Snapshot
+		}
+		versions[i] = model.AlumniVersion{
+			Version: rev.Version,
+			At:      rev.At,
+			Action:  rev.Action,
+			By:      rev.By,
+			Changes: audit.Diff(prev, rev.Snapshot),
+		}
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+// @Summary Revert alumni
+// @Description Mengembalikan profil alumni ke isi versi tertentu (admin). Hasilnya disimpan sebagai versi baru
+// @Tags Alumni
+// @Accept json
+// @Produce json
+// @Param id path string true "Alumni ID"
+// @Param data body model.RevertAlumniRequest true "Versi tujuan"
+// @Success 200 {object} model.Alumni
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Router /alumni/{id}/revert [post]
+func (s *AlumniService) RevertAlumni(c *gin.Context) {
+	objID, err := parseObjectID("id", c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req model.RevertAlumniRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	before, err := s.repo.FindByID(ctx, objID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	after, err := s.repo.Revert(ctx, objID, req.Version, currentUserID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	s.audit.Record(c, model.AuditRevert, model.EntityAlumni, objID, before, after)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Data alumni dikembalikan ke versi sebelumnya",
+		"data":    after,
+	})
+}
+
 
 // @Summary Create new alumni
 // @Description Menambahkan data alumni baru ke database
 // @Tags Alumni
 // @Accept json
 // @Produce json
-// @Param data body model.Alumni true "Data Alumni"
+// @Param data body model.AlumniRequest true "Data Alumni"
 // @Success 201 {object} model.Alumni
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
 // @Router /alumni [post]
 func (s *AlumniService) CreateAlumni(c *gin.Context) {
-	var alum model.Alumni
-	if err := c.ShouldBindJSON(&alum); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+	var req model.AlumniRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.FromBinding(err))
 		return
 	}
 
+	alum := req.ToAlumni()
+	alum.No_telp = model.Secret(validation.NormalizePhone(string(alum.No_telp)))
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if err := s.repo.Create(ctx, &alum); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create alumni"})
+	if err := s.vocab.Apply(ctx, vocab.Jurusan(&alum)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := s.repo.Create(ctx, &alum, currentUserID(c)); err != nil {
+		c.Error(err)
 		return
 	}
+	s.audit.Record(c, model.AuditCreate, model.EntityAlumni, alum.ID, nil, alum)
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Data alumni berhasil ditambahkan",
@@ -112,59 +300,98 @@ func (s *AlumniService) CreateAlumni(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Alumni ID"
-// @Param data body model.Alumni true "Data Alumni"
+// @Param data body model.AlumniRequest true "Data Alumni"
 // @Success 200 {object} model.Alumni
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
 // @Router /alumni/{id} [put]
 func (s *AlumniService) UpdateAlumni(c *gin.Context) {
-	idParam := c.Param("id")
-
-	objID, err := primitive.ObjectIDFromHex(idParam)
+	objID, err := parseObjectID("id", c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		c.Error(err)
 		return
 	}
 
-	var updatedData model.Alumni
-	if err := c.ShouldBindJSON(&updatedData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+	var req model.AlumniRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.FromBinding(err))
 		return
 	}
 
+	updatedData := req.ToAlumni()
+	updatedData.ID = objID
+	updatedData.No_telp = model.Secret(validation.NormalizePhone(string(updatedData.No_telp)))
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if err := s.repo.Update(ctx, objID, &updatedData); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update alumni"})
+	if err := s.vocab.Apply(ctx, vocab.Jurusan(&updatedData)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	before, err := s.repo.FindByID(ctx, objID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	after, err := s.repo.Update(ctx, objID, &updatedData, currentUserID(c))
+	if err != nil {
+		c.Error(err)
 		return
 	}
+	s.audit.Record(c, model.AuditUpdate, model.EntityAlumni, objID, before, after)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data alumni berhasil diperbarui",
-		"data":    updatedData,
+		"data":    after,
 	})
 }
 
 
-// 🟢 Delete alumni
+// @Summary Delete alumni
+// @Description Memindahkan alumni beserta pekerjaannya ke tempat sampah; bisa dipulihkan lewat /api/trash. Policy menentukan akun user dan upload yang tertaut: cascade ikut dihapus, block menolak dengan 409, detach melepas tautan
+// @Tags Alumni
+// @Produce json
+// @Param id path string true "Alumni ID"
+// @Param policy query string false "cascade, block atau detach (default dari ALUMNI_DELETE_POLICY)"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /alumni/{id} [delete]
 func (s *AlumniService) DeleteAlumni(c *gin.Context) {
-	id := c.Param("id")
-	objID, err := primitive.ObjectIDFromHex(id)
+	objID, err := parseObjectID("id", c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		c.Error(err)
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	var query model.DeleteAlumniQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+	if query.Policy == "" {
+		query.Policy = s.deletePolicy
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Delete pakai DeleteOne (karena repo kamu belum punya delete, bisa ditambah)
-	_, err = s.repo.Col.DeleteOne(ctx, bson.M{"_id": objID})
+	before, err := s.repo.FindByID(ctx, objID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete alumni"})
+		c.Error(err)
+		return
+	}
+	if err := s.repo.Delete(ctx, objID, currentUserID(c), query.Policy); err != nil {
+		c.Error(err)
 		return
 	}
+	s.audit.Record(c, model.AuditDelete, model.EntityAlumni, objID, before, nil)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Alumni deleted successfully"})
 }
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"Mango/app/repository"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditService menampilkan audit log untuk admin.
+type AuditService struct {
+	repo *repository.AuditRepository
+}
+
+func NewAuditService(repo *repository.AuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// @Summary List audit log
+// @Description Riwayat perubahan data (siapa, kapan, dari mana dan field apa yang berubah), yang terbaru lebih dulu (admin)
+// @Tags Audit
+// @Produce json
+// @Param entity query string false "alumni, pekerjaan, user, upload, company atau term"
+// @Param entity_id query string false "ID entitas"
+// @Param actor query string false "ID atau username pelaku"
+// @Param action query string false "create, update, delete, restore, merge, import, revert, export atau erase"
+// @Param from query string false "Sejak (RFC 3339, YYYY-MM-DD atau unix detik)"
+// @Param to query string false "Sampai (RFC 3339, YYYY-MM-DD atau unix detik; tanggal termasuk)"
+// @Param limit query int false "Jumlah maksimal (1-500)" default(100)
+// @Success 200 {array} model.AuditEntry
+// @Failure 400 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/audit [get]
+func (s *AuditService) ListAudit(c *gin.Context) {
+	var query model.AuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+
+	filter, err := auditFilter(query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	entries, err := s.repo.List(ctx, filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// auditFilter mengurai AuditQuery. Tanggal tanpa jam pada "to" dihitung
+// sampai akhir hari tersebut.
+func auditFilter(q model.AuditQuery) (model.AuditFilter, error) {
+	f := model.AuditFilter{Entity: q.Entity, Action: q.Action, Limit: q.Limit}
+	if f.Limit == 0 {
+		f.Limit = 100
+	}
+	if q.EntityID != "" {
+		f.EntityID, _ = primitive.ObjectIDFromHex(q.EntityID)
+	}
+	if id, err := primitive.ObjectIDFromHex(q.Actor); err == nil {
+		f.ActorID = id
+	} else {
+		f.Actor = q.Actor
+	}
+
+	var err error
+	if q.From != "" {
+		if f.From, err = parseTime("from", q.From, false); err != nil {
+			return f, err
+		}
+	}
+	if q.To != "" {
+		if f.To, err = parseTime("to", q.To, true); err != nil {
+			return f, err
+		}
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		return f, apperror.Validation("validation_failed", "Request validation failed", apperror.FieldError{
+			Field: "to", Code: "range", Message: "must be after from",
+		})
+	}
+	return f, nil
+}
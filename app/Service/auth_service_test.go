@@ -0,0 +1,55 @@
+package service
+
+import (
+	"Mango/app/apperror"
+	"Mango/app/validation"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRegisterValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	if err := validation.Register(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		fields map[string]string // field → code
+	}{
+		{"empty", `{}`, map[string]string{"username": "required", "email": "required", "password": "required"}},
+		{"bad email", `{"username":"budi","email":"budi","password":"rahasia123"}`, map[string]string{"email": "email"}},
+		{"long username", `{"username":"` + strings.Repeat("b", 51) + `","email":"budi@example.com","password":"rahasia123"}`, map[string]string{"username": "max"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/auth/register", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			(&AuthService{}).Register(c)
+
+			if len(c.Errors) == 0 {
+				t.Fatal("no validation error")
+			}
+			err := apperror.From(c.Errors.Last().Err)
+			if err.Kind != apperror.KindValidation {
+				t.Fatalf("error = %v, want validation", err)
+			}
+			got := map[string]string{}
+			for _, f := range err.Fields {
+				got[f.Field] = f.Code
+			}
+			if !reflect.DeepEqual(got, tt.fields) {
+				t.Errorf("fields = %v, want %v", got, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"Mango/app/apperror"
+	"Mango/app/audit"
+	"Mango/app/fuzzy"
+	model "Mango/app/Model"
+	"Mango/app/repository"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// minSuggestScore adalah skor minimum agar perusahaan disarankan.
+const minSuggestScore = 0.65
+
+type CompanyService struct {
+	repo *repository.CompanyRepository
+	jobs  *repository.PekerjaanRepository
+	audit *audit.Recorder
+}
+
+func NewCompanyService(repo *repository.CompanyRepository, jobs *repository.PekerjaanRepository, recorder *audit.Recorder) *CompanyService {
+	return &CompanyService{repo: repo, jobs: jobs, audit: recorder}
+}
+
+// @Summary List companies
+// @Description Mengambil direktori perusahaan, urut nama
+// @Tags Companies
+// @Produce json
+// @Param q query string false "Cari nama atau alias"
+// @Success 200 {array} model.Company
+// @Failure 500 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/companies [get]
+func (s *CompanyService) ListCompanies(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	companies, err := s.repo.List(ctx, c.Query("q"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, companies)
+}
+
+// @Summary Suggest companies
+// @Description Menyarankan perusahaan yang sudah ada saat user mengetik; toleran terhadap salah ketik dan bentuk badan usaha (PT, Tbk, ...)
+// @Tags Companies
+// @Produce json
+// @Param q query string true "Nama yang sedang diketik"
+// @Param limit query int false "Jumlah saran (maks 20)" default(5)
+// @Success 200 {array} model.CompanySuggestion
+// @Failure 400 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/companies/suggest [get]
+func (s *CompanyService) SuggestCompanies(c *gin.Context) {
+	query := model.CompanyKey(c.Query("q"))
+	if query == "" {
+		c.Error(apperror.Validation("validation_failed", "Request validation failed", apperror.FieldError{
+			Field: "q", Code: "required", Message: "is required",
+		}))
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if err != nil || limit < 1 || limit > 20 {
+		c.Error(apperror.Validation("validation_failed", "Request validation failed", apperror.FieldError{
+			Field: "limit", Code: "range", Message: "must be between 1 and 20",
+		}))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	companies, err := s.repo.Candidates(ctx)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	suggestions := []model.CompanySuggestion{}
+	for _, company := range companies {
+		best := model.CompanySuggestion{ID: company.ID, Name: company.Name, MatchedOn: company.Name, Score: fuzzy.Score(query, company.NameKey)}
+		for _, key := range company.AliasKeys {
+			if score := fuzzy.Score(query, key); score > best.Score {
+				best.Score = score
+				best.MatchedOn = aliasFor(company, key)
+			}
+		}
+		if best.Score >= minSuggestScore {
+			suggestions = append(suggestions, best)
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
+// @Summary Get company by ID
+// @Tags Companies
+// @Produce json
+// @Param id path string true "Company ID"
+// @Success 200 {object} model.Company
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/companies/{id} [get]
+func (s *CompanyService) GetCompany(c *gin.Context) {
+	objID, err := parseObjectID("id", c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	company, err := s.repo.FindByID(ctx, objID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, company)
+}
+
+// @Summary Create company
+// @Description Menambahkan perusahaan ke direktori (admin)
+// @Tags Companies
+// @Accept json
+// @Produce json
+// @Param data body model.CompanyRequest true "Data perusahaan"
+// @Success 201 {object} model.Company
+// @Failure 400 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/companies [post]
+func (s *CompanyService) CreateCompany(c *gin.Context) {
+	var req model.CompanyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+
+	company := req.ToCompany()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.repo.Create(ctx, &company); err != nil {
+		c.Error(err)
+		return
+	}
+	s.audit.Record(c, model.AuditCreate, model.EntityCompany, company.ID, nil, company)
+
+	c.JSON(http.StatusCreated, company)
+}
+
+// @Summary Update company
+// @Description Memperbarui data perusahaan (admin)
+// @Tags Companies
+// @Accept json
+// @Produce json
+// @Param id path string true "Company ID"
+// @Param data body model.CompanyRequest true "Data perusahaan"
+// @Success 200 {object} model.Company
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/companies/{id} [put]
+func (s *CompanyService) UpdateCompany(c *gin.Context) {
+	objID, err := parseObjectID("id", c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req model.CompanyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+
+	company := req.ToCompany()
+	company.ID = objID
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	before, err := s.repo.FindByID(ctx, objID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if err := s.repo.Update(ctx, objID, &company); err != nil {
+		c.Error(err)
+		return
+	}
+	s.audit.Record(c, model.AuditUpdate, model.EntityCompany, objID, before, company)
+
+	c.JSON(http.StatusOK, company)
+}
+
+// @Summary Delete company
+// @Description Menghapus perusahaan yang tidak dirujuk pekerjaan mana pun (admin); gunakan merge untuk duplikat
+// @Tags Companies
+// @Produce json
+// @Param id path string true "Company ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/companies/{id} [delete]
+func (s *CompanyService) DeleteCompany(c *gin.Context) {
+	objID, err := parseObjectID("id", c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	n, err := s.jobs.CountByCompany(ctx, objID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if n > 0 {
+		c.Error(apperror.Conflict("company_in_use", fmt.Sprintf("Company is referenced by %d pekerjaan; merge it into another company instead", n)))
+		return
+	}
+
+	before, err := s.repo.FindByID(ctx, objID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if err := s.repo.Delete(ctx, objID); err != nil {
+		c.Error(err)
+		return
+	}
+	s.audit.Record(c, model.AuditDelete, model.EntityCompany, objID, before, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "company deleted"})
+}
+
+// @Summary Merge duplicate companies
+// @Description Memindahkan semua pekerjaan dari perusahaan sumber ke perusahaan {id}, menyimpan nama sumber sebagai alias, lalu menghapus perusahaan sumber (admin)
+// @Tags Companies
+// @Accept json
+// @Produce json
+// @Param id path string true "Company ID tujuan"
+// @Param data body model.MergeCompaniesRequest true "Perusahaan sumber"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/companies/{id}/merge [post]
+func (s *CompanyService) MergeCompanies(c *gin.Context) {
+	targetID, err := parseObjectID("id", c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req model.MergeCompaniesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	target, err := s.repo.FindByID(ctx, targetID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var sources []model.Company
+	for i, hex := range req.SourceIDs {
+		id, _ := primitive.ObjectIDFromHex(hex)
+		if id == targetID {
+			c.Error(apperror.Validation("validation_failed", "Request validation failed", apperror.FieldError{
+				Field: fmt.Sprintf("source_ids[%d]", i), Code: "self_merge", Message: "must differ from the target company",
+			}))
+			return
+		}
+		source, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		sources = append(sources, *source)
+	}
+
+	// Pekerjaan dipindahkan lebih dulu sehingga kegagalan di tengah jalan
+	// tidak meninggalkan rujukan ke perusahaan yang sudah dihapus.
+	ids := make([]primitive.ObjectID, len(sources))
+	names := make([]string, len(sources))
+	var aliases []string
+	for i, source := range sources {
+		ids[i] = source.ID
+		names[i] = source.Name
+		aliases = append(aliases, source.Name)
+		aliases = append(aliases, source.Aliases...)
+	}
+	moved, err := s.jobs.ReassignCompany(ctx, ids, target.ID, target.Name)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	for _, source := range sources {
+		if err := s.repo.Delete(ctx, source.ID); err != nil {
+			c.Error(err)
+			return
+		}
+		s.audit.Record(c, model.AuditDelete, model.EntityCompany, source.ID, source, nil)
+	}
+	// Alias ditambahkan setelah sumber dihapus agar tidak bentrok dengan
+	// index name_key
+	if err := s.repo.AddAliases(ctx, target.ID, aliases); err != nil {
+		c.Error(err)
+		return
+	}
+	s.audit.Note(c, model.AuditMerge, model.EntityCompany, target.ID,
+		fmt.Sprintf("merged %s; %d pekerjaan moved", strings.Join(names, ", "), moved))
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "companies merged",
+		"merged":          len(sources),
+		"pekerjaan_moved": moved,
+	})
+}
+
+// aliasFor mengembalikan alias asli untuk alias key.
+func aliasFor(company model.Company, key string) string {
+	for _, a := range company.Aliases {
+		if model.CompanyKey(a) == key {
+			return a
+		}
+	}
+	return company.Name
+}
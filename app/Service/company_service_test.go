@@ -0,0 +1,157 @@
+package service
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"Mango/app/repository"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestSuggestCompanies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	directory := []model.Company{
+		{Name: "PT Telekomunikasi Indonesia Tbk", Aliases: []string{"Telkom Indonesia", "TLKM"}},
+		{Name: "PT Telkomsel"},
+		{Name: "Telkom Sigma"},
+		{Name: "PT Bank Mandiri (Persero) Tbk", Aliases: []string{"Mandiri"}},
+		{Name: "Unilever Indonesia"},
+	}
+	docs := make([]bson.D, len(directory))
+	for i := range directory {
+		directory[i].ID = primitive.NewObjectID()
+		directory[i].SetKeys()
+		raw, err := bson.Marshal(directory[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := bson.Unmarshal(raw, &docs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		q         string
+		limit     string
+		want      []string
+		matchedOn string
+	}{
+		{
+			name:      "whole word ranks above prefix",
+			q:         "telkom",
+			want:      []string{"PT Telekomunikasi Indonesia Tbk", "Telkom Sigma", "PT Telkomsel"},
+			matchedOn: "Telkom Indonesia",
+		},
+		{
+			name:      "legal form and typo ignored",
+			q:         "PT Bank Mandri",
+			want:      []string{"PT Bank Mandiri (Persero) Tbk"},
+			matchedOn: "PT Bank Mandiri (Persero) Tbk",
+		},
+		{
+			name:      "prefix while typing",
+			q:         "unile",
+			want:      []string{"Unilever Indonesia"},
+			matchedOn: "Unilever Indonesia",
+		},
+		{
+			name:      "limit",
+			q:         "telkom",
+			limit:     "1",
+			want:      []string{"PT Telekomunikasi Indonesia Tbk"},
+			matchedOn: "Telkom Indonesia",
+		},
+		{
+			name: "no match",
+			q:    "astra",
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.companies", mtest.FirstBatch, docs...))
+			s := NewCompanyService(&repository.CompanyRepository{Col: mt.Coll}, nil, nil)
+
+			query := url.Values{"q": {tt.q}}
+			if tt.limit != "" {
+				query.Set("limit", tt.limit)
+			}
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/companies/suggest?"+query.Encode(), nil)
+			s.SuggestCompanies(c)
+
+			if len(c.Errors) > 0 {
+				mt.Fatalf("SuggestCompanies: %v", c.Errors.Last().Err)
+			}
+			var got []model.CompanySuggestion
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				mt.Fatalf("decode %s: %v", w.Body.String(), err)
+			}
+			names := make([]string, len(got))
+			for i, s := range got {
+				names[i] = s.Name
+			}
+			if len(names) != len(tt.want) {
+				mt.Fatalf("suggestions = %q, want %q", names, tt.want)
+			}
+			for i := range names {
+				if names[i] != tt.want[i] {
+					mt.Fatalf("suggestions = %q, want %q", names, tt.want)
+				}
+			}
+			if len(got) > 0 {
+				if got[0].MatchedOn != tt.matchedOn {
+					mt.Errorf("matched_on = %q, want %q", got[0].MatchedOn, tt.matchedOn)
+				}
+				if got[0].Score < minSuggestScore || got[0].Score > 1 {
+					mt.Errorf("score = %v, want in [%v, 1]", got[0].Score, minSuggestScore)
+				}
+			}
+		})
+	}
+}
+
+func TestSuggestCompaniesValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := NewCompanyService(nil, nil, nil)
+
+	tests := []struct {
+		name  string
+		query string
+		field string
+	}{
+		{"missing q", "", "q"},
+		{"only legal form", "q=PT+Tbk", "q"},
+		{"limit not a number", "q=telkom&limit=x", "limit"},
+		{"limit too small", "q=telkom&limit=0", "limit"},
+		{"limit too large", "q=telkom&limit=21", "limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/companies/suggest?"+tt.query, nil)
+			s.SuggestCompanies(c)
+
+			if len(c.Errors) == 0 {
+				t.Fatal("no error, want validation error")
+			}
+			err := apperror.From(c.Errors.Last().Err)
+			if err.Status() != http.StatusBadRequest || len(err.Fields) != 1 || err.Fields[0].Field != tt.field {
+				t.Errorf("error = %d %v, want 400 on %s", err.Status(), err.Fields, tt.field)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"Mango/app/apperror"
+	"Mango/app/exporter"
+	model "Mango/app/Model"
+	"Mango/app/privacy"
+	"Mango/app/repository"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ExportService struct {
+	alumniRepo    *repository.AlumniRepository
+	pekerjaanRepo *repository.PekerjaanRepository
+}
+
+func NewExportService(alumniRepo *repository.AlumniRepository, pekerjaanRepo *repository.PekerjaanRepository) *ExportService {
+	return &ExportService{alumniRepo: alumniRepo, pekerjaanRepo: pekerjaanRepo}
+}
+
+// @Summary Export alumni
+// @Description Export data alumni (filter sama dengan GET /api/alumni) sebagai CSV, XLSX atau NDJSON
+// @Tags Export
+// @Produce text/csv
+// @Param format query string false "csv, xlsx atau ndjson" default(csv)
+// @Param columns query string false "Daftar kolom dipisah koma, contoh nim,nama,email"
+// @Param audience query string false "Terapkan pengaturan privasi alumni untuk pembaca public atau alumni (default: peran peminta)"
+// @Param q query string false "Cari nama, NIM atau email"
+// @Param jurusan query string false "Jurusan"
+// @Param angkatan query int false "Angkatan"
+// @Param tahun_lulus query int false "Tahun lulus"
+// @Success 200 {file} file
+// @Failure 400 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/export/alumni [get]
+func (s *ExportService) ExportAlumni(c *gin.Context) {
+	var filter model.AlumniFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+	s.export(c, exporter.Alumni, func(ctx context.Context) (*mongo.Cursor, error) {
+		return s.alumniRepo.Cursor(ctx, filter)
+	})
+}
+
+// @Summary Export pekerjaan
+// @Description Export data pekerjaan (filter sama dengan GET /api/pekerjaan) sebagai CSV, XLSX atau NDJSON
+// @Tags Export
+// @Produce text/csv
+// @Param format query string false "csv, xlsx atau ndjson" default(csv)
+// @Param columns query string false "Daftar kolom dipisah koma"
+// @Param alumni_id query string false "Alumni ID"
+// @Param perusahaan query string false "Cari nama perusahaan"
+// @Param bidang_industri query string false "Bidang industri"
+// @Param lokasi_kerja query string false "Lokasi kerja"
+// @Success 200 {file} file
+// @Failure 400 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/export/pekerjaan [get]
+func (s *ExportService) ExportPekerjaan(c *gin.Context) {
+	var filter model.PekerjaanFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+	s.export(c, exporter.Pekerjaan, func(ctx context.Context) (*mongo.Cursor, error) {
+		return s.pekerjaanRepo.Cursor(ctx, filter)
+	})
+}
+
+// @Summary Export alumni with current job
+// @Description Export alumni beserta pekerjaan saat ini (filter sama dengan GET /api/alumni)
+// @Tags Export
+// @Produce text/csv
+// @Param format query string false "csv, xlsx atau ndjson" default(csv)
+// @Param columns query string false "Daftar kolom dipisah koma"
+// @Param audience query string false "Terapkan pengaturan privasi alumni untuk pembaca public atau alumni (default: peran peminta)"
+// @Param q query string false "Cari nama, NIM atau email"
+// @Param jurusan query string false "Jurusan"
+// @Param angkatan query int false "Angkatan"
+// @Param tahun_lulus query int false "Tahun lulus"
+// @Success 200 {file} file
+// @Failure 400 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/export/alumni-pekerjaan [get]
+func (s *ExportService) ExportAlumniPekerjaan(c *gin.Context) {
+	var filter model.AlumniFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+	s.export(c, exporter.AlumniCurrentJob, func(ctx context.Context) (*mongo.Cursor, error) {
+		return s.alumniRepo.CursorWithCurrentJob(ctx, filter)
+	})
+}
+
+// export memvalidasi format dan kolom lalu men-stream cursor ke response.
+// Setelah header terkirim, error hanya bisa dicatat di log.
+func (s *ExportService) export(c *gin.Context, dataset exporter.Dataset, open func(context.Context) (*mongo.Cursor, error)) {
+	format, err := exporter.ParseFormat(c.Query("format"))
+	if err != nil {
+		c.Error(apperror.Validation("invalid_format", err.Error(), apperror.FieldError{Field: "format", Code: "oneof", Message: "must be one of [csv xlsx ndjson]"}))
+		return
+	}
+
+	cols, err := dataset.Select(c.Query("columns"))
+	if err != nil {
+		c.Error(apperror.Validation("invalid_columns", err.Error(), apperror.FieldError{
+			Field:   "columns",
+			Code:    "oneof",
+			Message: "must be a comma separated subset of [" + strings.Join(dataset.ColumnKeys(), " ") + "]",
+		}))
+		return
+	}
+
+	// Field kontak disaring seperti di API, untuk peminta atau audience
+	// yang akan menerima file
+	var project func(bson.M)
+	if dataset.Contacts {
+		viewer := viewerOf(c)
+		switch audience := c.Query("audience"); audience {
+		case "":
+		case model.VisibilityPublic, model.VisibilityAlumni:
+			viewer = privacy.Audience(audience)
+		default:
+			c.Error(apperror.Validation("validation_failed", "Request validation failed", apperror.FieldError{
+				Field: "audience", Code: "oneof", Message: "must be one of [public alumni]",
+			}))
+			return
+		}
+		project = viewer.Doc
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Minute)
+	defer cancel()
+
+	cursor, err := open(ctx)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", dataset.Name, time.Now().Format("20060102"), format.Extension())
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(200)
+
+	n, err := exporter.Write(ctx, c.Writer, format, cols, cursor, project, c.Writer.Flush)
+	if err != nil {
+		log.Printf("❌ export %s aborted after %d rows: %v", dataset.Name, n, err)
+		c.Abort()
+	}
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"Mango/app/apperror"
+	"Mango/app/audit"
 	model "Mango/app/Model"
 	"Mango/app/repository"
 	"context"
@@ -16,11 +18,12 @@ import (
 )
 
 type FileService struct {
-	Repo *repository.Filerepository
+	Repo  *repository.Filerepository
+	Audit *audit.Recorder
 }
 
-func NewFileservice(repo *repository.Filerepository) *FileService {
-	return &FileService{Repo: repo}
+func NewFileservice(repo *repository.Filerepository, recorder *audit.Recorder) *FileService {
+	return &FileService{Repo: repo, Audit: recorder}
 }
 
 // ========================= UPLOAD FOTO =========================
@@ -32,15 +35,15 @@ func NewFileservice(repo *repository.Filerepository) *FileService {
 // @Produce json
 // @Param file formData file true "Photo file"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]interface{}
-// @Failure 401 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 500 {object} model.Problem
 // @Security BearerAuth
 // @Router /uploads/photo [post]
 func (s *FileService) UploadPhoto(c *gin.Context) {
 	userVal, exists := c.Get("user")
 	if !exists {
-		c.JSON(401, gin.H{"error": "User not found in context"})
+		c.Error(apperror.Unauthorized("unauthorized", "User not found in context"))
 		return
 	}
 
@@ -55,7 +58,7 @@ func (s *FileService) UploadPhoto(c *gin.Context) {
 		role = fmt.Sprintf("%v", uMap["role"])
 		targetUserID = fmt.Sprintf("%v", uMap["id"])
 	} else {
-		c.JSON(500, gin.H{"error": "Invalid user type in context"})
+		c.Error(apperror.Internal(fmt.Errorf("invalid user type in context: %T", userVal)))
 		return
 	}
 
@@ -68,27 +71,30 @@ func (s *FileService) UploadPhoto(c *gin.Context) {
 
 	file, err := c.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "file not found"})
+		c.Error(apperror.Validation("file_missing", "file not found", apperror.FieldError{Field: "file", Code: "required", Message: "is required"}))
 		return
 	}
 
 	// Validasi format
 	ext := filepath.Ext(file.Filename)
 	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "only jpg, jpeg, png allowed"})
+		c.Error(apperror.Validation("file_type_not_allowed", "only jpg, jpeg, png allowed", apperror.FieldError{Field: "file", Code: "extension", Message: "must be jpg, jpeg or png"}))
 		return
 	}
 
 	// Validasi ukuran file (max 1MB)
 	if file.Size > 1*1024*1024 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "file too large (max 1MB)"})
+		c.Error(apperror.Validation("file_too_large", "file too large (max 1MB)", apperror.FieldError{Field: "file", Code: "max_size", Message: "must be at most 1MB"}))
 		return
 	}
 
 	saveDir := "uploads/photos"
 	os.MkdirAll(saveDir, os.ModePerm)
 	filePath := fmt.Sprintf("%s/%s_%s", saveDir, targetUserID, file.Filename)
-	c.SaveUploadedFile(file, filePath)
+	if err := c.SaveUploadedFile(file, filePath); err != nil {
+		c.Error(apperror.Internal(err))
+		return
+	}
 
 	objID, _ := primitive.ObjectIDFromHex(targetUserID)
 	upload := model.Files{
@@ -102,9 +108,10 @@ func (s *FileService) UploadPhoto(c *gin.Context) {
 	}
 
 	if err := s.Repo.Save(context.Background(), &upload); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save photo metadata"})
+		c.Error(err)
 		return
 	}
+	s.Audit.Record(c, model.AuditCreate, model.EntityUpload, upload.ID, nil, upload)
 
 	c.JSON(http.StatusOK, gin.H{"message": "photo uploaded successfully", "data": upload})
 }
@@ -118,16 +125,16 @@ func (s *FileService) UploadPhoto(c *gin.Context) {
 // @Produce json
 // @Param file formData file true "Certificate file"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]interface{}
-// @Failure 401 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 500 {object} model.Problem
 // @Security BearerAuth
 // @Router /uploads/certificate [post]
 func (s *FileService) UploadCertificate(c *gin.Context) {
 
 	userVal, exists := c.Get("user")
 	if !exists {
-		c.JSON(401, gin.H{"error": "User not found in context"})
+		c.Error(apperror.Unauthorized("unauthorized", "User not found in context"))
 		return
 	}
 
@@ -142,7 +149,7 @@ func (s *FileService) UploadCertificate(c *gin.Context) {
 		role = fmt.Sprintf("%v", uMap["role"])
 		targetUserID = fmt.Sprintf("%v", uMap["id"])
 	} else {
-		c.JSON(500, gin.H{"error": "Invalid user type in context"})
+		c.Error(apperror.Internal(fmt.Errorf("invalid user type in context: %T", userVal)))
 		return
 	}
 
@@ -155,25 +162,28 @@ func (s *FileService) UploadCertificate(c *gin.Context) {
 
 	file, err := c.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "file not found"})
+		c.Error(apperror.Validation("file_missing", "file not found", apperror.FieldError{Field: "file", Code: "required", Message: "is required"}))
 		return
 	}
 
 	ext := filepath.Ext(file.Filename)
 	if ext != ".pdf" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "only pdf allowed"})
+		c.Error(apperror.Validation("file_type_not_allowed", "only pdf allowed", apperror.FieldError{Field: "file", Code: "extension", Message: "must be pdf"}))
 		return
 	}
 
 	if file.Size > 2*1024*1024 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "file too large (max 2MB)"})
+		c.Error(apperror.Validation("file_too_large", "file too large (max 2MB)", apperror.FieldError{Field: "file", Code: "max_size", Message: "must be at most 2MB"}))
 		return
 	}
 
 	saveDir := "uploads/certificates"
 	os.MkdirAll(saveDir, os.ModePerm)
 	filePath := fmt.Sprintf("%s/%s_%s", saveDir, targetUserID, file.Filename)
-	c.SaveUploadedFile(file, filePath)
+	if err := c.SaveUploadedFile(file, filePath); err != nil {
+		c.Error(apperror.Internal(err))
+		return
+	}
 
 	objID, _ := primitive.ObjectIDFromHex(targetUserID)
 	upload := model.Files{ 
@@ -187,9 +197,10 @@ func (s *FileService) UploadCertificate(c *gin.Context) {
 	}
 
 	if err := s.Repo.Save(context.Background(), &upload); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save certificate metadata"})
+		c.Error(err)
 		return
 	}
+	s.Audit.Record(c, model.AuditCreate, model.EntityUpload, upload.ID, nil, upload)
 
 	c.JSON(http.StatusOK, gin.H{"message": "certificate uploaded successfully", "data": upload})
 }
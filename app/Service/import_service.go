@@ -0,0 +1,107 @@
+package service
+
+import (
+	"Mango/app/apperror"
+	"Mango/app/audit"
+	"Mango/app/importer"
+	model "Mango/app/Model"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxImportSize = 10 * 1024 * 1024
+
+type ImportService struct {
+	importer *importer.AlumniImporter
+	audit    *audit.Recorder
+}
+
+func NewImportService(im *importer.AlumniImporter, recorder *audit.Recorder) *ImportService {
+	return &ImportService{importer: im, audit: recorder}
+}
+
+// @Summary Import alumni from CSV/XLSX
+// @Description Impor daftar wisudawan (CSV atau XLSX) dengan upsert berdasarkan NIM. Gunakan dry_run=true untuk melihat hasil tanpa menyimpan.
+// @Tags Alumni
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "File CSV atau XLSX"
+// @Param mapping formData string false "JSON pemetaan kolom ke field, contoh {\"Nama Lengkap\":\"nama\"}"
+// @Param dry_run query bool false "Hanya validasi, tidak menyimpan"
+// @Success 200 {object} importer.Report
+// @Failure 400 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/alumni/import [post]
+func (s *ImportService) ImportAlumni(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.Error(apperror.Validation("file_missing", "file not found", apperror.FieldError{Field: "file", Code: "required", Message: "is required"}))
+		return
+	}
+	if file.Size > maxImportSize {
+		c.Error(apperror.Validation("file_too_large", "file too large (max 10MB)", apperror.FieldError{Field: "file", Code: "max_size", Message: "must be at most 10MB"}))
+		return
+	}
+
+	format, err := importer.FormatFromFilename(file.Filename)
+	if err != nil {
+		c.Error(apperror.Validation("file_type_not_allowed", err.Error(), apperror.FieldError{Field: "file", Code: "extension", Message: "must be csv or xlsx"}))
+		return
+	}
+
+	var mapping importer.Mapping
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			c.Error(apperror.Validation("invalid_mapping", "mapping must be a JSON object", apperror.FieldError{Field: "mapping", Code: "json", Message: "must be a JSON object of column to field"}))
+			return
+		}
+	}
+
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+	f, err := file.Open()
+	if err != nil {
+		c.Error(apperror.Internal(err))
+		return
+	}
+	defer f.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	// Setiap baris yang tersimpan dicatat sebagai create/update alumni
+	onWrite := func(before *model.Alumni, after model.Alumni) {
+		if before == nil {
+			s.audit.Record(c, model.AuditCreate, model.EntityAlumni, after.ID, nil, after)
+		} else {
+			s.audit.Record(c, model.AuditUpdate, model.EntityAlumni, after.ID, before, after)
+		}
+	}
+
+	report, err := s.importer.Import(ctx, f, format, importer.Options{DryRun: dryRun, Mapping: mapping, OnWrite: onWrite, By: currentUserID(c)})
+	if err != nil {
+		// File dan mapping yang salah sudah berupa error validasi dari
+		// importer; sisanya (MongoDB, dekripsi, timeout) kesalahan server
+		var appErr *apperror.Error
+		if !errors.As(err, &appErr) {
+			err = apperror.Internal(err)
+		}
+		c.Error(err)
+		return
+	}
+	if !dryRun {
+		s.audit.Note(c, model.AuditImport, model.EntityAlumni, primitive.NilObjectID,
+			fmt.Sprintf("%s: %d inserted, %d updated, %d failed", file.Filename, report.Inserted, report.Updated, report.Failed))
+	}
+
+	c.JSON(http.StatusOK, report)
+}
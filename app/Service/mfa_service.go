@@ -0,0 +1,446 @@
+package service
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"Mango/app/repository"
+	"Mango/app/totp"
+	"context"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MFAPolicy menentukan role yang wajib memakai TOTP dan nama issuer yang
+// tampil di aplikasi authenticator.
+type MFAPolicy struct {
+	RequiredRoles []string
+	Issuer        string
+}
+
+// Required melaporkan apakah role wajib memakai TOTP.
+func (p MFAPolicy) Required(role string) bool {
+	return slices.Contains(p.RequiredRoles, role)
+}
+
+// @Summary Two-factor status
+// @Description Status TOTP user yang sedang login
+// @Tags MFA
+// @Produce json
+// @Success 200 {object} model.MFAStatus
+// @Security BearerAuth
+// @Router /api/me/mfa [get]
+func (s *AuthService) MFAStatus(c *gin.Context) {
+	user := c.MustGet("user").(*model.User)
+	status := model.MFAStatus{Enabled: user.MFAEnabled(), Required: s.protection.MFA.Required(user.Role)}
+	if status.Enabled {
+		status.EnabledAt = user.MFA.EnabledAt
+		status.RecoveryCodesLeft = len(user.MFA.RecoveryCodes)
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// @Summary Start TOTP enrollment
+// @Description Membuat secret TOTP baru. Masukkan secret (atau QR dari otpauth_uri) ke aplikasi authenticator lalu konfirmasi dengan kode pertama.
+// @Tags MFA
+// @Produce json
+// @Success 200 {object} model.MFAEnrollment
+// @Failure 409 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/me/mfa/enroll [post]
+func (s *AuthService) EnrollMFA(c *gin.Context) {
+	s.beginEnrollment(c, c.MustGet("user").(*model.User))
+}
+
+// @Summary Confirm TOTP enrollment
+// @Description Mengaktifkan TOTP dengan kode pertama dari aplikasi authenticator. Kode pemulihan hanya ditampilkan sekali.
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Param body body model.MFACodeInput true "Kode TOTP"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/me/mfa/confirm [post]
+func (s *AuthService) ConfirmMFA(c *gin.Context) {
+	var input model.MFACodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	codes, err := s.confirmEnrollment(ctx, c, c.MustGet("user").(*model.User), input.Code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication enabled", "recovery_codes": codes})
+}
+
+// @Summary Disable TOTP
+// @Description Mematikan TOTP dengan kode TOTP atau kode pemulihan. Tidak bisa untuk role yang wajib MFA.
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Param body body model.MFACodeInput true "Kode TOTP atau kode pemulihan"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/me/mfa [delete]
+func (s *AuthService) DisableMFA(c *gin.Context) {
+	var input model.MFACodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+	user := c.MustGet("user").(*model.User)
+	if s.protection.MFA.Required(user.Role) {
+		c.Error(apperror.Forbidden("mfa_required_by_policy", "Two-factor authentication is required for your role"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := s.checkMFACode(ctx, user, input.Code); err != nil {
+		c.Error(err)
+		return
+	}
+	if err := s.repo.DisableMFA(ctx, user.ID); err != nil {
+		c.Error(err)
+		return
+	}
+	s.audit.Note(c, model.AuditUpdate, model.EntityUser, user.ID, "two-factor authentication disabled")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// @Summary Regenerate recovery codes
+// @Description Mengganti semua kode pemulihan; kode lama tidak berlaku lagi
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Param body body model.MFACodeInput true "Kode TOTP atau kode pemulihan"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/me/mfa/recovery-codes [post]
+func (s *AuthService) RegenerateRecoveryCodes(c *gin.Context) {
+	var input model.MFACodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+	user := c.MustGet("user").(*model.User)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := s.checkMFACode(ctx, user, input.Code); err != nil {
+		c.Error(err)
+		return
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if err := s.repo.SetRecoveryCodes(ctx, user.ID, hashes); err != nil {
+		c.Error(err)
+		return
+	}
+	s.audit.Note(c, model.AuditUpdate, model.EntityUser, user.ID, "recovery codes regenerated")
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// @Summary Verify login with TOTP
+// @Description Menukar challenge_token dari login dan kode TOTP (atau kode pemulihan) dengan JWT
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Param body body model.MFAChallengeInput true "Challenge token dan kode"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 429 {object} model.Problem
+// @Router /auth/mfa/verify [post]
+func (s *AuthService) VerifyMFA(c *gin.Context) {
+	var input model.MFAChallengeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := s.challengeUser(ctx, input.ChallengeToken)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if !user.MFAEnabled() {
+		c.Error(apperror.Validation("mfa_not_enabled", "Two-factor authentication is not enabled; enroll with /auth/mfa/enroll"))
+		return
+	}
+
+	recovery, err := s.checkMFACode(ctx, user, input.Code)
+	if err != nil {
+		if apperror.Is(err, apperror.KindValidation) {
+			// Kode salah dihitung sebagai login gagal
+			if ferr := s.recordFailure(ctx, c, user); ferr != nil {
+				log.Printf("⚠️  record failed mfa for %q: %v", user.Username, ferr)
+			}
+			err = apperror.Unauthorized("invalid_mfa_code", "Invalid two-factor code")
+		}
+		c.Error(err)
+		return
+	}
+
+	var extra gin.H
+	if recovery {
+		s.audit.Note(c, model.AuditUpdate, model.EntityUser, user.ID, "signed in with a recovery code")
+		extra = gin.H{"recovery_codes_left": len(user.MFA.RecoveryCodes) - 1}
+	}
+	s.completeLogin(ctx, c, user, extra)
+}
+
+// @Summary Enroll TOTP during login
+// @Description Untuk user yang wajib MFA tetapi belum mendaftar: membuat secret TOTP dengan challenge_token dari login
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Param body body model.MFAChallengeEnrollInput true "Challenge token"
+// @Success 200 {object} model.MFAEnrollment
+// @Failure 401 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Router /auth/mfa/enroll [post]
+func (s *AuthService) ChallengeEnrollMFA(c *gin.Context) {
+	var input model.MFAChallengeEnrollInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := s.challengeUser(ctx, input.ChallengeToken)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	s.beginEnrollment(c, user)
+}
+
+// @Summary Confirm TOTP enrollment during login
+// @Description Mengaktifkan TOTP dengan challenge_token dan kode pertama, lalu mengembalikan JWT beserta kode pemulihan
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Param body body model.MFAChallengeInput true "Challenge token dan kode TOTP"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Router /auth/mfa/confirm [post]
+func (s *AuthService) ChallengeConfirmMFA(c *gin.Context) {
+	var input model.MFAChallengeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := s.challengeUser(ctx, input.ChallengeToken)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	codes, err := s.confirmEnrollment(ctx, c, user, input.Code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	now := time.Now()
+	user.MFA.EnabledAt = &now
+	s.completeLogin(ctx, c, user, gin.H{"recovery_codes": codes})
+}
+
+// @Summary Reset user TOTP
+// @Description Menghapus TOTP user yang kehilangan perangkat dan kode pemulihannya (admin). User yang wajib MFA akan diminta mendaftar lagi saat login.
+// @Tags MFA
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/users/{id}/mfa/reset [post]
+func (s *AuthService) ResetUserMFA(c *gin.Context) {
+	objID, err := parseObjectID("id", c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.repo.DisableMFA(ctx, objID); err != nil {
+		c.Error(err)
+		return
+	}
+	s.audit.Note(c, model.AuditUpdate, model.EntityUser, objID, "two-factor authentication reset by admin")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication reset"})
+}
+
+// beginEnrollment membuat secret TOTP baru yang menunggu konfirmasi.
+func (s *AuthService) beginEnrollment(c *gin.Context, user *model.User) {
+	if user.MFAEnabled() {
+		c.Error(repository.ErrMFAAlreadyEnabled())
+		return
+	}
+	secret, err := totp.NewSecret()
+	if err != nil {
+		c.Error(apperror.Internal(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.repo.SetPendingMFA(ctx, user.ID, model.Secret(secret)); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, model.MFAEnrollment{
+		Secret:     secret,
+		OtpauthURI: totp.URI(s.protection.MFA.Issuer, user.Username, secret),
+	})
+}
+
+// confirmEnrollment mengaktifkan secret yang menunggu konfirmasi jika code
+// cocok dan mengembalikan kode pemulihan baru.
+func (s *AuthService) confirmEnrollment(ctx context.Context, c *gin.Context, user *model.User, code string) ([]string, error) {
+	if user.MFAEnabled() {
+		return nil, repository.ErrMFAAlreadyEnabled()
+	}
+	if user.MFA == nil || user.MFA.PendingSecret == "" {
+		return nil, apperror.Validation("mfa_not_enrolling", "Start enrollment first")
+	}
+	if err := s.takeMFAAttempt(ctx, user); err != nil {
+		return nil, err
+	}
+	step, ok := totp.Validate(string(user.MFA.PendingSecret), code, time.Now())
+	if !ok {
+		return nil, errInvalidMFACode()
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.EnableMFA(ctx, user.ID, user.MFA.PendingSecret, step, hashes); err != nil {
+		return nil, err
+	}
+	s.audit.Note(c, model.AuditUpdate, model.EntityUser, user.ID, "two-factor authentication enabled")
+	return codes, nil
+}
+
+// checkMFACode menerima kode TOTP (sekali pakai per periode) atau kode
+// pemulihan (sekali pakai). recovery melaporkan kode pemulihan dipakai.
+func (s *AuthService) checkMFACode(ctx context.Context, user *model.User, code string) (recovery bool, err error) {
+	if !user.MFAEnabled() {
+		return false, apperror.Validation("mfa_not_enabled", "Two-factor authentication is not enabled")
+	}
+	if err := s.takeMFAAttempt(ctx, user); err != nil {
+		return false, err
+	}
+
+	if step, ok := totp.Validate(string(user.MFA.Secret), code, time.Now()); ok {
+		fresh, err := s.repo.UseTOTPStep(ctx, user.ID, step)
+		if err != nil {
+			return false, err
+		}
+		if !fresh {
+			return false, errInvalidMFACode()
+		}
+		return false, nil
+	}
+
+	used, err := s.repo.UseRecoveryCode(ctx, user.ID, totp.HashRecoveryCode(code))
+	if err != nil {
+		return false, err
+	}
+	if !used {
+		return false, errInvalidMFACode()
+	}
+	return true, nil
+}
+
+// takeMFAAttempt membatasi percobaan kode per user dengan limit yang sama
+// seperti login per username.
+func (s *AuthService) takeMFAAttempt(ctx context.Context, user *model.User) error {
+	if s.protection.Store == nil {
+		return nil
+	}
+	res, err := s.protection.Store.Take(ctx, "mfa:user:"+user.ID.Hex(), s.protection.PerUser)
+	if err != nil {
+		log.Printf("⚠️  rate limit mfa: %v", err)
+		return nil
+	}
+	if !res.Allowed {
+		return apperror.TooManyRequests("rate_limited", "Too many two-factor attempts, try again later", res.RetryAfter)
+	}
+	return nil
+}
+
+// challengeUser memeriksa challenge token dan memuat user-nya. Akun yang
+// terkunci ditolak.
+func (s *AuthService) challengeUser(ctx context.Context, challenge string) (*model.User, error) {
+	id, err := parseChallenge(challenge)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if apperror.Is(err, apperror.KindNotFound) {
+			err = apperror.Unauthorized("invalid_challenge", "Challenge token is invalid or has expired")
+		}
+		return nil, err
+	}
+	if now := time.Now(); user.Locked(now) {
+		return nil, errAccountLocked(user.LockedUntil.Sub(now))
+	}
+	return user, nil
+}
+
+func newRecoveryCodes() (codes, hashes []string, err error) {
+	codes, err = totp.NewRecoveryCodes(totp.RecoveryCodeCount)
+	if err != nil {
+		return nil, nil, apperror.Internal(err)
+	}
+	for _, code := range codes {
+		hashes = append(hashes, totp.HashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+func errInvalidMFACode() *apperror.Error {
+	return apperror.Validation("invalid_mfa_code", "Invalid two-factor code", apperror.FieldError{
+		Field: "code", Code: "invalid", Message: "is not a valid or unused TOTP or recovery code",
+	})
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"Mango/app/privacy"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// parseObjectID mengubah parameter path menjadi ObjectID atau
+// mengembalikan error validasi dengan nama field.
+func parseObjectID(field, value string) (primitive.ObjectID, error) {
+	objID, err := primitive.ObjectIDFromHex(value)
+	if err != nil {
+		return primitive.NilObjectID, apperror.Validation("invalid_id", "Invalid ID format", apperror.FieldError{
+			Field:   field,
+			Code:    "objectid",
+			Message: "must be a 24 character hex ObjectID",
+		})
+	}
+	return objID, nil
+}
+
+// currentUserID mengembalikan ID user yang login (diisi AuthMiddleware),
+// atau NilObjectID jika tidak ada.
+func currentUserID(c *gin.Context) primitive.ObjectID {
+	if user, ok := c.Value("user").(*model.User); ok {
+		return user.ID
+	}
+	return primitive.NilObjectID
+}
+
+// linkedAlumni mengembalikan alumni yang tertaut ke akun yang login
+// lewat SSO atau admin, atau 404 jika akun belum tertaut.
+func linkedAlumni(c *gin.Context) (primitive.ObjectID, error) {
+	user, ok := c.Value("user").(*model.User)
+	if !ok {
+		return primitive.NilObjectID, apperror.Unauthorized("unauthorized", "User not found in context")
+	}
+	alumniID := user.LinkedAlumni()
+	if alumniID.IsZero() {
+		return primitive.NilObjectID, apperror.NotFound("no_linked_alumni", "Account is not linked to an alumni")
+	}
+	return alumniID, nil
+}
+
+// parseTime mengurai waktu dari query: RFC 3339, tanggal (YYYY-MM-DD,
+// UTC) atau unix detik. Tanggal diartikan awal hari, atau akhir hari
+// jika endOfDay.
+func parseTime(field, value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		return t, nil
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(n, 0).UTC(), nil
+	}
+	return time.Time{}, apperror.Validation("validation_failed", "Request validation failed", apperror.FieldError{
+		Field: field, Code: "datetime", Message: "must be RFC 3339, YYYY-MM-DD or unix seconds",
+	})
+}
+
+// viewerOf mengembalikan privacy.Viewer untuk user yang login.
+func viewerOf(c *gin.Context) privacy.Viewer {
+	user, _ := c.Value("user").(*model.User)
+	return privacy.ViewerOf(user)
+}
@@ -1,24 +1,33 @@
 package service
 
 import (
+	"Mango/app/apperror"
+	"Mango/app/audit"
 	model "Mango/app/Model"
 	"Mango/app/repository"
+	"Mango/app/salary"
+	"Mango/app/vocab"
 	"context"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type PekerjaanService struct {
-	Repo *repository.PekerjaanRepository
+	Repo      *repository.PekerjaanRepository
+	Companies *repository.CompanyRepository
+	Vocab     *vocab.Resolver
+	Salary    salary.Config
+	Audit     *audit.Recorder
 }
 
-func NewPekerjaanService(repo *repository.PekerjaanRepository) *PekerjaanService {
-	return &PekerjaanService{Repo: repo}
+func NewPekerjaanService(repo *repository.PekerjaanRepository, companies *repository.CompanyRepository, resolver *vocab.Resolver, salaryCfg salary.Config, recorder *audit.Recorder) *PekerjaanService {
+	return &PekerjaanService{Repo: repo, Companies: companies, Vocab: resolver, Salary: salaryCfg, Audit: recorder}
 }
 
 
@@ -27,44 +36,83 @@ func NewPekerjaanService(repo *repository.PekerjaanRepository) *PekerjaanService
 // @Tags Pekerjaan
 // @Accept json
 // @Produce json
-// @Param data body model.Pekerjaan true "Data Pekerjaan"
+// @Param data body model.CreatePekerjaanRequest true "Data Pekerjaan"
 // @Success 201 {object} model.Pekerjaan
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem "Alumni tidak ada atau di tempat sampah"
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
 // @Router /api/pekerjaan [post]
 func (s *PekerjaanService) CreatePekerjaan(c *gin.Context) {
-	alumniID := c.MustGet("alumni_id").(primitive.ObjectID)
-
-	var req struct {
-		Nama            string `json:"nama_perusahaan" binding:"required"`
-		Posisi          string `json:"posisi_jabatan" binding:"required"`
-		Bidang_Industri string `json:"bidang_industri" binding:"required"`
-		Lokasi          string `json:"lokasi_kerja" binding:"required"`
-		TahunMasuk      int    `json:"tanggal_kerja" binding:"required"`
-		TahunKeluar     int    `json:"tanggal_selesai"`
-	}
+	var req model.CreatePekerjaanRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.FromBinding(err))
 		return
 	}
 
+	alumniID := c.MustGet("alumni_id").(primitive.ObjectID)
+	if req.AlumniID != "" {
+		var err error
+		if alumniID, err = parseObjectID("alumni_id", req.AlumniID); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+
+	// Format tanggal sudah diperiksa tag `month`
+	mulai, _ := model.ParseMonth(req.Tanggal_Kerja)
 	pekerjaan := model.Pekerjaan{
 		AlumniID:        alumniID,
-		Nama_perusahaan: req.Nama,
-		Posisi_jabatan:  req.Posisi,
-		Bidang_Industri: req.Bidang_Industri,
-		Lokasi_kerja:    req.Lokasi,
-		Tanggal_Kerja:   int64(req.TahunMasuk),
-		Tanggal_selesai: int64(req.TahunKeluar),
+		Nama_perusahaan: strings.TrimSpace(req.Nama_perusahaan),
+		Posisi_jabatan:  strings.TrimSpace(req.Posisi_jabatan),
+		Bidang_Industri: strings.TrimSpace(req.Bidang_Industri),
+		Lokasi_kerja:    strings.TrimSpace(req.Lokasi_kerja),
+		Tanggal_Kerja:   mulai,
+		Status:          req.Status,
+		Description:     strings.TrimSpace(req.Description),
+	}
+	if req.Tanggal_selesai != "" {
+		selesai, _ := model.ParseMonth(req.Tanggal_selesai)
+		pekerjaan.Tanggal_selesai = &selesai
+	}
+	if err := resolvePeriod(&pekerjaan); err != nil {
+		c.Error(err)
+		return
+	}
+	if req.Gaji != nil {
+		gaji := req.Gaji.ToSalary()
+		if fields := s.Salary.Validate(gaji); len(fields) > 0 {
+			c.Error(apperror.Validation("validation_failed", "Request validation failed", fields...))
+			return
+		}
+		pekerjaan.Gaji = &gaji
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	if err := s.Vocab.Apply(ctx, vocab.Industri(&pekerjaan), vocab.Lokasi(&pekerjaan)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := s.linkCompany(ctx, &pekerjaan, req.CompanyID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if !req.AllowOverlap {
+		if err := s.checkOverlap(ctx, &pekerjaan); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+
 	if err := s.Repo.Create(ctx, &pekerjaan); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create pekerjaan"})
+		c.Error(err)
 		return
 	}
+	s.Audit.Record(c, model.AuditCreate, model.EntityPekerjaan, pekerjaan.ID, nil, pekerjaan)
 
 	c.JSON(http.StatusCreated, pekerjaan)
 }
@@ -73,53 +121,61 @@ func (s *PekerjaanService) CreatePekerjaan(c *gin.Context) {
 // @Description Mengambil semua data pekerjaan dari database table pekerjaan alumni
 // @Tags Pekerjaan
 // @Produce json
+// @Param alumni_id query string false "Alumni ID"
+// @Param perusahaan query string false "Cari nama perusahaan"
+// @Param bidang_industri query string false "Bidang industri"
+// @Param lokasi_kerja query string false "Lokasi kerja"
 // @Success 200 {array} model.Pekerjaan
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} model.Problem
+// @Failure 500 {object} model.Problem
 // @Router /api/pekerjaan [get]
 func (s *PekerjaanService) GetAllPekerjaan(c *gin.Context) {
+	var filter model.PekerjaanFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	results, err := s.Repo.GetAllPekerjaan(ctx)
+	results, err := s.Repo.GetAllPekerjaan(ctx, filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pekerjaan"})
+		c.Error(err)
 		return
 	}
 
+	for i := range results {
+		redactSalary(c, &results[i])
+	}
 	c.JSON(http.StatusOK, results)
 }
 
 
 
 
-// @Summary Get Pekerjan by alumni
-// @Description Mengambil data Pekerjaan berdasarkan alumni
+// @Summary Get my pekerjaan
+// @Description Mengambil data Pekerjaan milik alumni yang tertaut ke akun yang login
 // @Tags pekerjaan
 // @Produce json
-// @Param id path string true "pekerjaan alumni"
-// @Success 200 {object} model.Pekerjaan
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Router /api/pekerjaan/{alumni_id} [get]
+// @Success 200 {array} model.Pekerjaan
+// @Failure 401 {object} model.Problem
+// @Failure 404 {object} model.Problem "Akun belum tertaut ke alumni"
+// @Router /api/pekerjaan/me [get]
 func (s *PekerjaanService) GetPekerjaanByAlumni(c *gin.Context) {
-	// Ambil user dari context yang diset di middleware
-	userVal, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+	// Hanya tautan alumni yang diverifikasi server yang dipakai
+	alumniID, err := linkedAlumni(c)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	user := userVal.(*model.User)
-
-	// Konversi user.ID ke alumniID jika diperlukan
-	alumniID := user.ID
-
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	results, err := s.Repo.FindByAlumniID(ctx, alumniID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pekerjaan"})
+		c.Error(err)
 		return
 	}
 
@@ -131,22 +187,49 @@ func (s *PekerjaanService) GetPekerjaanByAlumni(c *gin.Context) {
 
 
 
+// @Summary Get current job of an alumni
+// @Description Mengambil pekerjaan alumni yang masih berjalan (tanpa tanggal_selesai atau belum lewat) dan paling baru dimulai
+// @Tags Pekerjaan
+// @Produce json
+// @Param id path string true "Alumni ID"
+// @Success 200 {object} model.Pekerjaan
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Router /api/alumni/{id}/current-job [get]
+func (s *PekerjaanService) GetCurrentJob(c *gin.Context) {
+	alumniID, err := parseObjectID("id", c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pekerjaan, err := s.Repo.CurrentJob(ctx, alumniID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	redactSalary(c, pekerjaan)
+	c.JSON(http.StatusOK, pekerjaan)
+}
+
 // @Summary Get Pekerjan by ID
 // @Description Mengambil data Pekerjaan berdasarkan ID
 // @Tags pekerjaan
 // @Produce json
 // @Param id path string true "pekerjaan ID"
 // @Success 200 {object} model.Pekerjaan
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
 // @Router /api/pekerjaan/{id} [get]
 func (s *PekerjaanService) GetPekerjaanByID(c *gin.Context) {
-	idParam := c.Param("id")
-
 	// Convert ID dari string ke ObjectID MongoDB
-	objID, err := primitive.ObjectIDFromHex(idParam)
+	objID, err := parseObjectID("id", c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		c.Error(err)
 		return
 	}
 
@@ -155,14 +238,11 @@ func (s *PekerjaanService) GetPekerjaanByID(c *gin.Context) {
 
 	pekerjaan, err := s.Repo.FindByID(ctx, objID)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Pekerjaan not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pekerjaan"})
+		c.Error(err)
 		return
 	}
 
+	redactSalary(c, pekerjaan)
 	c.JSON(http.StatusOK, pekerjaan)
 }
 
@@ -173,73 +253,301 @@ func (s *PekerjaanService) GetPekerjaanByID(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Pekerjaan ID"
-// @Param data body model.Pekerjaan true "Data Pekerjaan"
-// @Success 200 {object} model.Pekerjaan
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Param data body model.UpdatePekerjaanRequest true "Data Pekerjaan"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
 // @Router /api/Pekerjaan/{id} [put]
 func (s *PekerjaanService) UpdatePekerjaan(c *gin.Context) {
-	idParam := c.Param("id")
-	objID, err := primitive.ObjectIDFromHex(idParam)
+	objID, err := parseObjectID("id", c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pekerjaan id"})
+		c.Error(err)
 		return
 	}
 
-	var req struct {
-		Nama            string `json:"nama_perusahaan"`
-		Posisi          string `json:"posisi_jabatan"`
-	}
+	var req model.UpdatePekerjaanRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.FromBinding(err))
 		return
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	existing, err := s.Repo.FindByID(ctx, objID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	merged := *existing
 	update := bson.M{}
-	if req.Nama != "" {
-		update["nama_perusahaan"] = req.Nama
+	for _, f := range []struct {
+		key, value string
+		dst        *string
+	}{
+		{"nama_perusahaan", req.Nama_perusahaan, &merged.Nama_perusahaan},
+		{"posisi_jabatan", req.Posisi_jabatan, &merged.Posisi_jabatan},
+		{"bidang_industri", req.Bidang_Industri, &merged.Bidang_Industri},
+		{"lokasi_kerja", req.Lokasi_kerja, &merged.Lokasi_kerja},
+		{"deskripsi", req.Description, &merged.Description},
+	} {
+		if v := strings.TrimSpace(f.value); v != "" {
+			update[f.key] = v
+			*f.dst = v
+		}
 	}
-	if req.Posisi != "" {
-		update["posisi_jabatan"] = req.Posisi
+
+	if req.Gaji != nil {
+		gaji := req.Gaji.ToSalary()
+		if fields := s.Salary.Validate(gaji); len(fields) > 0 {
+			c.Error(apperror.Validation("validation_failed", "Request validation failed", fields...))
+			return
+		}
+		update["gaji"] = gaji
 	}
-	
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	var unset []string
+	var vocabFields []vocab.Field
+	if req.Bidang_Industri != "" {
+		vocabFields = append(vocabFields, vocab.Industri(&merged))
+	}
+	if req.Lokasi_kerja != "" {
+		vocabFields = append(vocabFields, vocab.Lokasi(&merged))
+	}
+	if len(vocabFields) > 0 {
+		if err := s.Vocab.Apply(ctx, vocabFields...); err != nil {
+			c.Error(err)
+			return
+		}
+		// Simpan nama baku dan kodenya untuk field yang dikirim
+		for _, f := range []struct{ field, input, value, codeField, code string }{
+			{"bidang_industri", req.Bidang_Industri, merged.Bidang_Industri, "kode_industri", merged.KodeIndustri},
+			{"lokasi_kerja", req.Lokasi_kerja, merged.Lokasi_kerja, "kode_lokasi", merged.KodeLokasi},
+		} {
+			if f.input == "" {
+				continue
+			}
+			update[f.field] = f.value
+			if f.code != "" {
+				update[f.codeField] = f.code
+			} else {
+				unset = append(unset, f.codeField)
+			}
+		}
+	}
+
+	if req.CompanyID != "" || req.Nama_perusahaan != "" {
+		if err := s.linkCompany(ctx, &merged, req.CompanyID); err != nil {
+			c.Error(err)
+			return
+		}
+		update["nama_perusahaan"] = merged.Nama_perusahaan
+		if merged.CompanyID.IsZero() {
+			unset = append(unset, "company_id")
+		} else {
+			update["company_id"] = merged.CompanyID
+		}
+	}
 
-	if err := s.Repo.Update(ctx, objID, update); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update pekerjaan"})
+	// Periode dan status diperiksa terhadap gabungan data lama dan baru
+	periodChanged := req.Tanggal_Kerja != "" || req.Tanggal_selesai != "" || req.Status != ""
+	if req.Tanggal_Kerja != "" {
+		merged.Tanggal_Kerja, _ = model.ParseMonth(req.Tanggal_Kerja)
+	}
+	if req.Tanggal_selesai != "" {
+		selesai, _ := model.ParseMonth(req.Tanggal_selesai)
+		merged.Tanggal_selesai = &selesai
+	} else if req.Status == model.StatusCurrent {
+		merged.Tanggal_selesai = nil
+	}
+	switch {
+	case req.Status != "":
+		merged.Status = req.Status
+	case model.IsFullTime(merged.Status):
+		// Turunkan ulang current/ended dari tanggal yang baru
+		merged.Status = ""
+	}
+
+	if len(update) == 0 && !periodChanged {
+		c.Error(apperror.Validation("empty_update", "No fields to update"))
+		return
+	}
+
+	if periodChanged {
+		if err := resolvePeriod(&merged); err != nil {
+			c.Error(err)
+			return
+		}
+		if !req.AllowOverlap {
+			if err := s.checkOverlap(ctx, &merged); err != nil {
+				c.Error(err)
+				return
+			}
+		}
+		update["tanggal_kerja"] = merged.Tanggal_Kerja
+		update["status"] = merged.Status
+		if merged.Tanggal_selesai != nil {
+			update["tanggal_selesai"] = merged.Tanggal_selesai
+		} else {
+			unset = append(unset, "tanggal_selesai")
+		}
+	}
+
+	if err := s.Repo.Update(ctx, objID, update, unset...); err != nil {
+		c.Error(err)
 		return
 	}
+	if after, err := s.Repo.FindByID(ctx, objID); err == nil {
+		s.Audit.Record(c, model.AuditUpdate, model.EntityPekerjaan, objID, existing, after)
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "pekerjaan updated"})
 }
 
 // @Summary Delete Pekerjaan
-// @Description Menghapus data Pekerjaan berdasarkan ID
+// @Description Memindahkan pekerjaan ke tempat sampah; bisa dipulihkan lewat /api/trash
 // @Tags Pekerjaan
 // @Produce json
 // @Param id path string true "Pekerjaan ID"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} model.Problem
+// @Failure 500 {object} model.Problem
 // @Router /api/Pekerjaan/{id} [delete]
 // ✅ Delete pekerjaan
 func (s *PekerjaanService) DeletePekerjaan(c *gin.Context) {
-	idParam := c.Param("id")
-	objID, err := primitive.ObjectIDFromHex(idParam)
+	objID, err := parseObjectID("id", c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pekerjaan id"})
+		c.Error(err)
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if err := s.Repo.Delete(ctx, objID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete pekerjaan"})
+	before, err := s.Repo.FindByID(ctx, objID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if err := s.Repo.Delete(ctx, objID, currentUserID(c)); err != nil {
+		c.Error(err)
 		return
 	}
+	s.Audit.Record(c, model.AuditDelete, model.EntityPekerjaan, objID, before, nil)
 
 	c.JSON(http.StatusOK, gin.H{"message": "pekerjaan deleted"})
 }
+
+// linkCompany menautkan pekerjaan ke direktori perusahaan. Dengan
+// companyID, nama_perusahaan diambil dari perusahaan tersebut; tanpa itu
+// nama_perusahaan dicocokkan ke nama atau alias perusahaan yang ada dan
+// dibiarkan tanpa tautan jika tidak ada yang cocok.
+func (s *PekerjaanService) linkCompany(ctx context.Context, p *model.Pekerjaan, companyID string) error {
+	if companyID != "" {
+		id, _ := primitive.ObjectIDFromHex(companyID)
+		company, err := s.Companies.FindByID(ctx, id)
+		if apperror.Is(err, apperror.KindNotFound) {
+			return apperror.Validation("validation_failed", "Request validation failed", apperror.FieldError{
+				Field: "company_id", Code: "not_found", Message: "does not match any company",
+			})
+		}
+		if err != nil {
+			return err
+		}
+		p.CompanyID = company.ID
+		p.Nama_perusahaan = company.Name
+		return nil
+	}
+
+	p.CompanyID = primitive.NilObjectID
+	company, err := s.Companies.FindByName(ctx, p.Nama_perusahaan)
+	switch {
+	case err == nil:
+		p.CompanyID = company.ID
+	case !apperror.Is(err, apperror.KindNotFound):
+		return err
+	}
+	return nil
+}
+
+// redactSalary menghapus data gaji dari response: gaji hanya boleh dilihat
+// admin dan alumni pemilik pekerjaan.
+func redactSalary(c *gin.Context, p *model.Pekerjaan) {
+	if user, ok := c.Value("user").(*model.User); ok && user.Role == "admin" {
+		return
+	}
+	if owner, ok := c.Value("alumni_id").(primitive.ObjectID); ok && owner == p.AlumniID {
+		return
+	}
+	p.Gaji = nil
+}
+
+// checkOverlap menolak pekerjaan penuh waktu yang periodenya tumpang
+// tindih dengan pekerjaan penuh waktu lain milik alumni yang sama.
+func (s *PekerjaanService) checkOverlap(ctx context.Context, p *model.Pekerjaan) error {
+	if !model.IsFullTime(p.Status) {
+		return nil
+	}
+	others, err := s.Repo.FindByAlumniID(ctx, p.AlumniID)
+	if err != nil {
+		return err
+	}
+	for _, other := range others {
+		if other.ID == p.ID || !model.IsFullTime(other.Status) {
+			continue
+		}
+		if periodsOverlap(*p, other) {
+			return apperror.Conflict("period_overlap", fmt.Sprintf(
+				"Period overlaps with %s at %s since %s; set allow_overlap to save anyway",
+				other.Posisi_jabatan, other.Nama_perusahaan, other.Tanggal_Kerja))
+		}
+	}
+	return nil
+}
+
+// periodsOverlap memeriksa dua periode kerja. Periode yang hanya
+// bersinggungan di bulan yang sama (selesai Maret, mulai Maret) tidak
+// dianggap tumpang tindih; tanpa tanggal_selesai berarti masih berjalan.
+func periodsOverlap(a, b model.Pekerjaan) bool {
+	return a.Tanggal_Kerja.Before(periodEnd(b)) && b.Tanggal_Kerja.Before(periodEnd(a))
+}
+
+func periodEnd(p model.Pekerjaan) time.Time {
+	if p.Tanggal_selesai == nil {
+		return time.Date(9999, time.December, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return p.Tanggal_selesai.Time
+}
+
+// resolvePeriod memeriksa tanggal dan status pekerjaan. Status kosong
+// diturunkan dari tanggal_selesai: "ended" jika sudah lewat, selain itu
+// "current".
+func resolvePeriod(p *model.Pekerjaan) error {
+	now := model.CurrentMonth()
+	end := p.Tanggal_selesai
+	finished := end != nil && end.Before(now.Time)
+
+	var fe *apperror.FieldError
+	switch {
+	case end != nil && end.Before(p.Tanggal_Kerja.Time):
+		fe = &apperror.FieldError{Field: "tanggal_selesai", Code: "gtefield", Message: "must not be earlier than tanggal_kerja"}
+	case p.Status == "":
+		p.Status = model.StatusCurrent
+		if finished {
+			p.Status = model.StatusEnded
+		}
+	case p.Status == model.StatusEnded && end == nil:
+		fe = &apperror.FieldError{Field: "tanggal_selesai", Code: "required", Message: "is required when status is ended"}
+	case p.Status == model.StatusEnded && end.After(now.Time):
+		fe = &apperror.FieldError{Field: "tanggal_selesai", Code: "future", Message: "must not be in the future when status is ended"}
+	case p.Status == model.StatusCurrent && finished:
+		fe = &apperror.FieldError{Field: "status", Code: "period_ended", Message: "must be ended because tanggal_selesai has passed"}
+	}
+	if fe != nil {
+		return apperror.Validation("validation_failed", "Request validation failed", *fe)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"Mango/app/repository"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestGetPekerjaanByAlumni(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	alumniID := primitive.NewObjectID()
+	link := &model.AlumniLink{Via: model.AlumniLinkAdmin, LinkedAt: time.Now()}
+
+	tests := []struct {
+		name   string
+		user   *model.User
+		status int
+		code   string
+	}{
+		{"no user", nil, http.StatusUnauthorized, "unauthorized"},
+		{"not linked", &model.User{ID: primitive.NewObjectID()}, http.StatusNotFound, "no_linked_alumni"},
+		{"unverified alumni_id", &model.User{ID: primitive.NewObjectID(), AlumniID: alumniID}, http.StatusNotFound, "no_linked_alumni"},
+		{"linked", &model.User{ID: primitive.NewObjectID(), AlumniID: alumniID, AlumniLink: link}, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			job := bson.D{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "alumni_id", Value: alumniID}}
+			mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.pekerjaan_alumni", mtest.FirstBatch, job))
+			s := &PekerjaanService{Repo: &repository.PekerjaanRepository{Col: mt.Coll}}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/pekerjaan/me", nil)
+			if tt.user != nil {
+				c.Set("user", tt.user)
+			}
+			s.GetPekerjaanByAlumni(c)
+
+			if tt.code != "" {
+				if len(c.Errors) == 0 {
+					mt.Fatalf("no error, want %s", tt.code)
+				}
+				err := apperror.From(c.Errors.Last().Err)
+				if err.Status() != tt.status || err.Code != tt.code {
+					mt.Errorf("error = %d %s, want %d %s", err.Status(), err.Code, tt.status, tt.code)
+				}
+				if ev := mt.GetStartedEvent(); ev != nil {
+					mt.Errorf("queried %s without a linked alumni", ev.CommandName)
+				}
+				return
+			}
+
+			if w.Code != tt.status {
+				mt.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+			ev := mt.GetStartedEvent()
+			if ev == nil || ev.CommandName != "find" {
+				mt.Fatalf("started event = %v, want find", ev)
+			}
+			got, ok := ev.Command.Lookup("filter", "alumni_id").ObjectIDOK()
+			if !ok || got != alumniID {
+				mt.Errorf("filter alumni_id = %v, want the linked alumni %v", got, alumniID)
+			}
+		})
+	}
+}
@@ -0,0 +1,442 @@
+package service
+
+import (
+	"Mango/app/apperror"
+	"Mango/app/audit"
+	model "Mango/app/Model"
+	"Mango/app/privacy"
+	"Mango/app/repository"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PrivacyService melayani hak subject data menurut UU PDP: salinan data
+// pribadi, pengaturan visibilitas field kontak, dan penghapusan data
+// dengan persetujuan admin.
+type PrivacyService struct {
+	alumni   *repository.AlumniRepository
+	users    *repository.UserRepository
+	erasures *repository.ErasureRepository
+	audit    *audit.Recorder
+}
+
+func NewPrivacyService(alumni *repository.AlumniRepository, users *repository.UserRepository, erasures *repository.ErasureRepository, recorder *audit.Recorder) *PrivacyService {
+	return &PrivacyService{alumni: alumni, users: users, erasures: erasures, audit: recorder}
+}
+
+// @Summary Export my personal data
+// @Description Mengunduh seluruh data pribadi user yang login (akun, alumni, pekerjaan, riwayat versi, upload beserta filenya) sebagai ZIP berisi JSON
+// @Tags Privacy
+// @Produce application/zip
+// @Success 200 {file} file
+// @Failure 401 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/me/data-export [get]
+func (s *PrivacyService) ExportMyData(c *gin.Context) {
+	user := c.MustGet("user").(*model.User)
+	s.export(c, model.DataSubject{UserID: user.ID, AlumniID: user.LinkedAlumni()}, model.EntityUser, user.ID.Hex())
+}
+
+// @Summary Export alumni personal data
+// @Description Mengunduh seluruh data pribadi alumni beserta akun yang tertaut sebagai ZIP (admin), misalnya untuk permintaan dari alumni tanpa akun
+// @Tags Privacy
+// @Produce application/zip
+// @Param id path string true "Alumni ID"
+// @Success 200 {file} file
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/privacy/alumni/{id}/export [get]
+func (s *PrivacyService) ExportAlumniData(c *gin.Context) {
+	objID, err := parseObjectID("id", c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	s.export(c, model.DataSubject{AlumniID: objID}, model.EntityAlumni, objID.Hex())
+}
+
+// export menyusun bundle lalu men-stream ZIP ke response. Setelah header
+// terkirim, error hanya bisa dicatat di log.
+func (s *PrivacyService) export(c *gin.Context, subject model.DataSubject, entity, name string) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Minute)
+	defer cancel()
+
+	bundle, err := privacy.Collect(ctx, s.alumni.Col.Database(), subject)
+	if err != nil {
+		c.Error(apperror.Internal(err))
+		return
+	}
+	if entity == model.EntityAlumni && bundle.Alumni == nil {
+		c.Error(repository.ErrAlumniNotFound())
+		return
+	}
+
+	id := subject.UserID
+	if entity == model.EntityAlumni {
+		id = subject.AlumniID
+	}
+	s.audit.Note(c, model.AuditExport, entity, id, "personal data export")
+
+	filename := fmt.Sprintf("data-pribadi-%s-%s.zip", name, time.Now().Format("20060102"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	if err := bundle.WriteZip(c.Writer); err != nil {
+		log.Printf("❌ personal data export %s aborted: %v", name, err)
+		c.Abort()
+	}
+}
+
+// @Summary Get my privacy settings
+// @Description Pengaturan visibilitas email, telepon dan alamat alumni yang tertaut ke akun (nilai default sudah dilengkapi)
+// @Tags Privacy
+// @Produce json
+// @Success 200 {object} model.AlumniPrivacy
+// @Failure 404 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/me/privacy [get]
+func (s *PrivacyService) GetMyPrivacy(c *gin.Context) {
+	alumniID, err := linkedAlumni(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	alum, err := s.alumni.FindByID(ctx, alumniID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alum.Privacy.Resolve())
+}
+
+// @Summary Update my privacy settings
+// @Description Mengatur siapa yang boleh melihat email, telepon dan alamat: public (semua user), alumni (sesama alumni) atau admin. Telepon tetap disamarkan kecuali show_full_phone
+// @Tags Privacy
+// @Accept json
+// @Produce json
+// @Param data body model.PrivacyRequest true "Pengaturan privasi"
+// @Success 200 {object} model.AlumniPrivacy
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/me/privacy [put]
+func (s *PrivacyService) UpdateMyPrivacy(c *gin.Context) {
+	alumniID, err := linkedAlumni(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	s.updatePrivacy(c, alumniID)
+}
+
+// @Summary Update alumni privacy settings
+// @Description Mengubah pengaturan privasi alumni atas permintaannya (admin), misalnya untuk alumni tanpa akun
+// @Tags Privacy
+// @Accept json
+// @Produce json
+// @Param id path string true "Alumni ID"
+// @Param data body model.PrivacyRequest true "Pengaturan privasi"
+// @Success 200 {object} model.AlumniPrivacy
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/privacy/alumni/{id}/settings [put]
+func (s *PrivacyService) UpdateAlumniPrivacy(c *gin.Context) {
+	objID, err := parseObjectID("id", c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	s.updatePrivacy(c, objID)
+}
+
+func (s *PrivacyService) updatePrivacy(c *gin.Context, id primitive.ObjectID) {
+	var req model.PrivacyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	before, err := s.alumni.FindByID(ctx, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var current model.AlumniPrivacy
+	if before.Privacy != nil {
+		current = *before.Privacy
+	}
+
+	after, err := s.alumni.UpdatePrivacy(ctx, id, req.Apply(current), currentUserID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	s.audit.Record(c, model.AuditUpdate, model.EntityAlumni, id, before, after)
+
+	c.JSON(http.StatusOK, after.Privacy.Resolve())
+}
+
+// @Summary Request erasure of my data
+// @Description Mengajukan penghapusan data pribadi user yang login. Data baru dihapus setelah disetujui admin; hanya boleh ada satu permintaan pending
+// @Tags Privacy
+// @Accept json
+// @Produce json
+// @Param data body model.ErasureRequestBody false "Alasan"
+// @Success 201 {object} model.ErasureRequest
+// @Failure 400 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/me/erasure-requests [post]
+func (s *PrivacyService) RequestErasure(c *gin.Context) {
+	var body model.ErasureRequestBody
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.Error(apperror.FromBinding(err))
+			return
+		}
+	}
+
+	user := c.MustGet("user").(*model.User)
+	req := model.ErasureRequest{UserID: user.ID, AlumniID: user.LinkedAlumni(), Reason: body.Reason}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.erasures.Create(ctx, &req); err != nil {
+		c.Error(err)
+		return
+	}
+	s.audit.Note(c, model.AuditCreate, model.EntityUser, user.ID, "erasure requested")
+
+	c.JSON(http.StatusCreated, req)
+}
+
+// @Summary List my erasure requests
+// @Tags Privacy
+// @Produce json
+// @Success 200 {array} model.ErasureRequest
+// @Security BearerAuth
+// @Router /api/me/erasure-requests [get]
+func (s *PrivacyService) MyErasureRequests(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	requests, err := s.erasures.List(ctx, currentUserID(c), "", 100)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+// @Summary List erasure requests
+// @Description Daftar permintaan penghapusan data, terbaru lebih dulu (admin)
+// @Tags Privacy
+// @Produce json
+// @Param status query string false "pending, rejected atau completed"
+// @Param limit query int false "Jumlah maksimal (1-500)" default(100)
+// @Success 200 {array} model.ErasureRequest
+// @Failure 400 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/privacy/erasure-requests [get]
+func (s *PrivacyService) ListErasureRequests(c *gin.Context) {
+	var query model.ErasureQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = 100
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	requests, err := s.erasures.List(ctx, primitive.NilObjectID, query.Status, query.Limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+// @Summary Approve erasure request
+// @Description Menyetujui dan langsung menjalankan penghapusan data (admin): alumni dan pekerjaannya dianonimkan agar statistik tetap utuh, akun user dan upload beserta file dihapus, riwayat versi dan nilai di audit log dibersihkan
+// @Tags Privacy
+// @Accept json
+// @Produce json
+// @Param id path string true "Erasure request ID"
+// @Param data body model.ErasureDecision false "Catatan admin"
+// @Success 200 {object} model.ErasureRequest
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/privacy/erasure-requests/{id}/approve [post]
+func (s *PrivacyService) ApproveErasure(c *gin.Context) {
+	objID, decision, err := bindDecision(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	req, err := s.erasures.FindByID(ctx, objID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if req.Status != model.ErasurePending {
+		c.Error(repository.ErrErasureNotPending())
+		return
+	}
+
+	report, err := s.alumni.Erase(ctx, model.DataSubject{UserID: req.UserID, AlumniID: req.AlumniID}, currentUserID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	req, err = s.erasures.Decide(ctx, objID, model.ErasureCompleted, currentUserID(c), decision.Note, &report)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	s.audit.Note(c, model.AuditErase, model.EntityUser, req.UserID, fmt.Sprintf(
+		"erasure %s: %d alumni and %d pekerjaan anonymised, %d users and %d uploads deleted",
+		req.ID.Hex(), report.Alumni, report.Pekerjaan, report.Users, report.Uploads))
+
+	c.JSON(http.StatusOK, req)
+}
+
+// @Summary Reject erasure request
+// @Description Menolak permintaan penghapusan data (admin), misalnya karena kewajiban penyimpanan data; alasan dicatat di note
+// @Tags Privacy
+// @Accept json
+// @Produce json
+// @Param id path string true "Erasure request ID"
+// @Param data body model.ErasureDecision false "Alasan penolakan"
+// @Success 200 {object} model.ErasureRequest
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/privacy/erasure-requests/{id}/reject [post]
+func (s *PrivacyService) RejectErasure(c *gin.Context) {
+	objID, decision, err := bindDecision(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := s.erasures.Decide(ctx, objID, model.ErasureRejected, currentUserID(c), decision.Note, nil)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	note := "erasure " + req.ID.Hex() + " rejected"
+	if decision.Note != "" {
+		note += ": " + decision.Note
+	}
+	s.audit.Note(c, model.AuditUpdate, model.EntityUser, req.UserID, note)
+
+	c.JSON(http.StatusOK, req)
+}
+
+// @Summary Link user to alumni
+// @Description Menautkan akun user dengan data alumni setelah admin memeriksa identitasnya (admin). Hanya akun yang tertaut (lewat SSO atau admin) yang bisa mengunduh, mengatur privasi dan meminta penghapusan data alumni itu. alumni_id kosong melepas tautan
+// @Tags Privacy
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param data body model.AlumniLinkRequest true "Alumni"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/privacy/users/{id}/alumni [put]
+func (s *PrivacyService) LinkUserAlumni(c *gin.Context) {
+	objID, err := parseObjectID("id", c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var req model.AlumniLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+	var alumniID primitive.ObjectID
+	if req.AlumniID != "" {
+		if alumniID, err = parseObjectID("alumni_id", req.AlumniID); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := s.users.FindByID(ctx, objID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if !alumniID.IsZero() {
+		if _, err := s.alumni.FindByID(ctx, alumniID); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+
+	link := model.AlumniLink{Via: model.AlumniLinkAdmin, By: currentUserID(c), LinkedAt: time.Now().UTC()}
+	if err := s.users.SetAlumniLink(ctx, objID, alumniID, link); err != nil {
+		c.Error(err)
+		return
+	}
+	note := "linked to alumni " + alumniID.Hex() + " by admin"
+	if alumniID.IsZero() {
+		note = "unlinked from alumni " + user.LinkedAlumni().Hex() + " by admin"
+	}
+	s.audit.Note(c, model.AuditUpdate, model.EntityUser, objID, note)
+
+	c.JSON(http.StatusOK, gin.H{"message": "User alumni link updated", "alumni_id": req.AlumniID})
+}
+
+func bindDecision(c *gin.Context) (primitive.ObjectID, model.ErasureDecision, error) {
+	var decision model.ErasureDecision
+	objID, err := parseObjectID("id", c.Param("id"))
+	if err != nil {
+		return objID, decision, err
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&decision); err != nil {
+			return objID, decision, apperror.FromBinding(err)
+		}
+	}
+	return objID, decision, nil
+}
@@ -0,0 +1,447 @@
+package service
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"Mango/app/crypt"
+	"Mango/app/oidc"
+	"Mango/app/repository"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// ssoCookie menyimpan state, nonce dan code verifier dari
+	// /auth/oidc/login sampai callback.
+	ssoCookie = "mango_sso"
+	// ssoFlowTTL adalah waktu untuk login di identity provider.
+	ssoFlowTTL = 10 * time.Minute
+	typSSOFlow = "sso_flow"
+)
+
+// SingleSignOn mengatur login lewat identity provider OIDC universitas.
+// Provider nil berarti SSO mati; login lokal dengan password tetap
+// tersedia sebagai cadangan.
+type SingleSignOn struct {
+	Provider *oidc.Provider
+	Alumni   *repository.AlumniRepository
+	// NIMClaim dan GroupsClaim adalah nama claim ID token yang berisi NIM
+	// dan grup user
+	NIMClaim    string
+	GroupsClaim string
+	// GroupRoles dicocokkan berurutan; pemetaan pertama yang grupnya
+	// dimiliki user menentukan role, jika tidak ada yang cocok DefaultRole
+	GroupRoles  []GroupRole
+	DefaultRole string
+	// FrontendURL, jika diisi, menerima hasil login di fragment URL
+	// (#token=... atau #challenge_token=...); jika kosong callback
+	// menjawab JSON seperti /auth/login.
+	FrontendURL string
+}
+
+// GroupRole memetakan satu grup di identity provider ke role.
+type GroupRole struct {
+	Group string
+	Role  string
+}
+
+// role menentukan role dari grup user di identity provider.
+func (sso SingleSignOn) role(groups []string) string {
+	for _, m := range sso.GroupRoles {
+		if slices.Contains(groups, m.Group) {
+			return m.Role
+		}
+	}
+	if sso.DefaultRole == "" {
+		return "user"
+	}
+	return sso.DefaultRole
+}
+
+// @Summary Start single sign-on
+// @Description Mengarahkan browser ke halaman login identity provider universitas (OIDC authorization code + PKCE). State, nonce dan code verifier disimpan di cookie bertanda tangan sampai callback.
+// @Tags Auth
+// @Success 302
+// @Failure 404 {object} model.Problem "SSO tidak dikonfigurasi"
+// @Router /auth/oidc/login [get]
+func (s *AuthService) SSOLogin(c *gin.Context) {
+	if s.sso.Provider == nil {
+		c.Error(errSSODisabled())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	flow, err := oidc.NewFlow()
+	if err != nil {
+		c.Error(apperror.Internal(err))
+		return
+	}
+	authURL, err := s.sso.Provider.AuthCodeURL(ctx, flow)
+	if err != nil {
+		c.Error(apperror.Internal(err))
+		return
+	}
+	sealed, err := sealFlow(flow)
+	if err != nil {
+		c.Error(apperror.Internal(err))
+		return
+	}
+	s.setFlowCookie(c, sealed, ssoFlowTTL)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// @Summary Single sign-on callback
+// @Description Dipanggil identity provider setelah login. Code ditukar dengan ID token; pada login pertama user dibuat (atau akun lokal dengan email terverifikasi yang sama dihubungkan), dicocokkan dengan alumni lewat NIM atau email, dan diberi role sesuai grup. Jawabannya sama dengan /auth/login, atau redirect ke frontend dengan hasil di fragment URL jika OIDC_FRONTEND_URL diisi.
+// @Tags Auth
+// @Produce json
+// @Param code query string false "Authorization code"
+// @Param state query string true "State dari /auth/oidc/login"
+// @Success 200 {object} map[string]interface{}
+// @Success 302
+// @Failure 401 {object} model.Problem
+// @Failure 404 {object} model.Problem "SSO tidak dikonfigurasi"
+// @Failure 409 {object} model.Problem
+// @Router /auth/oidc/callback [get]
+func (s *AuthService) SSOCallback(c *gin.Context) {
+	if s.sso.Provider == nil {
+		c.Error(errSSODisabled())
+		return
+	}
+	response, err := s.ssoCallback(c)
+	s.ssoRespond(c, response, err)
+}
+
+func (s *AuthService) ssoCallback(c *gin.Context) (gin.H, error) {
+	// 🍪 Cookie flow hanya berlaku untuk satu callback
+	sealed, _ := c.Cookie(ssoCookie)
+	s.setFlowCookie(c, "", 0)
+
+	if e := c.Query("error"); e != "" {
+		log.Printf("⚠️  SSO login rejected by identity provider: %s %s", e, c.Query("error_description"))
+		return nil, apperror.Unauthorized("sso_denied", "Single sign-on was cancelled or denied by the identity provider")
+	}
+	flow, err := openFlow(sealed)
+	state := c.Query("state")
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(flow.State)) != 1 {
+		return nil, errSSOState()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	claims, err := s.sso.Provider.Exchange(ctx, c.Query("code"), flow)
+	if err != nil {
+		log.Printf("❌ SSO login failed: %v", err)
+		return nil, apperror.Unauthorized("sso_failed", "Single sign-on failed")
+	}
+
+	user, err := s.ssoUser(ctx, c, claims)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RecordSSOLogin(ctx, user.ID); err != nil {
+		log.Printf("⚠️  record SSO login for %q: %v", user.Username, err)
+	}
+
+	// 🔐 Kebijakan TOTP tetap berlaku untuk login SSO
+	return s.signIn(ctx, user)
+}
+
+// ssoRespond mengirim hasil callback. Jika FrontendURL diisi, browser
+// diarahkan ke frontend dengan hasilnya di fragment URL; fragment tidak
+// dikirim ke server sehingga token tidak tercatat di log.
+func (s *AuthService) ssoRespond(c *gin.Context, response gin.H, err error) {
+	if s.sso.FrontendURL == "" {
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	fragment := url.Values{}
+	if err != nil {
+		appErr := apperror.From(err)
+		if appErr.Kind == apperror.KindInternal {
+			log.Printf("❌ SSO login: %v", err)
+		}
+		fragment.Set("error", appErr.Code)
+	}
+	for k, v := range response {
+		if k != "user" {
+			fragment.Set(k, fmt.Sprint(v))
+		}
+	}
+	c.Redirect(http.StatusFound, s.sso.FrontendURL+"#"+fragment.Encode())
+}
+
+// ssoUser mencari user untuk akun SSO, atau menghubungkan atau membuat
+// user pada login pertama, lalu menyinkronkan role, alumni dan status
+// verifikasi email dari claim.
+func (s *AuthService) ssoUser(ctx context.Context, c *gin.Context, claims *oidc.Claims) (*model.User, error) {
+	user, err := s.repo.FindBySSO(ctx, s.sso.Provider.Issuer(), claims.Subject)
+	if apperror.Is(err, apperror.KindNotFound) {
+		user, err = s.linkOrProvision(ctx, c, claims)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.syncSSOUser(ctx, c, user, claims); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// linkOrProvision menghubungkan akun SSO baru dengan akun lokal yang
+// emailnya sama, jika email itu sudah diverifikasi di kedua sisi; jika
+// tidak, user baru dibuat tanpa password.
+func (s *AuthService) linkOrProvision(ctx context.Context, c *gin.Context, claims *oidc.Claims) (*model.User, error) {
+	identity := model.SSOIdentity{Issuer: s.sso.Provider.Issuer(), Subject: claims.Subject, LinkedAt: time.Now().UTC()}
+
+	// 🔗 Email akun lokal yang belum diverifikasi bisa saja didaftarkan
+	// orang lain, jadi tidak dihubungkan
+	if claims.Email != "" && claims.EmailVerified {
+		user, err := s.repo.FindByEmail(ctx, claims.Email)
+		switch {
+		case err == nil && user.EmailVerifiedAt != nil:
+			if err := s.repo.LinkSSO(ctx, user.ID, identity); err != nil {
+				return nil, err
+			}
+			user.SSO = &identity
+			log.Printf("🔗 User %q linked to single sign-on", user.Username)
+			s.audit.Note(c, model.AuditUpdate, model.EntityUser, user.ID, "linked to single sign-on account")
+			return user, nil
+		case err != nil && !apperror.Is(err, apperror.KindNotFound):
+			return nil, err
+		}
+	}
+
+	// 🆕 Provisioning: user tanpa password dengan role dari grup IdP
+	now := time.Now()
+	identity.Provisioned = true
+	user := &model.User{
+		ID:        primitive.NewObjectID(),
+		Username:  ssoUsername(claims),
+		Email:     model.Secret(claims.Email),
+		Role:      s.sso.role(claims.Strings(s.sso.GroupsClaim)),
+		CreatedAt: now,
+		SSO:       &identity,
+	}
+	if claims.Email != "" && claims.EmailVerified {
+		user.EmailVerifiedAt = &now
+	}
+	base := user.Username
+	for attempt := 1; ; attempt++ {
+		err := s.repo.Create(ctx, user)
+		if err == nil {
+			break
+		}
+		switch code := apperror.From(err).Code; {
+		case code == repository.ErrSSOAlreadyLinked().Code:
+			// Login pertama yang bersamaan; user sudah dibuat oleh yang lain
+			return s.repo.FindBySSO(ctx, identity.Issuer, identity.Subject)
+		case code == repository.ErrUsernameTaken().Code && attempt < 5:
+			user.Username = base + "-" + randomSuffix()
+		case code == repository.ErrEmailTaken().Code && attempt < 5:
+			// Email dipakai akun lain yang tidak bisa dihubungkan
+			user.Email, user.EmailVerifiedAt = "", nil
+		default:
+			return nil, err
+		}
+	}
+	log.Printf("🆕 Provisioned user %q (%s) from single sign-on", user.Username, user.Role)
+	s.audit.Record(c, model.AuditCreate, model.EntityUser, user.ID, nil, user)
+	return user, nil
+}
+
+// syncSSOUser menerapkan claim terbaru ke user: role user hasil
+// provisioning mengikuti grup, user yang belum terhubung dengan alumni
+// dicocokkan lewat NIM atau email, dan email yang diverifikasi IdP
+// ditandai terverifikasi.
+func (s *AuthService) syncSSOUser(ctx context.Context, c *gin.Context, user *model.User, claims *oidc.Claims) error {
+	if user.SSO != nil && user.SSO.Provisioned {
+		if role := s.sso.role(claims.Strings(s.sso.GroupsClaim)); role != user.Role {
+			if err := s.repo.SetRole(ctx, user.ID, role); err != nil {
+				return err
+			}
+			s.audit.Note(c, model.AuditUpdate, model.EntityUser, user.ID, fmt.Sprintf("role changed from %s to %s by single sign-on groups", user.Role, role))
+			user.Role = role
+		}
+	}
+
+	if user.LinkedAlumni().IsZero() {
+		alumniID, err := s.matchAlumni(ctx, claims)
+		if err != nil {
+			return err
+		}
+		if !alumniID.IsZero() {
+			link := model.AlumniLink{Via: model.AlumniLinkSSO, LinkedAt: time.Now().UTC()}
+			linked, err := s.repo.LinkAlumni(ctx, user.ID, alumniID, link)
+			if err != nil {
+				return err
+			}
+			if linked {
+				user.AlumniID, user.AlumniLink = alumniID, &link
+				s.audit.Note(c, model.AuditUpdate, model.EntityUser, user.ID, "linked to alumni "+alumniID.Hex()+" by single sign-on")
+			}
+		}
+	}
+
+	if user.EmailVerifiedAt == nil && claims.EmailVerified && user.EmailIndex != "" && crypt.EmailIndex(claims.Email) == user.EmailIndex {
+		if err := s.repo.MarkEmailVerified(ctx, user.ID, user.EmailIndex); err != nil {
+			return err
+		}
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		s.audit.Note(c, model.AuditUpdate, model.EntityUser, user.ID, "email verified by single sign-on")
+	}
+	return nil
+}
+
+// matchAlumni mencari alumni untuk claim: lewat NIM, atau lewat email jika
+// IdP sudah memverifikasinya. Alumni yang sudah terhubung dengan user lain
+// tidak diambil alih.
+func (s *AuthService) matchAlumni(ctx context.Context, claims *oidc.Claims) (primitive.ObjectID, error) {
+	if s.sso.Alumni == nil {
+		return primitive.NilObjectID, nil
+	}
+
+	var alum *model.Alumni
+	err := error(repository.ErrAlumniNotFound())
+	if nim := claims.String(s.sso.NIMClaim); s.sso.NIMClaim != "" && nim != "" {
+		alum, err = s.sso.Alumni.FindByNIM(ctx, nim)
+	}
+	if apperror.Is(err, apperror.KindNotFound) && claims.Email != "" && claims.EmailVerified {
+		alum, err = s.sso.Alumni.FindByEmail(ctx, claims.Email)
+	}
+	if apperror.Is(err, apperror.KindNotFound) {
+		return primitive.NilObjectID, nil
+	}
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	taken, err := s.repo.AlumniLinked(ctx, alum.ID)
+	if err != nil || taken {
+		return primitive.NilObjectID, err
+	}
+	return alum.ID, nil
+}
+
+// ssoUsername membuat username dari preferred_username, bagian depan
+// email, atau subject.
+func ssoUsername(claims *oidc.Claims) string {
+	candidate := claims.PreferredUsername
+	if candidate == "" {
+		candidate, _, _ = strings.Cut(claims.Email, "@")
+	}
+	var b strings.Builder
+	for _, r := range strings.ToLower(candidate) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' || r == '_' || r == '-' {
+			b.WriteRune(r)
+		}
+	}
+	username := b.String()
+	if username == "" {
+		username = "sso-" + randomSuffix()
+	}
+	if len(username) > 32 {
+		username = username[:32]
+	}
+	return username
+}
+
+func randomSuffix() string {
+	b := make([]byte, 3)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// setFlowCookie menyimpan cookie flow selama ttl, atau menghapusnya jika
+// value kosong. Cookie hanya dikirim ke path callback dan hanya lewat
+// HTTPS jika redirect URL memakai HTTPS.
+func (s *AuthService) setFlowCookie(c *gin.Context, value string, ttl time.Duration) {
+	maxAge := int(ttl.Seconds())
+	if value == "" {
+		maxAge = -1
+	}
+	cookiePath, secure := "/", false
+	if u, err := url.Parse(s.sso.Provider.RedirectURL()); err == nil {
+		secure = u.Scheme == "https"
+		if dir := path.Dir(u.Path); strings.HasPrefix(dir, "/") {
+			cookiePath = dir
+		}
+	}
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     ssoCookie,
+		Value:    value,
+		Path:     cookiePath,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   secure,
+		// Lax: cookie tetap terkirim saat IdP mengarahkan browser kembali
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// ssoFlowKey adalah key HMAC cookie flow.
+func ssoFlowKey() ([]byte, error) {
+	return crypt.DeriveKey("sso-flow")
+}
+
+// sealFlow menandatangani flow untuk disimpan di cookie.
+func sealFlow(flow oidc.Flow) (string, error) {
+	key, err := ssoFlowKey()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"typ":      typSSOFlow,
+		"state":    flow.State,
+		"nonce":    flow.Nonce,
+		"verifier": flow.Verifier,
+		"exp":      time.Now().Add(ssoFlowTTL).Unix(),
+	})
+	return token.SignedString(key)
+}
+
+// openFlow memeriksa cookie flow dan mengembalikan isinya.
+func openFlow(s string) (oidc.Flow, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(s, claims, func(token *jwt.Token) (interface{}, error) {
+		return ssoFlowKey()
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil || claims["typ"] != typSSOFlow {
+		return oidc.Flow{}, errSSOState()
+	}
+	flow := oidc.Flow{}
+	flow.State, _ = claims["state"].(string)
+	flow.Nonce, _ = claims["nonce"].(string)
+	flow.Verifier, _ = claims["verifier"].(string)
+	return flow, nil
+}
+
+func errSSODisabled() *apperror.Error {
+	return apperror.NotFound("sso_disabled", "Single sign-on is not configured")
+}
+
+func errSSOState() *apperror.Error {
+	return apperror.Unauthorized("invalid_sso_state", "Single sign-on session is invalid or has expired, start again")
+}
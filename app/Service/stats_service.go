@@ -0,0 +1,186 @@
+package service
+
+import (
+	"Mango/app/apperror"
+	"Mango/app/cache"
+	model "Mango/app/Model"
+	"Mango/app/repository"
+	"Mango/app/salary"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatsService struct {
+	repo   *repository.StatsRepository
+	cache  *cache.TTL
+	salary salary.Config
+}
+
+// NewStatsService membuat service statistik; hasil setiap laporan
+// di-cache selama ttl (0 berarti tanpa cache). Laporan gaji memakai
+// bracket dari salaryCfg.
+func NewStatsService(repo *repository.StatsRepository, ttl time.Duration, salaryCfg salary.Config) *StatsService {
+	return &StatsService{repo: repo, cache: cache.NewTTL(ttl), salary: salaryCfg}
+}
+
+// @Summary Employment rate
+// @Description Tingkat keterserapan kerja alumni per angkatan, tahun lulus atau jurusan
+// @Tags Statistik
+// @Produce json
+// @Param by query string false "angkatan, tahun_lulus atau jurusan" default(tahun_lulus)
+// @Param jurusan query string false "Jurusan"
+// @Param angkatan query int false "Angkatan"
+// @Param tahun_lulus query int false "Tahun lulus"
+// @Success 200 {array} model.EmploymentRate
+// @Failure 400 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/stats/employment [get]
+func (s *StatsService) EmploymentRate(c *gin.Context) {
+	s.report(c, "employment", true, func(ctx context.Context, f model.AlumniFilter, by string) (any, error) {
+		return s.repo.EmploymentRate(ctx, f, by)
+	})
+}
+
+// @Summary Industry distribution
+// @Description Sebaran pekerjaan saat ini berdasarkan bidang industri
+// @Tags Statistik
+// @Produce json
+// @Param jurusan query string false "Jurusan"
+// @Param angkatan query int false "Angkatan"
+// @Param tahun_lulus query int false "Tahun lulus"
+// @Success 200 {array} model.DistributionItem
+// @Failure 400 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/stats/industri [get]
+func (s *StatsService) IndustryDistribution(c *gin.Context) {
+	s.report(c, "industri", false, func(ctx context.Context, f model.AlumniFilter, _ string) (any, error) {
+		return s.repo.CurrentJobDistribution(ctx, f, "bidang_industri")
+	})
+}
+
+// @Summary Location distribution
+// @Description Sebaran pekerjaan saat ini berdasarkan lokasi kerja
+// @Tags Statistik
+// @Produce json
+// @Param jurusan query string false "Jurusan"
+// @Param angkatan query int false "Angkatan"
+// @Param tahun_lulus query int false "Tahun lulus"
+// @Success 200 {array} model.DistributionItem
+// @Failure 400 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/stats/lokasi [get]
+func (s *StatsService) LocationDistribution(c *gin.Context) {
+	s.report(c, "lokasi", false, func(ctx context.Context, f model.AlumniFilter, _ string) (any, error) {
+		return s.repo.CurrentJobDistribution(ctx, f, "lokasi_kerja")
+	})
+}
+
+// @Summary Average waiting time
+// @Description Rata-rata masa tunggu (bulan) dari lulus sampai pekerjaan pertama
+// @Tags Statistik
+// @Produce json
+// @Param by query string false "angkatan, tahun_lulus atau jurusan" default(tahun_lulus)
+// @Param jurusan query string false "Jurusan"
+// @Param angkatan query int false "Angkatan"
+// @Param tahun_lulus query int false "Tahun lulus"
+// @Success 200 {array} model.WaitingTime
+// @Failure 400 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/stats/waiting-time [get]
+func (s *StatsService) WaitingTime(c *gin.Context) {
+	s.report(c, "waiting-time", true, func(ctx context.Context, f model.AlumniFilter, by string) (any, error) {
+		return s.repo.WaitingTime(ctx, f, by)
+	})
+}
+
+// @Summary Salary distribution
+// @Description Sebaran gaji pekerjaan saat ini per bracket tracer study (rupiah per bulan)
+// @Tags Statistik
+// @Produce json
+// @Param jurusan query string false "Jurusan"
+// @Param angkatan query int false "Angkatan"
+// @Param tahun_lulus query int false "Tahun lulus"
+// @Success 200 {array} model.SalaryBucket
+// @Failure 400 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/stats/salary [get]
+func (s *StatsService) SalaryDistribution(c *gin.Context) {
+	s.report(c, "salary", false, func(ctx context.Context, f model.AlumniFilter, _ string) (any, error) {
+		return s.repo.SalaryDistribution(ctx, f, s.salary)
+	})
+}
+
+// @Summary Top employers
+// @Description Perusahaan dengan alumni terbanyak (hanya pekerjaan yang tertaut ke direktori perusahaan)
+// @Tags Statistik
+// @Produce json
+// @Param limit query int false "Jumlah perusahaan (maks 100)" default(10)
+// @Param current query bool false "Hanya pekerjaan saat ini"
+// @Param jurusan query string false "Jurusan"
+// @Param angkatan query int false "Angkatan"
+// @Param tahun_lulus query int false "Tahun lulus"
+// @Success 200 {array} model.TopEmployer
+// @Failure 400 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/stats/top-employers [get]
+func (s *StatsService) TopEmployers(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.Error(apperror.Validation("validation_failed", "Request validation failed", apperror.FieldError{
+			Field: "limit", Code: "range", Message: "must be between 1 and 100",
+		}))
+		return
+	}
+	current := c.Query("current") == "true"
+
+	name := fmt.Sprintf("top-employers:%d:%t", limit, current)
+	s.report(c, name, false, func(ctx context.Context, f model.AlumniFilter, _ string) (any, error) {
+		return s.repo.TopEmployers(ctx, f, limit, current)
+	})
+}
+
+// report membaca filter, mengambil hasil dari cache atau menjalankan
+// aggregation, lalu menulis response.
+func (s *StatsService) report(c *gin.Context, name string, grouped bool, run func(context.Context, model.AlumniFilter, string) (any, error)) {
+	var filter model.AlumniFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.Error(apperror.FromBinding(err))
+		return
+	}
+
+	by := ""
+	if grouped {
+		by = c.DefaultQuery("by", "tahun_lulus")
+		if by != "angkatan" && by != "tahun_lulus" && by != "jurusan" {
+			c.Error(apperror.Validation("validation_failed", "Request validation failed", apperror.FieldError{
+				Field: "by", Code: "oneof", Message: "must be one of [angkatan tahun_lulus jurusan]",
+			}))
+			return
+		}
+	}
+
+	key := fmt.Sprintf("%s|%s|%+v", name, by, filter)
+	if cached, ok := s.cache.Get(key); ok {
+		c.Header("X-Cache", "HIT")
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := run(ctx, filter, by)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	s.cache.Set(key, result)
+	c.Header("X-Cache", "MISS")
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"Mango/app/crypt"
+	"Mango/app/jwtkeys"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// AccessTokenTTL adalah masa berlaku JWT hasil login. Key tanda
+	// tangan lama baru dihapus setelah token yang ditandatanganinya
+	// kedaluwarsa.
+	AccessTokenTTL = 24 * time.Hour
+	// challengeTTL adalah waktu untuk memasukkan kode TOTP setelah
+	// password benar.
+	challengeTTL = 5 * time.Minute
+	// typMFAChallenge menandai challenge token; AuthMiddleware menolak
+	// token yang punya claim typ sehingga challenge tidak bisa dipakai
+	// sebagai JWT akses.
+	typMFAChallenge = "mfa_challenge"
+)
+
+// issueAccessToken membuat JWT akses untuk user, ditandatangani dengan
+// key aktif dari jwtkeys.
+func issueAccessToken(user *model.User) (string, error) {
+	keys, err := jwtkeys.Current()
+	if err != nil {
+		return "", err
+	}
+	return keys.Sign(jwt.MapClaims{
+		"sub":  user.ID.Hex(),
+		"role": user.Role,
+		"exp":  time.Now().Add(AccessTokenTTL).Unix(),
+	})
+}
+
+// challengeKey adalah key HMAC challenge token. Key ini tidak ada di
+// JWKS sehingga aplikasi lain tidak akan menerima challenge sebagai
+// token akses.
+func challengeKey() ([]byte, error) {
+	return crypt.DeriveKey("mfa-challenge")
+}
+
+// issueChallenge membuat challenge token untuk user yang password-nya
+// sudah benar (atau sudah login lewat SSO) tetapi masih harus memasukkan
+// kode TOTP.
+func issueChallenge(user *model.User) (string, error) {
+	key, err := challengeKey()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": user.ID.Hex(),
+		"typ": typMFAChallenge,
+		"exp": time.Now().Add(challengeTTL).Unix(),
+	})
+	return token.SignedString(key)
+}
+
+// parseChallenge memeriksa challenge token dan mengembalikan ID user-nya.
+func parseChallenge(s string) (primitive.ObjectID, error) {
+	invalid := apperror.Unauthorized("invalid_challenge", "Challenge token is invalid or has expired")
+
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(s, claims, func(token *jwt.Token) (interface{}, error) {
+		return challengeKey()
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil || !token.Valid || claims["typ"] != typMFAChallenge {
+		return primitive.NilObjectID, invalid
+	}
+	sub, _ := claims["sub"].(string)
+	id, err := primitive.ObjectIDFromHex(sub)
+	if err != nil {
+		return primitive.NilObjectID, invalid
+	}
+	return id, nil
+}
+
+// @Summary JSON Web Key Set
+// @Description Public key untuk memverifikasi JWT akses (RS256/ES256, dicocokkan lewat header kid). Aplikasi lain sebaiknya meng-cache-nya dan memuat ulang saat menemukan kid baru.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} jwtkeys.JWKS
+// @Router /.well-known/jwks.json [get]
+func (s *AuthService) JWKS(c *gin.Context) {
+	keys, err := jwtkeys.Current()
+	if err != nil {
+		c.Error(apperror.Internal(err))
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, keys.JWKS())
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"Mango/app/apperror"
+	"Mango/app/audit"
+	model "Mango/app/Model"
+	"Mango/app/repository"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrashService mengelola alumni dan pekerjaan yang dihapus sementara.
+type TrashService struct {
+	alumni    *repository.AlumniRepository
+	pekerjaan *repository.PekerjaanRepository
+	audit     *audit.Recorder
+}
+
+func NewTrashService(alumni *repository.AlumniRepository, pekerjaan *repository.PekerjaanRepository, recorder *audit.Recorder) *TrashService {
+	return &TrashService{alumni: alumni, pekerjaan: pekerjaan, audit: recorder}
+}
+
+// @Summary List deleted alumni
+// @Description Daftar alumni di tempat sampah, yang terbaru dihapus lebih dulu (admin)
+// @Tags Trash
+// @Produce json
+// @Param limit query int false "Jumlah maksimal (1-500)" default(100)
+// @Success 200 {array} model.Alumni
+// @Failure 400 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/trash/alumni [get]
+func (s *TrashService) ListAlumni(c *gin.Context) {
+	limit, err := trashLimit(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	alumni, err := s.alumni.Trash(ctx, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alumni)
+}
+
+// @Summary List deleted pekerjaan
+// @Description Daftar pekerjaan di tempat sampah (admin). Pekerjaan yang terhapus bersama alumninya dipulihkan lewat alumni dan tidak ditampilkan di sini
+// @Tags Trash
+// @Produce json
+// @Param limit query int false "Jumlah maksimal (1-500)" default(100)
+// @Success 200 {array} model.Pekerjaan
+// @Failure 400 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/trash/pekerjaan [get]
+func (s *TrashService) ListPekerjaan(c *gin.Context) {
+	limit, err := trashLimit(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pekerjaan, err := s.pekerjaan.Trash(ctx, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pekerjaan)
+}
+
+// @Summary Restore alumni
+// @Description Memulihkan alumni dari tempat sampah beserta pekerjaan dan akun user yang terhapus bersamanya (admin)
+// @Tags Trash
+// @Produce json
+// @Param id path string true "Alumni ID"
+// @Success 200 {object} model.Alumni
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/trash/alumni/{id}/restore [post]
+func (s *TrashService) RestoreAlumni(c *gin.Context) {
+	objID, err := parseObjectID("id", c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	alumni, err := s.alumni.Restore(ctx, objID, currentUserID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	s.audit.Record(c, model.AuditRestore, model.EntityAlumni, objID, nil, nil)
+
+	c.JSON(http.StatusOK, alumni)
+}
+
+// @Summary Restore pekerjaan
+// @Description Memulihkan pekerjaan dari tempat sampah (admin). Alumni pemiliknya harus aktif
+// @Tags Trash
+// @Produce json
+// @Param id path string true "Pekerjaan ID"
+// @Success 200 {object} model.Pekerjaan
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/trash/pekerjaan/{id}/restore [post]
+func (s *TrashService) RestorePekerjaan(c *gin.Context) {
+	objID, err := parseObjectID("id", c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pekerjaan, err := s.pekerjaan.Restore(ctx, objID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	s.audit.Record(c, model.AuditRestore, model.EntityPekerjaan, objID, nil, nil)
+
+	c.JSON(http.StatusOK, pekerjaan)
+}
+
+// Purge menghapus permanen isi tempat sampah yang lebih lama dari
+// retention. Dipakai oleh job purge di serve dan command purge-trash.
+func (s *TrashService) Purge(ctx context.Context, retention time.Duration, dryRun bool) (model.PurgeReport, error) {
+	before := time.Now().Add(-retention).Unix()
+
+	report, err := s.alumni.Purge(ctx, before, dryRun)
+	if err != nil {
+		return report, err
+	}
+	n, err := s.pekerjaan.Purge(ctx, before, dryRun)
+	if err != nil {
+		return report, err
+	}
+	report.Pekerjaan += n
+	return report, nil
+}
+
+func trashLimit(c *gin.Context) (int, error) {
+	var query model.TrashQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		return 0, apperror.FromBinding(err)
+	}
+	if query.Limit == 0 {
+		query.Limit = 100
+	}
+	return query.Limit, nil
+}
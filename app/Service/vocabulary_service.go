@@ -0,0 +1,234 @@
+package service
+
+import (
+	"Mango/app/apperror"
+	"Mango/app/audit"
+	model "Mango/app/Model"
+	"Mango/app/repository"
+	"Mango/app/vocab"
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VocabularyService struct {
+	repo     *repository.VocabularyRepository
+	resolver *vocab.Resolver
+	audit    *audit.Recorder
+}
+
+func NewVocabularyService(repo *repository.VocabularyRepository, resolver *vocab.Resolver, recorder *audit.Recorder) *VocabularyService {
+	return &VocabularyService{repo: repo, resolver: resolver, audit: recorder}
+}
+
+// @Summary List vocabulary terms
+// @Description Daftar kosakata referensi untuk dropdown: jurusan, industri (KBLI), provinsi atau kota
+// @Tags Vocabularies
+// @Produce json
+// @Param type path string true "jurusan, industri, provinsi atau kota"
+// @Param q query string false "Cari nama, kode atau alias"
+// @Param parent query string false "Kode provinsi (khusus kota)"
+// @Success 200 {array} model.Term
+// @Failure 400 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/vocabularies/{type} [get]
+func (s *VocabularyService) ListTerms(c *gin.Context) {
+	typ, err := vocabType(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	terms, err := s.repo.List(ctx, typ, strings.ToUpper(c.Query("parent")), c.Query("q"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, terms)
+}
+
+// @Summary Create vocabulary term
+// @Description Menambah term kosakata (admin). Jurusan wajib punya faculty; kota wajib punya parent berupa kode provinsi
+// @Tags Vocabularies
+// @Accept json
+// @Produce json
+// @Param type path string true "jurusan, industri, provinsi atau kota"
+// @Param data body model.TermRequest true "Data term"
+// @Success 201 {object} model.Term
+// @Failure 400 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/vocabularies/{type} [post]
+func (s *VocabularyService) CreateTerm(c *gin.Context) {
+	term, err := s.bindTerm(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.checkTerm(ctx, &term); err != nil {
+		c.Error(err)
+		return
+	}
+	if err := s.repo.Create(ctx, &term); err != nil {
+		c.Error(err)
+		return
+	}
+	s.resolver.Invalidate()
+	s.audit.Record(c, model.AuditCreate, model.EntityTerm, term.ID, nil, term)
+
+	c.JSON(http.StatusCreated, term)
+}
+
+// @Summary Update vocabulary term
+// @Description Memperbarui term kosakata (admin). Data yang sudah tersimpan tidak ikut berubah
+// @Tags Vocabularies
+// @Accept json
+// @Produce json
+// @Param type path string true "jurusan, industri, provinsi atau kota"
+// @Param id path string true "Term ID"
+// @Param data body model.TermRequest true "Data term"
+// @Success 200 {object} model.Term
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/vocabularies/{type}/{id} [put]
+func (s *VocabularyService) UpdateTerm(c *gin.Context) {
+	objID, err := parseObjectID("id", c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	term, err := s.bindTerm(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	term.ID = objID
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.checkTerm(ctx, &term); err != nil {
+		c.Error(err)
+		return
+	}
+	before, err := s.repo.FindByID(ctx, term.Type, objID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if err := s.repo.Update(ctx, objID, &term); err != nil {
+		c.Error(err)
+		return
+	}
+	s.resolver.Invalidate()
+	s.audit.Record(c, model.AuditUpdate, model.EntityTerm, objID, before, term)
+
+	c.JSON(http.StatusOK, term)
+}
+
+// @Summary Delete vocabulary term
+// @Description Menghapus term kosakata (admin). Data yang sudah memakai term tetap menyimpan nama dan kodenya
+// @Tags Vocabularies
+// @Produce json
+// @Param type path string true "jurusan, industri, provinsi atau kota"
+// @Param id path string true "Term ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Security BearerAuth
+// @Router /api/vocabularies/{type}/{id} [delete]
+func (s *VocabularyService) DeleteTerm(c *gin.Context) {
+	typ, err := vocabType(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	objID, err := parseObjectID("id", c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	before, err := s.repo.FindByID(ctx, typ, objID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if err := s.repo.Delete(ctx, typ, objID); err != nil {
+		c.Error(err)
+		return
+	}
+	s.resolver.Invalidate()
+	s.audit.Record(c, model.AuditDelete, model.EntityTerm, objID, before, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "term deleted"})
+}
+
+func (s *VocabularyService) bindTerm(c *gin.Context) (model.Term, error) {
+	typ, err := vocabType(c)
+	if err != nil {
+		return model.Term{}, err
+	}
+	var req model.TermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return model.Term{}, apperror.FromBinding(err)
+	}
+	return req.ToTerm(typ), nil
+}
+
+// checkTerm memeriksa aturan per jenis: fakultas untuk jurusan dan
+// provinsi induk untuk kota.
+func (s *VocabularyService) checkTerm(ctx context.Context, t *model.Term) error {
+	switch t.Type {
+	case model.VocabJurusan:
+		if t.Faculty == "" {
+			return apperror.Validation("validation_failed", "Request validation failed", apperror.FieldError{
+				Field: "faculty", Code: "required", Message: "is required for jurusan",
+			})
+		}
+	case model.VocabKota:
+		if t.Parent == "" {
+			return apperror.Validation("validation_failed", "Request validation failed", apperror.FieldError{
+				Field: "parent", Code: "required", Message: "is required for kota",
+			})
+		}
+		if _, err := s.repo.FindByCode(ctx, model.VocabProvinsi, t.Parent); err != nil {
+			if apperror.Is(err, apperror.KindNotFound) {
+				return apperror.Validation("validation_failed", "Request validation failed", apperror.FieldError{
+					Field: "parent", Code: "not_found", Message: "must be an existing provinsi code",
+				})
+			}
+			return err
+		}
+	default:
+		t.Parent, t.Faculty = "", ""
+	}
+	return nil
+}
+
+func vocabType(c *gin.Context) (string, error) {
+	typ := c.Param("type")
+	if !slices.Contains(model.VocabTypes, typ) {
+		return "", apperror.Validation("validation_failed", "Request validation failed", apperror.FieldError{
+			Field: "type", Code: "oneof", Message: "must be one of [" + strings.Join(model.VocabTypes, " ") + "]",
+		})
+	}
+	return typ, nil
+}
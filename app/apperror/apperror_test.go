@@ -0,0 +1,50 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		err  *Error
+		want int
+	}{
+		{Validation("v", "v"), http.StatusBadRequest},
+		{Unauthorized("u", "u"), http.StatusUnauthorized},
+		{Forbidden("f", "f"), http.StatusForbidden},
+		{NotFound("n", "n"), http.StatusNotFound},
+		{Conflict("c", "c"), http.StatusConflict},
+		{TooManyRequests("t", "t", 0), http.StatusTooManyRequests},
+		{MethodNotAllowed("m", "m"), http.StatusMethodNotAllowed},
+		{Internal(errors.New("boom")), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Status(); got != tt.want {
+			t.Errorf("%s.Status() = %d, want %d", tt.err.Code, got, tt.want)
+		}
+	}
+}
+
+func TestFromAndIs(t *testing.T) {
+	notFound := NotFound("alumni_not_found", "Alumni not found")
+	wrapped := fmt.Errorf("load: %w", notFound)
+
+	if got := From(wrapped); got != notFound {
+		t.Errorf("From(wrapped) = %v, want the domain error", got)
+	}
+	if !Is(wrapped, KindNotFound) || Is(wrapped, KindConflict) {
+		t.Error("Is does not follow the wrapped kind")
+	}
+
+	plain := errors.New("connection reset")
+	got := From(plain)
+	if got.Kind != KindInternal || !errors.Is(got, plain) {
+		t.Errorf("From(plain) = %+v, want internal wrapping the error", got)
+	}
+	if got.Message == plain.Error() {
+		t.Error("internal error leaks the original message")
+	}
+}
@@ -0,0 +1,99 @@
+package apperror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FromBinding mengubah error dari c.ShouldBindJSON menjadi error validasi
+// dengan detail per field.
+func FromBinding(err error) *Error {
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		fields := make([]FieldError, 0, len(verrs))
+		for _, fe := range verrs {
+			fields = append(fields, FieldError{
+				Field:   fieldPath(fe),
+				Code:    fe.Tag(),
+				Message: fieldMessage(fe),
+			})
+		}
+		return Validation("validation_failed", "Request validation failed", fields...)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return Validation("validation_failed", "Request validation failed", FieldError{
+			Field:   typeErr.Field,
+			Code:    "type",
+			Message: fmt.Sprintf("must be of type %s", typeErr.Type),
+		})
+	}
+
+	return &Error{Kind: KindValidation, Code: "invalid_body", Message: "Request body is not valid JSON", Err: err}
+}
+
+// fieldPath mengembalikan path field tanpa nama struct root,
+// misalnya "CreateAlumniRequest.tahun_lulus" menjadi "tahun_lulus".
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "required_without":
+		return fmt.Sprintf("is required when %s is not set", paramField(fe.Param()))
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", fe.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of [%s]", fe.Param())
+	case "nim":
+		return "must be 6-20 letters or digits"
+	case "phone_id":
+		return "must be an Indonesian phone number (0…, 62… or +62…)"
+	case "year":
+		return "must be a valid year"
+	case "month":
+		return "must be a month in YYYY-MM format"
+	case "gtefield":
+		return fmt.Sprintf("must not be earlier than %s", paramField(fe.Param()))
+	case "mongodb":
+		return "must be a 24 character hex ObjectID"
+	case "len":
+		return fmt.Sprintf("must have length %s", fe.Param())
+	default:
+		return fmt.Sprintf("failed on the '%s' rule", fe.Tag())
+	}
+}
+
+// paramField mengubah nama field Go pada parameter rule (misalnya
+// "CompanyID" atau "Tanggal_Kerja") menjadi gaya json "company_id".
+func paramField(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		upper := unicode.IsUpper(r)
+		if upper && i > 0 && runes[i-1] != '_' && !unicode.IsUpper(runes[i-1]) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
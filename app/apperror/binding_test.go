@@ -0,0 +1,111 @@
+package apperror
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type bindingGaji struct {
+	Min int64 `json:"min"`
+}
+
+type bindingRequest struct {
+	Nama      string       `json:"nama" validate:"required"`
+	Email     string       `json:"email" validate:"omitempty,email"`
+	Angkatan  int          `json:"angkatan" validate:"min=1950"`
+	Status    string       `json:"status" validate:"omitempty,oneof=current ended"`
+	CompanyID string       `json:"company_id"`
+	Perusahan string       `json:"nama_perusahaan" validate:"required_without=CompanyID"`
+	Gaji      *bindingGaji `json:"gaji" validate:"omitempty"`
+}
+
+// validate memvalidasi v dengan nama field dari tag json, seperti
+// validation.Register.
+func validate(v any) error {
+	val := validator.New()
+	val.RegisterTagNameFunc(func(f reflect.StructField) string {
+		return strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+	})
+	return val.Struct(v)
+}
+
+func TestFromBindingValidation(t *testing.T) {
+	err := validate(bindingRequest{Email: "bukan-email", Angkatan: 1900, Status: "retired"})
+
+	got := FromBinding(err)
+	if got.Kind != KindValidation || got.Code != "validation_failed" {
+		t.Fatalf("FromBinding = %v/%s, want validation_failed", got.Kind, got.Code)
+	}
+
+	want := []FieldError{
+		{Field: "nama", Code: "required", Message: "is required"},
+		{Field: "email", Code: "email", Message: "must be a valid email address"},
+		{Field: "angkatan", Code: "min", Message: "must be at least 1950"},
+		{Field: "status", Code: "oneof", Message: "must be one of [current ended]"},
+		{Field: "nama_perusahaan", Code: "required_without", Message: "is required when company_id is not set"},
+	}
+	if !reflect.DeepEqual(got.Fields, want) {
+		t.Errorf("Fields =\n%+v\nwant\n%+v", got.Fields, want)
+	}
+}
+
+func TestFromBindingDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		code   string
+		fields []FieldError
+	}{
+		{
+			name:   "wrong type",
+			body:   `{"angkatan": "2016"}`,
+			code:   "validation_failed",
+			fields: []FieldError{{Field: "angkatan", Code: "type", Message: "must be of type int"}},
+		},
+		{
+			name:   "nested wrong type",
+			body:   `{"gaji": {"min": "banyak"}}`,
+			code:   "validation_failed",
+			fields: []FieldError{{Field: "gaji.min", Code: "type", Message: "must be of type int64"}},
+		},
+		{name: "invalid json", body: `{"nama": `, code: "invalid_body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req bindingRequest
+			err := json.Unmarshal([]byte(tt.body), &req)
+			if err == nil {
+				t.Fatal("expected a decode error")
+			}
+
+			got := FromBinding(err)
+			if got.Kind != KindValidation || got.Code != tt.code {
+				t.Fatalf("FromBinding = %v/%s, want %s", got.Kind, got.Code, tt.code)
+			}
+			if !reflect.DeepEqual(got.Fields, tt.fields) {
+				t.Errorf("Fields = %+v, want %+v", got.Fields, tt.fields)
+			}
+			if tt.code == "invalid_body" && !errors.Is(got, err) {
+				t.Error("invalid_body does not wrap the decode error")
+			}
+		})
+	}
+}
+
+func TestParamField(t *testing.T) {
+	tests := map[string]string{
+		"CompanyID":     "company_id",
+		"Tanggal_Kerja": "tanggal_kerja",
+		"Email":         "email",
+	}
+	for in, want := range tests {
+		if got := paramField(in); got != want {
+			t.Errorf("paramField(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Package audit mencatat setiap perubahan data ke audit_log: siapa, kapan,
+// dari mana dan field apa saja yang berubah.
+package audit
+
+import (
+	model "Mango/app/Model"
+	"Mango/app/repository"
+	"context"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Recorder menulis entri audit dari handler. Recorder nil tidak mencatat
+// apa pun, sehingga aman dipakai di command dan pengujian manual.
+type Recorder struct {
+	repo *repository.AuditRepository
+}
+
+func NewRecorder(repo *repository.AuditRepository) *Recorder {
+	return &Recorder{repo: repo}
+}
+
+// Record mencatat perubahan entity id oleh user yang sedang login.
+// before dan after adalah dokumen sebelum dan sesudah perubahan (nil untuk
+// create atau delete permanen). Update tanpa perubahan field tidak
+// dicatat. Kegagalan menulis audit hanya di-log karena perubahannya sudah
+// tersimpan.
+func (r *Recorder) Record(c *gin.Context, action, entity string, id primitive.ObjectID, before, after any) {
+	r.write(c, model.AuditEntry{Action: action, Entity: entity}, id, before, after)
+}
+
+// Note mencatat aksi yang tidak berupa perubahan satu dokumen (misalnya
+// ringkasan import atau merge) dengan keterangan bebas.
+func (r *Recorder) Note(c *gin.Context, action, entity string, id primitive.ObjectID, note string) {
+	r.write(c, model.AuditEntry{Action: action, Entity: entity, Note: note}, id, nil, nil)
+}
+
+func (r *Recorder) write(c *gin.Context, e model.AuditEntry, id primitive.ObjectID, before, after any) {
+	if r == nil {
+		return
+	}
+
+	e.Changes = Diff(before, after)
+	if e.Action == model.AuditUpdate && len(e.Changes) == 0 && e.Note == "" {
+		return
+	}
+
+	e.ID = primitive.NewObjectID()
+	e.At = time.Now().UTC()
+	if !id.IsZero() {
+		e.EntityID = &id
+	}
+	if user, ok := c.Value("user").(*model.User); ok {
+		e.ActorID = &user.ID
+		e.Actor = user.Username
+		e.Role = user.Role
+	}
+	e.IP = c.ClientIP()
+	e.RequestID = c.GetString("request_id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.repo.Create(ctx, &e); err != nil {
+		log.Printf("⚠️  audit %s %s %s: %v", e.Action, e.Entity, id.Hex(), err)
+	}
+}
@@ -0,0 +1,121 @@
+package audit
+
+import (
+	model "Mango/app/Model"
+	"Mango/app/crypt"
+	"reflect"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ignored adalah field yang tidak dicatat: metadata yang berubah di setiap
+// tulis, field turunan untuk pencarian dan penanda tempat sampah (sudah
+// tercermin di aksi).
+var ignored = map[string]bool{
+	"_id": true, "created_at": true, "updated_at": true, "revision": true,
+	"search": true, "name_key": true, "alias_keys": true, "email_bidx": true,
+	"deleted_at": true, "deleted_by": true, "deleted_with": true,
+}
+
+// masked adalah field yang perubahannya dicatat tanpa nilai. Field yang
+// tersimpan terenkripsi (lihat paket crypt) juga selalu disamarkan agar
+// audit log tidak menyimpan plaintext-nya.
+var masked = map[string]bool{"password": true}
+
+const maskedValue = "***"
+
+// Diff membandingkan dua dokumen (struct model atau nil) per field BSON.
+// before nil berarti dokumen baru; after nil berarti dokumen dihapus.
+func Diff(before, after any) []model.FieldChange {
+	b, a := flatten(before), flatten(after)
+
+	var changes []model.FieldChange
+	for field, bv := range b {
+		av, ok := a[field]
+		if ok && reflect.DeepEqual(bv, av) {
+			continue
+		}
+		changes = append(changes, change(field, bv, av))
+	}
+	for field, av := range a {
+		if _, ok := b[field]; !ok {
+			changes = append(changes, change(field, nil, av))
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+func change(field string, before, after any) model.FieldChange {
+	_, sealedBefore := before.(sealed)
+	_, sealedAfter := after.(sealed)
+	if masked[field] || sealedBefore || sealedAfter {
+		if before != nil {
+			before = maskedValue
+		}
+		if after != nil {
+			after = maskedValue
+		}
+	}
+	return model.FieldChange{Field: field, Before: before, After: after}
+}
+
+// sealed adalah plaintext dari field terenkripsi. Nilainya dibandingkan
+// setelah didekripsi karena ciphertext selalu berbeda di setiap tulis.
+type sealed struct{ value any }
+
+// flatten mengubah dokumen menjadi map field bertitik ke nilai. Array
+// dibandingkan sebagai satu nilai.
+func flatten(doc any) map[string]any {
+	out := map[string]any{}
+	if doc == nil {
+		return out
+	}
+	if v := reflect.ValueOf(doc); v.Kind() == reflect.Pointer && v.IsNil() {
+		return out
+	}
+
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return out
+	}
+	var m bson.M
+	if err := bson.Unmarshal(raw, &m); err != nil {
+		return out
+	}
+	walk(out, "", m)
+	return out
+}
+
+func walk(out map[string]any, prefix string, m bson.M) {
+	for k, v := range m {
+		if prefix == "" && ignored[k] {
+			continue
+		}
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch nested := v.(type) {
+		case primitive.Binary:
+			if !crypt.IsSealed(nested) {
+				out[key] = v
+				continue
+			}
+			plain, err := crypt.OpenValue(nested)
+			if err != nil {
+				plain = nil
+			}
+			out[key] = sealed{plain}
+		case bson.M:
+			walk(out, key, nested)
+		case primitive.D:
+			walk(out, key, nested.Map())
+		default:
+			out[key] = v
+		}
+	}
+}
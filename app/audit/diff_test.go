@@ -0,0 +1,137 @@
+package audit
+
+import (
+	model "Mango/app/Model"
+	"Mango/app/crypt"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type diffAddress struct {
+	Kota string `bson:"kota"`
+}
+
+type diffDoc struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Nama      string             `bson:"nama"`
+	Angkatan  int64              `bson:"angkatan"`
+	Password  string             `bson:"password,omitempty"`
+	Email     model.Secret       `bson:"email,omitempty"`
+	Alamat    diffAddress        `bson:"alamat"`
+	Tags      []string           `bson:"tags,omitempty"`
+	UpdatedAt int64              `bson:"updated_at"`
+	DeletedAt int64              `bson:"deleted_at,omitempty"`
+}
+
+func TestDiff(t *testing.T) {
+	k, err := crypt.NewKeyring()
+	if err != nil {
+		t.Fatal(err)
+	}
+	crypt.Use(k)
+
+	base := diffDoc{
+		ID:        primitive.NewObjectID(),
+		Nama:      "Budi",
+		Angkatan:  2016,
+		Email:     "budi@example.com",
+		Alamat:    diffAddress{Kota: "Surabaya"},
+		Tags:      []string{"alumni"},
+		UpdatedAt: 1,
+	}
+	with := func(edit func(d *diffDoc)) diffDoc {
+		d := base
+		d.Tags = append([]string(nil), base.Tags...)
+		edit(&d)
+		return d
+	}
+
+	tests := []struct {
+		name          string
+		before, after any
+		want          []model.FieldChange
+	}{
+		{
+			name:   "metadata only",
+			before: base,
+			after:  with(func(d *diffDoc) { d.UpdatedAt = 2; d.DeletedAt = 3; d.ID = primitive.NewObjectID() }),
+			want:   nil,
+		},
+		{
+			name:   "field changed",
+			before: base,
+			after:  with(func(d *diffDoc) { d.Nama = "Budi Santoso"; d.Angkatan = 2017 }),
+			want: []model.FieldChange{
+				{Field: "angkatan", Before: int64(2016), After: int64(2017)},
+				{Field: "nama", Before: "Budi", After: "Budi Santoso"},
+			},
+		},
+		{
+			name:   "nested field",
+			before: base,
+			after:  with(func(d *diffDoc) { d.Alamat.Kota = "Malang" }),
+			want:   []model.FieldChange{{Field: "alamat.kota", Before: "Surabaya", After: "Malang"}},
+		},
+		{
+			name:   "array compared as a whole",
+			before: base,
+			after:  with(func(d *diffDoc) { d.Tags = append(d.Tags, "mentor") }),
+			want:   []model.FieldChange{{Field: "tags", Before: primitive.A{"alumni"}, After: primitive.A{"alumni", "mentor"}}},
+		},
+		{
+			name:   "field removed",
+			before: base,
+			after:  with(func(d *diffDoc) { d.Tags = nil }),
+			want:   []model.FieldChange{{Field: "tags", Before: primitive.A{"alumni"}, After: nil}},
+		},
+		{
+			name:   "encrypted value re-sealed unchanged",
+			before: base,
+			after:  with(func(d *diffDoc) {}),
+			want:   nil,
+		},
+		{
+			name:   "encrypted value changed is masked",
+			before: base,
+			after:  with(func(d *diffDoc) { d.Email = "budi.s@example.com" }),
+			want:   []model.FieldChange{{Field: "email", Before: maskedValue, After: maskedValue}},
+		},
+		{
+			name:   "password is masked",
+			before: base,
+			after:  with(func(d *diffDoc) { d.Password = "rahasia" }),
+			want:   []model.FieldChange{{Field: "password", Before: nil, After: maskedValue}},
+		},
+		{
+			name:   "created",
+			before: nil,
+			after:  diffDoc{Nama: "Siti", Angkatan: 2017, Email: "siti@example.com"},
+			want: []model.FieldChange{
+				{Field: "alamat.kota", Before: nil, After: ""},
+				{Field: "angkatan", Before: nil, After: int64(2017)},
+				{Field: "email", Before: nil, After: maskedValue},
+				{Field: "nama", Before: nil, After: "Siti"},
+			},
+		},
+		{
+			name:   "deleted through a nil pointer",
+			before: &diffDoc{Nama: "Siti"},
+			after:  (*diffDoc)(nil),
+			want: []model.FieldChange{
+				{Field: "alamat.kota", Before: "", After: nil},
+				{Field: "angkatan", Before: int64(0), After: nil},
+				{Field: "nama", Before: "Siti", After: nil},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(tt.before, tt.after)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff =\n%#v\nwant\n%#v", got, tt.want)
+			}
+		})
+	}
+}
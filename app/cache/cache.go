@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry struct {
+	value   any
+	expires time.Time
+}
+
+// TTL adalah cache in-memory sederhana dengan masa berlaku per entri.
+// Aman dipakai bersamaan dari banyak goroutine.
+type TTL struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	items map[string]entry
+}
+
+func NewTTL(ttl time.Duration) *TTL {
+	return &TTL{ttl: ttl, items: make(map[string]entry)}
+}
+
+func (c *TTL) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(c.items, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+func (c *TTL) Set(key string, value any) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	// Bersihkan entri kedaluwarsa agar map tidak tumbuh tanpa batas
+	for k, e := range c.items {
+		if now.After(e.expires) {
+			delete(c.items, k)
+		}
+	}
+	c.items[key] = entry{value: value, expires: now.Add(c.ttl)}
+}
+
+// Clear menghapus semua entri.
+func (c *TTL) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[string]entry)
+}
@@ -0,0 +1,251 @@
+// Package consistency memeriksa referensi antar koleksi (pekerjaan ke
+// alumni, user ke alumni, upload ke user) dan file upload di disk, untuk
+// menemukan data yatim dari penghapusan lama, serta nilai ganda yang
+// mencegah index unik dibuat.
+package consistency
+
+import (
+	model "Mango/app/Model"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Jenis masalah yang dilaporkan.
+const (
+	OrphanPekerjaan  = "orphan_pekerjaan"   // pekerjaan aktif tanpa alumni aktif
+	DanglingUserLink = "dangling_user_link" // user aktif menunjuk alumni yang tidak aktif
+	OrphanUpload     = "orphan_upload"      // upload tanpa user maupun alumni
+	MissingFile      = "missing_file"       // dokumen upload tanpa file di disk
+	StrayFile        = "stray_file"         // file di disk tanpa dokumen upload
+	DuplicateUser    = "duplicate_username" // beberapa user dengan username sama
+	DuplicateNIM     = "duplicate_nim"      // beberapa alumni dengan NIM sama
+)
+
+// Issue adalah satu temuan. ID kosong untuk StrayFile.
+type Issue struct {
+	Kind  string             `json:"kind"`
+	ID    primitive.ObjectID `json:"id,omitempty"`
+	Ref   string             `json:"ref"`
+	Fixed bool               `json:"fixed"`
+}
+
+// Check menjalankan semua pemeriksaan. uploadDir adalah direktori root
+// file upload. Dengan fix, temuan diperbaiki dengan cara yang bisa
+// dipulihkan jika memungkinkan: pekerjaan yatim dipindah ke tempat
+// sampah, tautan user dilepas, dokumen upload tanpa file dan upload
+// yatim dihapus beserta filenya, file liar dihapus. Username dan NIM
+// ganda hanya dilaporkan; memilih data yang benar butuh keputusan admin.
+func Check(ctx context.Context, db *mongo.Database, uploadDir string, fix bool) ([]Issue, error) {
+	var issues []Issue
+	for _, check := range []func(context.Context, *mongo.Database, string, bool) ([]Issue, error){
+		duplicateUsernames, duplicateNIMs, orphanPekerjaan, danglingUserLinks, uploads,
+	} {
+		found, err := check(ctx, db, uploadDir, fix)
+		if err != nil {
+			return issues, err
+		}
+		issues = append(issues, found...)
+	}
+	return issues, nil
+}
+
+// lookupActive mengisi `as` dengan dokumen aktif di koleksi from yang
+// _id-nya sama dengan localField.
+func lookupActive(from, localField, as string) bson.D {
+	return bson.D{{Key: "$lookup", Value: bson.M{
+		"from": from,
+		"let":  bson.M{"ref": "$" + localField},
+		"pipeline": mongo.Pipeline{{{Key: "$match", Value: bson.M{
+			"$expr":      bson.M{"$eq": bson.A{"$_id", "$$ref"}},
+			"deleted_at": bson.M{"$exists": false},
+		}}}},
+		"as": as,
+	}}}
+}
+
+// duplicates melaporkan setiap dokumen di col yang field-nya sama dengan
+// dokumen lain, termasuk yang di tempat sampah karena index unik juga
+// mencakupnya. match membatasi dokumen yang ikut index.
+func duplicates(ctx context.Context, col *mongo.Collection, field string, match bson.M, kind string) ([]Issue, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{"_id": "$" + field, "ids": bson.M{"$push": "$_id"}}}},
+		{{Key: "$match", Value: bson.M{"ids.1": bson.M{"$exists": true}}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+	var groups []struct {
+		Value string               `bson:"_id"`
+		IDs   []primitive.ObjectID `bson:"ids"`
+	}
+	if err := aggregate(ctx, col, pipeline, &groups); err != nil {
+		return nil, err
+	}
+
+	var issues []Issue
+	for _, g := range groups {
+		ref := fmt.Sprintf("%s %q shared by %d documents", field, g.Value, len(g.IDs))
+		for _, id := range g.IDs {
+			issues = append(issues, Issue{Kind: kind, ID: id, Ref: ref})
+		}
+	}
+	return issues, nil
+}
+
+func duplicateUsernames(ctx context.Context, db *mongo.Database, _ string, _ bool) ([]Issue, error) {
+	return duplicates(ctx, db.Collection("Users"), "username", bson.M{}, DuplicateUser)
+}
+
+func duplicateNIMs(ctx context.Context, db *mongo.Database, _ string, _ bool) ([]Issue, error) {
+	return duplicates(ctx, db.Collection("alumni"), "nim", bson.M{"nim": bson.M{"$gt": ""}}, DuplicateNIM)
+}
+
+func orphanPekerjaan(ctx context.Context, db *mongo.Database, _ string, fix bool) ([]Issue, error) {
+	col := db.Collection("pekerjaan_alumni")
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"deleted_at": bson.M{"$exists": false}}}},
+		lookupActive("alumni", "alumni_id", "owner"),
+		{{Key: "$match", Value: bson.M{"owner": bson.M{"$size": 0}}}},
+		{{Key: "$project", Value: bson.M{"alumni_id": 1}}},
+	}
+	var rows []model.Pekerjaan
+	if err := aggregate(ctx, col, pipeline, &rows); err != nil {
+		return nil, err
+	}
+
+	issues := make([]Issue, 0, len(rows))
+	for _, p := range rows {
+		issue := Issue{Kind: OrphanPekerjaan, ID: p.ID, Ref: "alumni " + p.AlumniID.Hex()}
+		if fix {
+			// deleted_with membuat pekerjaan ikut pulih jika alumninya di-restore
+			mark := bson.M{"deleted_at": time.Now().Unix(), "deleted_with": p.AlumniID}
+			if _, err := col.UpdateByID(ctx, p.ID, bson.M{"$set": mark}); err != nil {
+				return issues, err
+			}
+			issue.Fixed = true
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+func danglingUserLinks(ctx context.Context, db *mongo.Database, _ string, fix bool) ([]Issue, error) {
+	col := db.Collection("Users")
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"deleted_at": bson.M{"$exists": false}, "alumni_id": bson.M{"$exists": true}}}},
+		lookupActive("alumni", "alumni_id", "alumni"),
+		{{Key: "$match", Value: bson.M{"alumni": bson.M{"$size": 0}}}},
+		{{Key: "$project", Value: bson.M{"alumni_id": 1}}},
+	}
+	var rows []model.User
+	if err := aggregate(ctx, col, pipeline, &rows); err != nil {
+		return nil, err
+	}
+
+	issues := make([]Issue, 0, len(rows))
+	for _, u := range rows {
+		issue := Issue{Kind: DanglingUserLink, ID: u.ID, Ref: "alumni " + u.AlumniID.Hex()}
+		if fix {
+			if _, err := col.UpdateByID(ctx, u.ID, bson.M{"$unset": bson.M{"alumni_id": ""}}); err != nil {
+				return issues, err
+			}
+			issue.Fixed = true
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// uploads memeriksa dokumen upload (pemilik dan file) lalu file di disk
+// yang tidak dirujuk dokumen mana pun.
+func uploads(ctx context.Context, db *mongo.Database, uploadDir string, fix bool) ([]Issue, error) {
+	col := db.Collection("uploads")
+	pipeline := mongo.Pipeline{
+		{{Key: "$lookup", Value: bson.M{"from": "Users", "localField": "user_id", "foreignField": "_id", "as": "user"}}},
+		{{Key: "$lookup", Value: bson.M{"from": "alumni", "localField": "user_id", "foreignField": "_id", "as": "alumni"}}},
+		{{Key: "$set", Value: bson.M{"orphan": bson.M{"$and": bson.A{
+			bson.M{"$eq": bson.A{bson.M{"$size": "$user"}, 0}},
+			bson.M{"$eq": bson.A{bson.M{"$size": "$alumni"}, 0}},
+		}}}}},
+		{{Key: "$project", Value: bson.M{"user_id": 1, "file_path": 1, "orphan": 1}}},
+	}
+	var rows []struct {
+		model.Files `bson:",inline"`
+		Orphan      bool `bson:"orphan"`
+	}
+	if err := aggregate(ctx, col, pipeline, &rows); err != nil {
+		return nil, err
+	}
+
+	var issues []Issue
+	known := map[string]bool{}
+	for _, f := range rows {
+		path := filepath.Clean(f.Filepath)
+		_, statErr := os.Stat(path)
+		missing := errors.Is(statErr, fs.ErrNotExist)
+
+		var issue Issue
+		switch {
+		case f.Orphan:
+			issue = Issue{Kind: OrphanUpload, ID: f.ID, Ref: "user " + f.UserID.Hex()}
+		case missing:
+			issue = Issue{Kind: MissingFile, ID: f.ID, Ref: f.Filepath}
+		default:
+			known[path] = true
+			continue
+		}
+
+		if fix {
+			if _, err := col.DeleteOne(ctx, bson.M{"_id": f.ID}); err != nil {
+				return issues, err
+			}
+			if !missing {
+				if err := os.Remove(path); err != nil {
+					return issues, err
+				}
+			}
+			issue.Fixed = true
+		} else if !missing {
+			known[path] = true
+		}
+		issues = append(issues, issue)
+	}
+
+	err := filepath.WalkDir(uploadDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && path == uploadDir {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() || known[filepath.Clean(path)] {
+			return nil
+		}
+		issue := Issue{Kind: StrayFile, Ref: path}
+		if fix {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			issue.Fixed = true
+		}
+		issues = append(issues, issue)
+		return nil
+	})
+	return issues, err
+}
+
+func aggregate(ctx context.Context, col *mongo.Collection, pipeline mongo.Pipeline, out any) error {
+	cursor, err := col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	return cursor.All(ctx, out)
+}
@@ -0,0 +1,163 @@
+// Package crypt mengenkripsi field sensitif sebelum disimpan ke MongoDB
+// (envelope encryption). Setiap nilai dienkripsi dengan data key acak
+// (AES-256-GCM); data key itu dibungkus dengan key aktif dari keyring.
+// Rotasi key cukup membungkus ulang data key, tanpa mengenkripsi ulang
+// isinya. Email juga disimpan sebagai blind index (HMAC) agar tetap bisa
+// dicari dan dijaga unik.
+package crypt
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// KeySize adalah panjang key (AES-256 dan HMAC-SHA256).
+const KeySize = 32
+
+// Keyring adalah isi file keyring. Key lama tetap disimpan agar nilai
+// yang dibungkus dengannya masih bisa dibuka sampai di-rewrap. IndexKey
+// tidak ikut dirotasi karena blind index harus stabil.
+type Keyring struct {
+	Active   string            `json:"active"`
+	Keys     map[string][]byte `json:"keys"`
+	IndexKey []byte            `json:"index_key"`
+}
+
+var current atomic.Pointer[Keyring]
+
+// Use menjadikan k keyring yang dipakai Seal, Open dan EmailIndex.
+func Use(k *Keyring) {
+	current.Store(k)
+}
+
+// Current mengembalikan keyring yang sedang dipakai.
+func Current() (*Keyring, error) {
+	k := current.Load()
+	if k == nil {
+		return nil, errors.New("field encryption keyring is not loaded")
+	}
+	return k, nil
+}
+
+// NewKeyring membuat keyring dengan satu key aktif dan index key baru.
+func NewKeyring() (*Keyring, error) {
+	k := &Keyring{Keys: map[string][]byte{}}
+	indexKey, err := randomKey()
+	if err != nil {
+		return nil, err
+	}
+	k.IndexKey = indexKey
+	if _, err := k.Rotate(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// LoadKeyring membaca file keyring JSON dan memeriksa isinya.
+func LoadKeyring(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var k Keyring
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, fmt.Errorf("parse keyring %s: %w", path, err)
+	}
+	if err := k.validate(); err != nil {
+		return nil, fmt.Errorf("keyring %s: %w", path, err)
+	}
+	return &k, nil
+}
+
+// Create membuat dan menyimpan keyring baru di path. File yang sudah ada
+// tidak pernah ditimpa: keyring baru membuat semua nilai terenkripsi dan
+// blind index lama tidak terbaca.
+func Create(path string) (*Keyring, error) {
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("keyring %s already exists", path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	k, err := NewKeyring()
+	if err != nil {
+		return nil, err
+	}
+	return k, k.Save(path)
+}
+
+// Save menulis keyring ke path dengan izin 0600. File ditulis ke file
+// sementara lalu di-rename agar tidak pernah setengah tertulis.
+func (k *Keyring) Save(path string) error {
+	data, err := json.MarshalIndent(k, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".keyring-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Rotate menambah key baru dan menjadikannya aktif. Nilai baru dibungkus
+// dengan key ini; nilai lama tetap bisa dibuka dengan key sebelumnya.
+func (k *Keyring) Rotate() (string, error) {
+	key, err := randomKey()
+	if err != nil {
+		return "", err
+	}
+	// ID berisi tanggal pembuatan dan akhiran acak agar tidak bentrok
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	id := fmt.Sprintf("%s-%x", time.Now().UTC().Format("20060102"), suffix)
+	k.Keys[id] = key
+	k.Active = id
+	return id, nil
+}
+
+func (k *Keyring) validate() error {
+	if len(k.IndexKey) != KeySize {
+		return fmt.Errorf("index_key must be %d bytes", KeySize)
+	}
+	for id, key := range k.Keys {
+		if len(id) == 0 || len(id) > 255 {
+			return fmt.Errorf("key id %q must be 1-255 bytes", id)
+		}
+		if len(key) != KeySize {
+			return fmt.Errorf("key %s must be %d bytes", id, KeySize)
+		}
+	}
+	if _, ok := k.Keys[k.Active]; !ok {
+		return fmt.Errorf("active key %q is not in keys", k.Active)
+	}
+	return nil
+}
+
+func randomKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,342 @@
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Subtype adalah subtype BSON binary (rentang user-defined) untuk nilai
+// terenkripsi.
+const Subtype byte = 0x80
+
+// Format payload versi 1:
+//
+//	versi(1) | panjang kid(1) | kid | data key terbungkus | nonce | ciphertext
+//
+// Plaintext adalah tipe BSON (1 byte) diikuti nilai BSON-nya sehingga
+// string maupun dokumen kembali ke tipe semula.
+const payloadVersion = 1
+
+const (
+	nonceSize   = 12
+	wrappedSize = nonceSize + KeySize + 16
+)
+
+var errMalformed = errors.New("malformed encrypted value")
+
+// Seal mengenkripsi nilai BSON dengan data key baru yang dibungkus key
+// aktif. Hasilnya berupa binary dengan subtype Subtype, siap dikembalikan
+// dari MarshalBSONValue.
+func Seal(t bsontype.Type, value []byte) (bsontype.Type, []byte, error) {
+	k, err := Current()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	dataKey := make([]byte, KeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return 0, nil, err
+	}
+	wrapped, err := encrypt(k.Keys[k.Active], dataKey, []byte(k.Active))
+	if err != nil {
+		return 0, nil, err
+	}
+	ciphertext, err := encrypt(dataKey, append([]byte{byte(t)}, value...), nil)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return bson.MarshalValue(primitive.Binary{Subtype: Subtype, Data: k.payload(wrapped, ciphertext)})
+}
+
+// Open membalik Seal. Nilai yang tidak terenkripsi (data lama sebelum
+// migrasi) dikembalikan apa adanya.
+func Open(t bsontype.Type, value []byte) (bsontype.Type, []byte, error) {
+	payload, ok := sealedPayload(t, value)
+	if !ok {
+		return t, value, nil
+	}
+	k, err := Current()
+	if err != nil {
+		return 0, nil, err
+	}
+	dataKey, ciphertext, err := k.unwrap(payload)
+	if err != nil {
+		return 0, nil, err
+	}
+	plain, err := decrypt(dataKey, ciphertext, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(plain) == 0 {
+		return 0, nil, errMalformed
+	}
+	return bsontype.Type(plain[0]), plain[1:], nil
+}
+
+// IsSealed melaporkan apakah v adalah nilai terenkripsi hasil Seal.
+func IsSealed(v any) bool {
+	b, ok := v.(primitive.Binary)
+	return ok && b.Subtype == Subtype
+}
+
+// OpenValue membuka nilai terenkripsi hasil decode ke tipe dinamis
+// (misalnya bson.M). Dokumen dikembalikan sebagai bson.M.
+func OpenValue(v any) (any, error) {
+	b, ok := v.(primitive.Binary)
+	if !ok || b.Subtype != Subtype {
+		return v, nil
+	}
+	t, data, err := bson.MarshalValue(b)
+	if err != nil {
+		return nil, err
+	}
+	if t, data, err = Open(t, data); err != nil {
+		return nil, err
+	}
+	if t == bsontype.EmbeddedDocument {
+		var m bson.M
+		err := bson.Unmarshal(data, &m)
+		return m, err
+	}
+	var out any
+	err = bson.RawValue{Type: t, Value: data}.Unmarshal(&out)
+	return out, err
+}
+
+// OpenDoc mengganti semua nilai terenkripsi di doc (termasuk dokumen dan
+// array bersarang) dengan plaintext-nya.
+func OpenDoc(doc bson.M) error {
+	for key, v := range doc {
+		opened, err := openAny(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		doc[key] = opened
+	}
+	return nil
+}
+
+func openAny(v any) (any, error) {
+	switch t := v.(type) {
+	case primitive.Binary:
+		return OpenValue(t)
+	case bson.M:
+		return t, OpenDoc(t)
+	case bson.D:
+		for i := range t {
+			opened, err := openAny(t[i].Value)
+			if err != nil {
+				return nil, err
+			}
+			t[i].Value = opened
+		}
+	case bson.A:
+		for i := range t {
+			opened, err := openAny(t[i])
+			if err != nil {
+				return nil, err
+			}
+			t[i] = opened
+		}
+	}
+	return v, nil
+}
+
+// Rewrap membungkus ulang data key nilai terenkripsi dengan key aktif.
+// Ciphertext tidak berubah. changed false jika nilai sudah memakai key
+// aktif.
+func Rewrap(b primitive.Binary) (out primitive.Binary, changed bool, err error) {
+	if b.Subtype != Subtype {
+		return b, false, nil
+	}
+	k, err := Current()
+	if err != nil {
+		return b, false, err
+	}
+	if id, ok := payloadKeyID(b.Data); ok && id == k.Active {
+		return b, false, nil
+	}
+	dataKey, ciphertext, err := k.unwrap(b.Data)
+	if err != nil {
+		return b, false, err
+	}
+	wrapped, err := encrypt(k.Keys[k.Active], dataKey, []byte(k.Active))
+	if err != nil {
+		return b, false, err
+	}
+
+	return primitive.Binary{Subtype: Subtype, Data: k.payload(wrapped, ciphertext)}, true, nil
+}
+
+// RewrapDoc menjalankan Rewrap untuk semua nilai terenkripsi di doc.
+func RewrapDoc(doc bson.M) (changed bool, err error) {
+	for key, v := range doc {
+		out, c, err := rewrapAny(v)
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", key, err)
+		}
+		if c {
+			doc[key] = out
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+func rewrapAny(v any) (any, bool, error) {
+	switch t := v.(type) {
+	case primitive.Binary:
+		return Rewrap(t)
+	case bson.M:
+		c, err := RewrapDoc(t)
+		return t, c, err
+	case bson.D:
+		changed := false
+		for i := range t {
+			out, c, err := rewrapAny(t[i].Value)
+			if err != nil {
+				return nil, false, err
+			}
+			t[i].Value, changed = out, changed || c
+		}
+		return t, changed, nil
+	case bson.A:
+		changed := false
+		for i := range t {
+			out, c, err := rewrapAny(t[i])
+			if err != nil {
+				return nil, false, err
+			}
+			t[i], changed = out, changed || c
+		}
+		return t, changed, nil
+	}
+	return v, false, nil
+}
+
+// EmailIndex adalah blind index email: HMAC-SHA256 dari email yang sudah
+// dinormalisasi. Deterministik sehingga bisa dipakai untuk pencarian
+// persis dan unique index. Email kosong menghasilkan string kosong,
+// begitu juga jika keyring belum dimuat (penulisan email tetap gagal di
+// Seal).
+func EmailIndex(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	k, err := Current()
+	if email == "" || err != nil {
+		return ""
+	}
+	mac := hmac.New(sha256.New, k.IndexKey)
+	mac.Write([]byte("email:" + email))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// DeriveKey menurunkan key HMAC untuk keperluan lain (misalnya challenge
+// token MFA) dari index key, sehingga semua replica memakai key yang sama
+// tanpa konfigurasi tambahan.
+func DeriveKey(purpose string) ([]byte, error) {
+	k, err := Current()
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, k.IndexKey)
+	mac.Write([]byte("derive:" + purpose))
+	return mac.Sum(nil), nil
+}
+
+func (k *Keyring) payload(wrapped, ciphertext []byte) []byte {
+	payload := make([]byte, 0, 2+len(k.Active)+len(wrapped)+len(ciphertext))
+	payload = append(payload, payloadVersion, byte(len(k.Active)))
+	payload = append(payload, k.Active...)
+	payload = append(payload, wrapped...)
+	payload = append(payload, ciphertext...)
+	return payload
+}
+
+func (k *Keyring) unwrap(payload []byte) (dataKey, ciphertext []byte, err error) {
+	id, ok := payloadKeyID(payload)
+	if !ok {
+		return nil, nil, errMalformed
+	}
+	rest := payload[2+len(id):]
+	if len(rest) < wrappedSize {
+		return nil, nil, errMalformed
+	}
+	kek, ok := k.Keys[id]
+	if !ok {
+		return nil, nil, fmt.Errorf("key %s is not in the keyring", id)
+	}
+	dataKey, err = decrypt(kek, rest[:wrappedSize], []byte(id))
+	if err != nil {
+		return nil, nil, err
+	}
+	return dataKey, rest[wrappedSize:], nil
+}
+
+func sealedPayload(t bsontype.Type, value []byte) ([]byte, bool) {
+	if t != bsontype.Binary {
+		return nil, false
+	}
+	var b primitive.Binary
+	if err := bson.UnmarshalValue(bsontype.Binary, value, &b); err != nil || b.Subtype != Subtype {
+		return nil, false
+	}
+	return b.Data, true
+}
+
+func payloadKeyID(payload []byte) (string, bool) {
+	if len(payload) < 2 || payload[0] != payloadVersion {
+		return "", false
+	}
+	n := int(payload[1])
+	if len(payload) < 2+n {
+		return "", false
+	}
+	return string(payload[2 : 2+n]), true
+}
+
+func encrypt(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func decrypt(key, sealed, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < nonceSize {
+		return nil, errMalformed
+	}
+	plain, err := gcm.Open(nil, sealed[:nonceSize], sealed[nonceSize:], aad)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: %w", err)
+	}
+	return plain, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,195 @@
+package crypt
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func useTestKeyring(t *testing.T) *Keyring {
+	t.Helper()
+	k, err := NewKeyring()
+	if err != nil {
+		t.Fatal(err)
+	}
+	Use(k)
+	t.Cleanup(func() { current.Store(nil) })
+	return k
+}
+
+func TestSealOpen(t *testing.T) {
+	useTestKeyring(t)
+
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"string", "budi@example.com"},
+		{"empty string", ""},
+		{"int", int32(12000000)},
+		{"document", bson.D{{Key: "min", Value: 5}, {Key: "max", Value: 10}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ, data, err := bson.MarshalValue(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sealedType, sealed, err := Seal(typ, data)
+			if err != nil {
+				t.Fatalf("Seal: %v", err)
+			}
+			if sealedType != bsontype.Binary {
+				t.Fatalf("Seal type = %v, want binary", sealedType)
+			}
+			if len(data) > 8 && bytes.Contains(sealed, data) {
+				t.Fatal("sealed value contains the plaintext")
+			}
+
+			gotType, got, err := Open(sealedType, sealed)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			if gotType != typ || !bytes.Equal(got, data) {
+				t.Errorf("Open = %v %x, want %v %x", gotType, got, typ, data)
+			}
+		})
+	}
+}
+
+func TestOpenPlaintext(t *testing.T) {
+	useTestKeyring(t)
+	typ, data, _ := bson.MarshalValue("data lama")
+	gotType, got, err := Open(typ, data)
+	if err != nil || gotType != typ || !bytes.Equal(got, data) {
+		t.Errorf("Open(plaintext) = %v %x %v, want unchanged", gotType, got, err)
+	}
+}
+
+func TestOpenRejectsTampering(t *testing.T) {
+	useTestKeyring(t)
+	typ, data, _ := bson.MarshalValue("081234567890")
+	sealedType, sealed, err := Seal(typ, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed[len(sealed)-1] ^= 1
+	if _, _, err := Open(sealedType, sealed); err == nil {
+		t.Error("Open accepted a modified ciphertext")
+	}
+
+	// Keyring lain tidak punya key yang membungkus data key
+	_, sealed, _ = Seal(typ, data)
+	useTestKeyring(t)
+	if _, _, err := Open(sealedType, sealed); err == nil {
+		t.Error("Open succeeded with another keyring")
+	}
+}
+
+func sealString(t *testing.T, s string) primitive.Binary {
+	t.Helper()
+	typ, data, _ := bson.MarshalValue(s)
+	sealedType, sealed, err := Seal(typ, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b primitive.Binary
+	if err := (bson.RawValue{Type: sealedType, Value: sealed}).Unmarshal(&b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestRewrap(t *testing.T) {
+	k := useTestKeyring(t)
+	old := sealString(t, "Surabaya")
+	oldKey := k.Active
+
+	if _, changed, err := Rewrap(old); err != nil || changed {
+		t.Fatalf("Rewrap with the same active key: changed %v, err %v", changed, err)
+	}
+
+	if _, err := k.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	rewrapped, changed, err := Rewrap(old)
+	if err != nil || !changed {
+		t.Fatalf("Rewrap after rotation: changed %v, err %v", changed, err)
+	}
+	if id, _ := payloadKeyID(rewrapped.Data); id != k.Active {
+		t.Errorf("rewrapped key id = %q, want %q", id, k.Active)
+	}
+
+	// Key lama boleh dihapus setelah rewrap
+	delete(k.Keys, oldKey)
+	got, err := OpenValue(rewrapped)
+	if err != nil || got != "Surabaya" {
+		t.Errorf("OpenValue(rewrapped) = %v, %v", got, err)
+	}
+	if _, err := OpenValue(old); err == nil {
+		t.Error("value wrapped with a removed key still opens")
+	}
+
+	plain := primitive.Binary{Subtype: 0, Data: []byte("x")}
+	if out, changed, err := Rewrap(plain); err != nil || changed || !bytes.Equal(out.Data, plain.Data) {
+		t.Errorf("Rewrap(non-sealed) = %v %v %v, want unchanged", out, changed, err)
+	}
+}
+
+func TestEmailIndex(t *testing.T) {
+	useTestKeyring(t)
+	base := EmailIndex("budi@example.com")
+	if base == "" {
+		t.Fatal("EmailIndex returned empty string")
+	}
+
+	tests := []struct {
+		email string
+		same  bool
+	}{
+		{"budi@example.com", true},
+		{"BUDI@Example.com", true},
+		{"  budi@example.com\n", true},
+		{"budi2@example.com", false},
+	}
+	for _, tt := range tests {
+		if got := EmailIndex(tt.email) == base; got != tt.same {
+			t.Errorf("EmailIndex(%q) == base is %v, want %v", tt.email, got, tt.same)
+		}
+	}
+	if got := EmailIndex("   "); got != "" {
+		t.Errorf("EmailIndex(blank) = %q, want empty", got)
+	}
+
+	// Keyring lain menghasilkan index lain
+	useTestKeyring(t)
+	if EmailIndex("budi@example.com") == base {
+		t.Error("EmailIndex does not depend on the keyring")
+	}
+	current.Store(nil)
+	if got := EmailIndex("budi@example.com"); got != "" {
+		t.Errorf("EmailIndex without keyring = %q, want empty", got)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	k, err := Create(path)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	loaded, err := LoadKeyring(path)
+	if err != nil {
+		t.Fatalf("LoadKeyring: %v", err)
+	}
+	if loaded.Active != k.Active || !bytes.Equal(loaded.IndexKey, k.IndexKey) {
+		t.Error("loaded keyring differs from the created one")
+	}
+	if _, err := Create(path); err == nil {
+		t.Error("Create overwrote an existing keyring")
+	}
+}
@@ -0,0 +1,138 @@
+package exporter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Column adalah satu kolom export. Path adalah path field di dokumen
+// MongoDB, dengan titik untuk dokumen bersarang.
+type Column struct {
+	Key  string
+	Path string
+}
+
+// Dataset mendefinisikan kolom yang tersedia untuk satu jenis export.
+// Contacts menandai dataset berisi field kontak alumni yang tunduk pada
+// pengaturan privasi.
+type Dataset struct {
+	Name     string
+	Columns  []Column
+	Contacts bool
+}
+
+var (
+	alumniColumns = []Column{
+		{"id", "_id"},
+		{"nim", "nim"},
+		{"nama", "nama"},
+		{"jurusan", "jurusan"},
+		{"kode_jurusan", "kode_jurusan"},
+		{"fakultas", "fakultas"},
+		{"angkatan", "angkatan"},
+		{"tahun_lulus", "tahun_lulus"},
+		{"email", "email"},
+		{"no_telp", "no_telp"},
+		{"alamat", "alamat"},
+	}
+
+	pekerjaanColumns = []Column{
+		{"id", "_id"},
+		{"alumni_id", "alumni_id"},
+		{"company_id", "company_id"},
+		{"nama_perusahaan", "nama_perusahaan"},
+		{"posisi_jabatan", "posisi_jabatan"},
+		{"bidang_industri", "bidang_industri"},
+		{"kode_industri", "kode_industri"},
+		{"lokasi_kerja", "lokasi_kerja"},
+		{"kode_lokasi", "kode_lokasi"},
+		{"gaji_min", "gaji.min"},
+		{"gaji_max", "gaji.max"},
+		{"gaji_currency", "gaji.currency"},
+		{"gaji_period", "gaji.period"},
+		{"tanggal_kerja", "tanggal_kerja"},
+		{"tanggal_selesai", "tanggal_selesai"},
+		{"status", "status"},
+	}
+
+	Alumni    = Dataset{Name: "alumni", Columns: alumniColumns, Contacts: true}
+	Pekerjaan = Dataset{Name: "pekerjaan", Columns: pekerjaanColumns}
+
+	// AlumniCurrentJob adalah alumni digabung dengan pekerjaan saat ini.
+	AlumniCurrentJob = Dataset{Name: "alumni-pekerjaan", Contacts: true, Columns: append(append([]Column{}, alumniColumns...),
+		Column{"perusahaan", "current_job.nama_perusahaan"},
+		Column{"posisi_jabatan", "current_job.posisi_jabatan"},
+		Column{"bidang_industri", "current_job.bidang_industri"},
+		Column{"lokasi_kerja", "current_job.lokasi_kerja"},
+		Column{"tanggal_kerja", "current_job.tanggal_kerja"},
+	)}
+)
+
+// Select memilih kolom berdasarkan daftar key yang dipisah koma.
+// Daftar kosong berarti semua kolom.
+func (d Dataset) Select(keys string) ([]Column, error) {
+	if strings.TrimSpace(keys) == "" {
+		return d.Columns, nil
+	}
+
+	byKey := make(map[string]Column, len(d.Columns))
+	for _, c := range d.Columns {
+		byKey[c.Key] = c
+	}
+
+	var cols []Column
+	for _, k := range strings.Split(keys, ",") {
+		k = strings.TrimSpace(k)
+		c, ok := byKey[k]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q for %s", k, d.Name)
+		}
+		cols = append(cols, c)
+	}
+	return cols, nil
+}
+
+// ColumnKeys mengembalikan semua key kolom (untuk pesan error).
+func (d Dataset) ColumnKeys() []string {
+	keys := make([]string, len(d.Columns))
+	for i, c := range d.Columns {
+		keys[i] = c.Key
+	}
+	return keys
+}
+
+// lookup mengambil nilai di path bertitik dari dokumen.
+func lookup(doc bson.M, path string) any {
+	var cur any = doc
+	for _, part := range strings.Split(path, ".") {
+		switch m := cur.(type) {
+		case bson.M:
+			cur = m[part]
+		case bson.D:
+			cur = m.Map()[part]
+		default:
+			return nil
+		}
+	}
+	return normalize(cur)
+}
+
+// normalize mengubah tipe BSON menjadi tipe yang mudah ditulis.
+func normalize(v any) any {
+	switch t := v.(type) {
+	case primitive.ObjectID:
+		return t.Hex()
+	case primitive.DateTime:
+		return t.Time().UTC().Format(time.RFC3339)
+	case time.Time:
+		return t.UTC().Format(time.RFC3339)
+	case bson.M, bson.A, bson.D:
+		return fmt.Sprint(t)
+	default:
+		return t
+	}
+}
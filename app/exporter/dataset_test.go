@@ -0,0 +1,114 @@
+package exporter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    string
+		want    []string
+		wantErr string
+	}{
+		{name: "empty means all", keys: "", want: Alumni.ColumnKeys()},
+		{name: "blank means all", keys: "  ", want: Alumni.ColumnKeys()},
+		{name: "keeps requested order", keys: "nama,nim", want: []string{"nama", "nim"}},
+		{name: "trims keys", keys: " nim , email ", want: []string{"nim", "email"}},
+		{name: "unknown column", keys: "nim,gaji_min", wantErr: `unknown column "gaji_min" for alumni`},
+		{name: "empty entry", keys: "nim,,nama", wantErr: `unknown column "" for alumni`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cols, err := Alumni.Select(tt.keys)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Select(%q) error = %v, want %q", tt.keys, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Select(%q): %v", tt.keys, err)
+			}
+			got := make([]string, len(cols))
+			for i, c := range cols {
+				got[i] = c.Key
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Select(%q) = %v, want %v", tt.keys, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectNestedPath(t *testing.T) {
+	cols, err := Pekerjaan.Select("gaji_min")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cols) != 1 || cols[0].Path != "gaji.min" {
+		t.Errorf("Select(gaji_min) = %v, want path gaji.min", cols)
+	}
+}
+
+func TestAlumniCurrentJobColumns(t *testing.T) {
+	// kolom alumni tidak boleh ikut berubah saat dataset gabungan dibuat
+	if len(Alumni.Columns) != len(alumniColumns) {
+		t.Fatalf("Alumni has %d columns, want %d", len(Alumni.Columns), len(alumniColumns))
+	}
+	keys := strings.Join(AlumniCurrentJob.ColumnKeys(), ",")
+	if !strings.HasPrefix(keys, strings.Join(Alumni.ColumnKeys(), ",")+",") {
+		t.Errorf("AlumniCurrentJob keys = %s, want alumni columns first", keys)
+	}
+	if !strings.HasSuffix(keys, ",tanggal_kerja") {
+		t.Errorf("AlumniCurrentJob keys = %s, want current job columns last", keys)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	id := primitive.NewObjectID()
+	at := time.Date(2024, 3, 1, 7, 30, 0, 0, time.FixedZone("WIB", 7*3600))
+	doc := bson.M{
+		"_id":           id,
+		"nama":          "Budi",
+		"angkatan":      int32(2019),
+		"tanggal_kerja": primitive.NewDateTimeFromTime(at),
+		"updated_at":    at,
+		"gaji":          bson.M{"min": int64(5000000), "currency": "IDR"},
+		"current_job":   bson.D{{Key: "posisi_jabatan", Value: "Engineer"}},
+		"tags":          bson.A{"a", "b"},
+	}
+
+	tests := []struct {
+		path string
+		want any
+	}{
+		{"_id", id.Hex()},
+		{"nama", "Budi"},
+		{"angkatan", int32(2019)},
+		{"tanggal_kerja", "2024-03-01T00:30:00Z"},
+		{"updated_at", "2024-03-01T00:30:00Z"},
+		{"gaji.min", int64(5000000)},
+		{"gaji.currency", "IDR"},
+		{"gaji.period", nil},
+		{"current_job.posisi_jabatan", "Engineer"},
+		{"tags", "[a b]"},
+		{"gaji", "map[currency:IDR min:5000000]"},
+		{"missing", nil},
+		{"missing.deeper", nil},
+		{"nama.first", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := lookup(doc, tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lookup(%q) = %#v, want %#v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
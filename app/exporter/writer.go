@@ -0,0 +1,234 @@
+package exporter
+
+import (
+	"Mango/app/crypt"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatXLSX   Format = "xlsx"
+	FormatNDJSON Format = "ndjson"
+)
+
+// ParseFormat menerima csv, xlsx atau ndjson (default csv).
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX, FormatNDJSON:
+		return Format(s), nil
+	default:
+		return "", fmt.Errorf("unsupported format %q (use csv, xlsx or ndjson)", s)
+	}
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+func (f Format) Extension() string {
+	return string(f)
+}
+
+// rowWriter menulis baris export ke satu format.
+type rowWriter interface {
+	header(cols []Column) error
+	row(cols []Column, values []any) error
+	close() error
+}
+
+// flushEvery menentukan seberapa sering data dikirim ke client.
+const flushEvery = 500
+
+// Write membaca cursor satu dokumen demi satu dokumen dan menulisnya ke w
+// tanpa menampung seluruh koleksi di memori. project (opsional) mengubah
+// setiap dokumen sebelum ditulis, misalnya menyaring field privat. flush
+// (opsional) dipanggil berkala agar data langsung terkirim ke client.
+func Write(ctx context.Context, w io.Writer, format Format, cols []Column, cursor *mongo.Cursor, project func(bson.M), flush func()) (int, error) {
+	defer cursor.Close(ctx)
+
+	var rw rowWriter
+	switch format {
+	case FormatXLSX:
+		xw, err := newXLSXWriter(w)
+		if err != nil {
+			return 0, err
+		}
+		rw = xw
+	case FormatNDJSON:
+		rw = &ndjsonWriter{enc: json.NewEncoder(w)}
+	default:
+		rw = &csvWriter{w: csv.NewWriter(w)}
+	}
+
+	if err := rw.header(cols); err != nil {
+		return 0, err
+	}
+
+	n := 0
+	values := make([]any, len(cols))
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return n, err
+		}
+		if err := crypt.OpenDoc(doc); err != nil {
+			return n, err
+		}
+		if project != nil {
+			project(doc)
+		}
+		for i, c := range cols {
+			values[i] = lookup(doc, c.Path)
+		}
+		if err := rw.row(cols, values); err != nil {
+			return n, err
+		}
+
+		n++
+		if flush != nil && n%flushEvery == 0 {
+			if f, ok := rw.(interface{ flush() }); ok {
+				f.flush()
+			}
+			flush()
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return n, err
+	}
+	return n, rw.close()
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) header(cols []Column) error {
+	keys := make([]string, len(cols))
+	for i, col := range cols {
+		keys[i] = col.Key
+	}
+	return c.w.Write(keys)
+}
+
+func (c *csvWriter) row(_ []Column, values []any) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		if v != nil {
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) flush() { c.w.Flush() }
+
+func (c *csvWriter) close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonWriter) header([]Column) error { return nil }
+
+func (n *ndjsonWriter) row(cols []Column, values []any) error {
+	// bson.D menjaga urutan kolom sesuai permintaan
+	obj := make(orderedObject, len(cols))
+	for i, c := range cols {
+		obj[i] = bson.E{Key: c.Key, Value: values[i]}
+	}
+	return n.enc.Encode(obj)
+}
+
+func (n *ndjsonWriter) close() error { return nil }
+
+// orderedObject di-encode sebagai objek JSON dengan urutan key tetap.
+type orderedObject bson.D
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	buf := []byte{'{'}
+	for i, e := range o {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		k, err := json.Marshal(e.Key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(append(append(buf, k...), ':'), v...)
+	}
+	return append(buf, '}'), nil
+}
+
+// xlsxWriter memakai StreamWriter excelize: baris ditulis ke file
+// sementara, bukan ditampung di memori, lalu workbook dikirim saat close.
+type xlsxWriter struct {
+	out  io.Writer
+	file *excelize.File
+	sw   *excelize.StreamWriter
+	next int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	sw, err := f.NewStreamWriter("Sheet1")
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &xlsxWriter{out: w, file: f, sw: sw, next: 1}, nil
+}
+
+func (x *xlsxWriter) header(cols []Column) error {
+	keys := make([]any, len(cols))
+	for i, col := range cols {
+		keys[i] = col.Key
+	}
+	return x.writeRow(keys)
+}
+
+func (x *xlsxWriter) row(_ []Column, values []any) error {
+	return x.writeRow(values)
+}
+
+func (x *xlsxWriter) writeRow(values []any) error {
+	cell, err := excelize.CoordinatesToCellName(1, x.next)
+	if err != nil {
+		return err
+	}
+	x.next++
+	return x.sw.SetRow(cell, values)
+}
+
+func (x *xlsxWriter) close() error {
+	defer x.file.Close()
+	if err := x.sw.Flush(); err != nil {
+		return err
+	}
+	return x.file.Write(x.out)
+}
@@ -0,0 +1,105 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in          string
+		want        Format
+		contentType string
+		wantErr     bool
+	}{
+		{in: "", want: FormatCSV, contentType: "text/csv; charset=utf-8"},
+		{in: "csv", want: FormatCSV, contentType: "text/csv; charset=utf-8"},
+		{in: "xlsx", want: FormatXLSX, contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+		{in: "ndjson", want: FormatNDJSON, contentType: "application/x-ndjson"},
+		{in: "CSV", wantErr: true},
+		{in: "json", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseFormat(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseFormat(%q) = %q, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFormat(%q): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFormat(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			if ct := got.ContentType(); ct != tt.contentType {
+				t.Errorf("ContentType() = %q, want %q", ct, tt.contentType)
+			}
+			if ext := got.Extension(); ext != string(tt.want) {
+				t.Errorf("Extension() = %q, want %q", ext, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	docs := []any{
+		bson.M{"nim": "123", "nama": "Budi, S.Kom", "email": "budi@example.com"},
+		bson.M{"nim": "456", "nama": "Ani"},
+	}
+	cols, err := Alumni.Select("nim,nama,email")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hideEmail := func(doc bson.M) { delete(doc, "email") }
+
+	tests := []struct {
+		name    string
+		format  Format
+		project func(bson.M)
+		want    string
+	}{
+		{
+			name:   "csv",
+			format: FormatCSV,
+			want:   "nim,nama,email\n123,\"Budi, S.Kom\",budi@example.com\n456,Ani,\n",
+		},
+		{
+			name:    "csv with projection",
+			format:  FormatCSV,
+			project: hideEmail,
+			want:    "nim,nama,email\n123,\"Budi, S.Kom\",\n456,Ani,\n",
+		},
+		{
+			name:   "ndjson keeps column order",
+			format: FormatNDJSON,
+			want: `{"nim":"123","nama":"Budi, S.Kom","email":"budi@example.com"}` + "\n" +
+				`{"nim":"456","nama":"Ani","email":null}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := mongo.NewCursorFromDocuments(docs, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			n, err := Write(context.Background(), &buf, tt.format, cols, cursor, tt.project, nil)
+			if err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			if n != len(docs) {
+				t.Errorf("Write wrote %d rows, want %d", n, len(docs))
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
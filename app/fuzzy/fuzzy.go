@@ -0,0 +1,101 @@
+// Package fuzzy berisi pencocokan teks toleran salah ketik untuk saran
+// input (nama perusahaan, kosakata referensi, ...).
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Fold menormalkan teks untuk dibandingkan: huruf kecil, tanda baca
+// menjadi spasi dan spasi berulang dirapikan.
+func Fold(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+			continue
+		}
+		space = true
+	}
+	return b.String()
+}
+
+// Distance menghitung jarak Levenshtein antara a dan b (per rune).
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// Similarity bernilai 1 untuk teks yang sama dan mendekati 0 untuk teks
+// yang sangat berbeda.
+func Similarity(a, b string) float64 {
+	n := max(len([]rune(a)), len([]rune(b)))
+	if n == 0 {
+		return 1
+	}
+	return 1 - float64(Distance(a, b))/float64(n)
+}
+
+// Score menilai seberapa cocok candidate untuk query yang sedang diketik.
+// Kedua teks diharapkan sudah melalui Fold. Setiap kata query dicocokkan
+// dengan kata candidate terbaik; kata yang menjadi awalan kata candidate
+// dianggap hampir cocok penuh sehingga saran muncul sambil mengetik.
+func Score(query, candidate string) float64 {
+	if query == "" || candidate == "" {
+		return 0
+	}
+	if query == candidate {
+		return 1
+	}
+
+	words := strings.Fields(candidate)
+	total := 0.0
+	for _, q := range strings.Fields(query) {
+		best := 0.0
+		for _, w := range words {
+			s := Similarity(q, w)
+			if w == q {
+				s = 1
+			} else if strings.HasPrefix(w, q) {
+				s = 0.95
+			} else if rw, rq := []rune(w), []rune(q); len(rw) > len(rq) {
+				// Bandingkan dengan awalan yang sama panjang agar salah
+				// ketik pada kata yang belum selesai tetap cocok
+				s = max(s, Similarity(q, string(rw[:len(rq)])))
+			}
+			best = max(best, s)
+		}
+		total += best
+	}
+	score := total / float64(len(strings.Fields(query)))
+
+	// Sedikit hukuman untuk kata candidate yang tidak disebut query agar
+	// "telkom" lebih dekat ke "telkom" daripada "telkom sigma"
+	extra := len(words) - len(strings.Fields(query))
+	if extra > 0 {
+		score -= 0.02 * float64(extra)
+	}
+	return max(score, 0)
+}
@@ -0,0 +1,108 @@
+package fuzzy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"Telkom", "telkom"},
+		{"  PT. Telkom  (Persero) Tbk ", "pt telkom persero tbk"},
+		{"Bank-BRI/Syariah", "bank bri syariah"},
+		{"Gojek 2.0", "gojek 2 0"},
+		{"Café Ñandú", "café ñandú"},
+		{"!!!", ""},
+	}
+	for _, tt := range tests {
+		if got := Fold(tt.in); got != tt.want {
+			t.Errorf("Fold(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"telkom", "telkom", 0},
+		{"telkom", "telkon", 1},
+		{"telkom", "tlekom", 2},
+		{"kitten", "sitting", 3},
+		{"café", "cafe", 1},
+	}
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Distance(tt.b, tt.a); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 1},
+		{"abc", "abc", 1},
+		{"abc", "xyz", 0},
+		{"telkom", "telkon", 1 - 1.0/6},
+		{"ab", "abcd", 0.5},
+	}
+	for _, tt := range tests {
+		if got := Similarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		query, candidate string
+		min, max         float64
+	}{
+		{"", "telkom", 0, 0},
+		{"telkom", "", 0, 0},
+		{"telkom", "telkom", 1, 1},
+		{"telk", "telkom", 0.95, 0.95},
+		{"telkon", "telkom", 0.8, 0.9},
+		{"tel", "telkom indonesia", 0.9, 0.95},
+		{"bank mandiri", "mandiri bank", 1, 1},
+		{"telkom", "unilever", 0, 0.4},
+	}
+	for _, tt := range tests {
+		got := Score(tt.query, tt.candidate)
+		if got < tt.min-1e-9 || got > tt.max+1e-9 {
+			t.Errorf("Score(%q, %q) = %v, want in [%v, %v]", tt.query, tt.candidate, got, tt.min, tt.max)
+		}
+	}
+}
+
+func TestScoreRanking(t *testing.T) {
+	tests := []struct {
+		query, better, worse string
+	}{
+		// kata tambahan di candidate sedikit menurunkan skor
+		{"telkom", "telkom", "telkom sigma"},
+		// awalan kata lebih baik daripada salah ketik
+		{"mand", "mandiri", "mendiri"},
+		// salah ketik pada kata yang belum selesai tetap lebih dekat
+		{"telkm", "telkomsel", "unilever"},
+	}
+	for _, tt := range tests {
+		better, worse := Score(tt.query, tt.better), Score(tt.query, tt.worse)
+		if better <= worse {
+			t.Errorf("Score(%q): %q = %v, want above %q = %v", tt.query, tt.better, better, tt.worse, worse)
+		}
+	}
+}
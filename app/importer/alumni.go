@@ -0,0 +1,235 @@
+package importer
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"Mango/app/repository"
+	"Mango/app/validation"
+	"Mango/app/vocab"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ActionInsert = "insert"
+	ActionUpdate = "update"
+	ActionError  = "error"
+)
+
+// RowResult adalah hasil impor satu baris file.
+type RowResult struct {
+	Line   int                   `json:"line"`
+	NIM    string                `json:"nim,omitempty"`
+	Action string                `json:"action"` // insert, update atau error
+	Errors []apperror.FieldError `json:"errors,omitempty"`
+}
+
+// Report merangkum hasil impor. Pada dry-run, Inserted dan Updated
+// adalah jumlah yang akan terjadi jika impor dijalankan.
+type Report struct {
+	DryRun   bool        `json:"dry_run"`
+	Inserted int         `json:"inserted"`
+	Updated  int         `json:"updated"`
+	Failed   int         `json:"failed"`
+	Rows     []RowResult `json:"rows"`
+}
+
+type Options struct {
+	DryRun  bool
+	Mapping Mapping
+	// OnWrite (opsional) dipanggil setelah setiap baris tersimpan dengan
+	// dokumen sebelumnya (nil untuk insert) dan data yang ditulis.
+	OnWrite func(before *model.Alumni, after model.Alumni)
+	// By adalah user yang menjalankan impor, dicatat di riwayat versi.
+	By primitive.ObjectID
+}
+
+type AlumniImporter struct {
+	repo  *repository.AlumniRepository
+	vocab *vocab.Resolver
+}
+
+func NewAlumniImporter(repo *repository.AlumniRepository, resolver *vocab.Resolver) *AlumniImporter {
+	return &AlumniImporter{repo: repo, vocab: resolver}
+}
+
+type parsedRow struct {
+	index  int // posisi di Report.Rows
+	alumni model.Alumni
+}
+
+// Import membaca file CSV/XLSX, memetakan kolom ke field alumni,
+// memvalidasi setiap baris dan melakukan upsert berdasarkan NIM.
+// Baris yang gagal dilaporkan tanpa menghentikan baris lainnya. Dengan
+// DryRun tidak ada data yang ditulis. File atau mapping yang salah
+// menghasilkan error validasi; error lain berasal dari server.
+func (im *AlumniImporter) Import(ctx context.Context, r io.Reader, format Format, opts Options) (*Report, error) {
+	rows, err := readRows(r, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrInvalidFile("file is empty")
+	}
+
+	fields, err := opts.Mapping.resolve(rows[0])
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{DryRun: opts.DryRun, Rows: []RowResult{}}
+	var valid []parsedRow
+	firstLine := map[string]int{}
+
+	for i, record := range rows[1:] {
+		line := i + 2
+		values := map[string]string{}
+		blank := true
+		for col, field := range fields {
+			if field == "" || col >= len(record) {
+				continue
+			}
+			v := strings.TrimSpace(record[col])
+			values[field] = v
+			if v != "" {
+				blank = false
+			}
+		}
+		if blank {
+			continue
+		}
+
+		index := len(report.Rows)
+		report.Rows = append(report.Rows, RowResult{Line: line, NIM: values["nim"], Action: ActionError})
+
+		alum, fieldErrs := buildAlumni(values)
+		if len(fieldErrs) == 0 {
+			if err := im.vocab.Apply(ctx, vocab.Jurusan(&alum)); err != nil {
+				if !apperror.Is(err, apperror.KindValidation) {
+					return nil, err
+				}
+				fieldErrs = apperror.From(err).Fields
+			}
+		}
+		if len(fieldErrs) == 0 {
+			if prev, dup := firstLine[alum.NIM]; dup {
+				fieldErrs = []apperror.FieldError{{Field: "nim", Code: "duplicate", Message: fmt.Sprintf("duplicates line %d", prev)}}
+			}
+		}
+		if len(fieldErrs) > 0 {
+			report.Rows[index].Errors = fieldErrs
+			continue
+		}
+
+		report.Rows[index].NIM = alum.NIM
+		firstLine[alum.NIM] = line
+		valid = append(valid, parsedRow{index: index, alumni: alum})
+	}
+
+	if opts.DryRun {
+		if err := im.plan(ctx, report, valid); err != nil {
+			return nil, err
+		}
+	} else {
+		im.apply(ctx, report, valid, opts)
+	}
+
+	for _, row := range report.Rows {
+		switch row.Action {
+		case ActionInsert:
+			report.Inserted++
+		case ActionUpdate:
+			report.Updated++
+		default:
+			report.Failed++
+		}
+	}
+	return report, nil
+}
+
+// plan menentukan insert atau update tanpa menulis data.
+func (im *AlumniImporter) plan(ctx context.Context, report *Report, rows []parsedRow) error {
+	nims := make([]string, len(rows))
+	for i, row := range rows {
+		nims[i] = row.alumni.NIM
+	}
+	existing, err := im.repo.ExistingNIMs(ctx, nims)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		report.Rows[row.index].Action = ActionInsert
+		if existing[row.alumni.NIM] {
+			report.Rows[row.index].Action = ActionUpdate
+		}
+	}
+	return nil
+}
+
+func (im *AlumniImporter) apply(ctx context.Context, report *Report, rows []parsedRow, opts Options) {
+	for _, row := range rows {
+		result := &report.Rows[row.index]
+		alum := row.alumni
+		before, err := im.repo.UpsertByNIM(ctx, &alum, opts.By)
+		if err != nil {
+			appErr := apperror.From(err)
+			result.Errors = []apperror.FieldError{{Field: "nim", Code: appErr.Code, Message: appErr.Message}}
+			continue
+		}
+
+		result.Action = ActionUpdate
+		if before == nil {
+			result.Action = ActionInsert
+		}
+		if opts.OnWrite != nil {
+			opts.OnWrite(before, alum)
+		}
+	}
+}
+
+// buildAlumni mengubah satu baris menjadi model.Alumni dengan aturan
+// validasi yang sama seperti CreateAlumni.
+func buildAlumni(values map[string]string) (model.Alumni, []apperror.FieldError) {
+	var errs []apperror.FieldError
+	parseInt := func(field string) int {
+		v := values[field]
+		if v == "" {
+			return 0
+		}
+		// Spreadsheet sering menyimpan tahun sebagai "2020.0"
+		n, err := strconv.Atoi(strings.TrimSuffix(v, ".0"))
+		if err != nil {
+			errs = append(errs, apperror.FieldError{Field: field, Code: "type", Message: "must be a number"})
+		}
+		return n
+	}
+
+	req := model.AlumniRequest{
+		NIM:         values["nim"],
+		Nama:        values["nama"],
+		Jurusan:     values["jurusan"],
+		Angkatan:    parseInt("angkatan"),
+		Tahun_lulus: parseInt("tahun_lulus"),
+		Email:       values["email"],
+		No_telp:     values["no_telp"],
+		Alamat:      values["alamat"],
+	}
+	if len(errs) > 0 {
+		return model.Alumni{}, errs
+	}
+
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return model.Alumni{}, apperror.FromBinding(err).Fields
+	}
+
+	alum := req.ToAlumni()
+	alum.No_telp = model.Secret(validation.NormalizePhone(string(alum.No_telp)))
+	return alum, nil
+}
@@ -0,0 +1,80 @@
+package importer
+
+import (
+	model "Mango/app/Model"
+	"Mango/app/validation"
+	"testing"
+)
+
+func TestBuildAlumni(t *testing.T) {
+	if err := validation.Register(); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := func() map[string]string {
+		return map[string]string{
+			"nim":         "081911133001",
+			"nama":        " Budi Santoso ",
+			"jurusan":     "Sistem Informasi",
+			"angkatan":    "2019",
+			"tahun_lulus": "2023.0",
+			"email":       "Budi@Example.com",
+			"no_telp":     "0812-3456-7890",
+			"alamat":      "Surabaya",
+		}
+	}
+	with := func(field, value string) map[string]string {
+		v := valid()
+		v[field] = value
+		return v
+	}
+
+	tests := []struct {
+		name   string
+		values map[string]string
+		errs   map[string]string // field → code
+	}{
+		{"valid", valid(), nil},
+		{"empty optional fields", with("no_telp", ""), nil},
+		{"year not a number", with("angkatan", "dua ribu"), map[string]string{"angkatan": "type"}},
+		{"missing nim", with("nim", ""), map[string]string{"nim": "required"}},
+		{"bad nim", with("nim", "08-19"), map[string]string{"nim": "nim"}},
+		{"bad email", with("email", "budi"), map[string]string{"email": "email"}},
+		{"bad phone", with("no_telp", "12345"), map[string]string{"no_telp": "phone_id"}},
+		{"graduated before entry", with("tahun_lulus", "2018"), map[string]string{"tahun_lulus": "gtefield"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alum, errs := buildAlumni(tt.values)
+			got := map[string]string{}
+			for _, e := range errs {
+				got[e.Field] = e.Code
+			}
+			if len(got) != len(tt.errs) {
+				t.Fatalf("errors = %v, want %v", got, tt.errs)
+			}
+			for field, code := range tt.errs {
+				if got[field] != code {
+					t.Errorf("%s error = %q, want %q", field, got[field], code)
+				}
+			}
+			if len(tt.errs) > 0 {
+				return
+			}
+
+			want := model.Alumni{
+				NIM:         "081911133001",
+				Nama:        "Budi Santoso",
+				Jurusan:     "Sistem Informasi",
+				Angkatan:    2019,
+				Tahun_lulus: 2023,
+				Email:       "budi@example.com",
+				No_telp:     model.Secret(validation.NormalizePhone(tt.values["no_telp"])),
+				Alamat:      "Surabaya",
+			}
+			if alum != want {
+				t.Errorf("alumni = %+v, want %+v", alum, want)
+			}
+		})
+	}
+}
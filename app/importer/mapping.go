@@ -0,0 +1,102 @@
+package importer
+
+import (
+	"Mango/app/apperror"
+	"fmt"
+	"strings"
+)
+
+// AlumniFields adalah field model.Alumni yang bisa diisi dari file impor.
+var AlumniFields = []string{"nim", "nama", "jurusan", "angkatan", "tahun_lulus", "email", "no_telp", "alamat"}
+
+// defaultAliases memetakan judul kolom yang lazim di daftar wisudawan
+// dari bagian akademik ke field alumni.
+var defaultAliases = map[string]string{
+	"nomor induk mahasiswa": "nim",
+	"nama mahasiswa":        "nama",
+	"nama lengkap":          "nama",
+	"program studi":         "jurusan",
+	"prodi":                 "jurusan",
+	"tahun masuk":           "angkatan",
+	"tahun angkatan":        "angkatan",
+	"tahun lulus":           "tahun_lulus",
+	"tahun wisuda":          "tahun_lulus",
+	"e-mail":                "email",
+	"no hp":                 "no_telp",
+	"no. hp":                "no_telp",
+	"nomor hp":              "no_telp",
+	"telepon":               "no_telp",
+}
+
+// Mapping memetakan judul kolom di file ke nama field alumni.
+type Mapping map[string]string
+
+// errInvalidMapping menandai mapping atau header file yang tidak bisa
+// dipetakan ke field alumni.
+func errInvalidMapping(format string, args ...any) *apperror.Error {
+	msg := fmt.Sprintf(format, args...)
+	return apperror.Validation("invalid_mapping", msg, apperror.FieldError{Field: "mapping", Code: "invalid", Message: msg})
+}
+
+// resolve menghasilkan field tujuan untuk setiap kolom header. Kolom yang
+// tidak dikenali bernilai "" dan diabaikan.
+func (m Mapping) resolve(header []string) ([]string, error) {
+	custom := make(map[string]string, len(m))
+	for col, field := range m {
+		if !isAlumniField(field) {
+			return nil, errInvalidMapping("mapping %q: unknown field %q", col, field)
+		}
+		custom[normalizeHeader(col)] = field
+	}
+
+	fields := make([]string, len(header))
+	seen := map[string]bool{}
+	for i, col := range header {
+		key := normalizeHeader(col)
+		field, ok := custom[key]
+		if !ok {
+			field, ok = defaultAliases[key]
+		}
+		if !ok && isAlumniField(key) {
+			field = key
+		}
+		if field != "" && seen[field] {
+			return nil, errInvalidMapping("column %q maps to %q more than once", col, field)
+		}
+		seen[field] = true
+		fields[i] = field
+	}
+
+	if !seen["nim"] {
+		return nil, errInvalidMapping("no column maps to nim")
+	}
+	return fields, nil
+}
+
+func normalizeHeader(s string) string {
+	s = strings.TrimPrefix(s, "\ufeff")
+	s = strings.ToLower(strings.TrimSpace(s))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func isAlumniField(s string) bool {
+	for _, f := range AlumniFields {
+		if f == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseMapping membaca pasangan "Kolom=field" (dipakai CLI).
+func ParseMapping(pairs []string) (Mapping, error) {
+	m := Mapping{}
+	for _, p := range pairs {
+		col, field, ok := strings.Cut(p, "=")
+		if !ok || strings.TrimSpace(col) == "" {
+			return nil, errInvalidMapping("invalid mapping %q (want Column=field)", p)
+		}
+		m[strings.TrimSpace(col)] = strings.TrimSpace(field)
+	}
+	return m, nil
+}
@@ -0,0 +1,78 @@
+package importer
+
+import (
+	"Mango/app/apperror"
+	"reflect"
+	"testing"
+)
+
+func TestMappingResolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping Mapping
+		header  []string
+		want    []string
+		ok      bool
+	}{
+		{
+			name:   "field names",
+			header: []string{"nim", "nama", "email"},
+			want:   []string{"nim", "nama", "email"},
+			ok:     true,
+		},
+		{
+			name:   "default aliases with bom and spacing",
+			header: []string{"\ufeffNomor  Induk Mahasiswa", " Nama Lengkap ", "Prodi", "Tahun Wisuda", "No. HP", "Keterangan"},
+			want:   []string{"nim", "nama", "jurusan", "tahun_lulus", "no_telp", ""},
+			ok:     true,
+		},
+		{
+			name:    "custom mapping wins over alias",
+			mapping: Mapping{"NIM Baru": "nim", "Prodi": "alamat"},
+			header:  []string{"nim baru", "prodi"},
+			want:    []string{"nim", "alamat"},
+			ok:      true,
+		},
+		{name: "unknown field", mapping: Mapping{"Kolom": "gaji"}, header: []string{"nim"}},
+		{name: "duplicate field", header: []string{"nim", "NIM"}},
+		{name: "alias duplicates field", header: []string{"nim", "nama", "Nama Mahasiswa"}},
+		{name: "no nim", header: []string{"nama", "email"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.mapping.resolve(tt.header)
+			if !tt.ok {
+				if e := apperror.From(err); e.Kind != apperror.KindValidation || e.Code != "invalid_mapping" {
+					t.Errorf("err = %v, want invalid_mapping", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolve = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMapping(t *testing.T) {
+	tests := []struct {
+		pairs []string
+		want  Mapping
+		ok    bool
+	}{
+		{[]string{"Nama Lengkap=nama", " No HP = no_telp "}, Mapping{"Nama Lengkap": "nama", "No HP": "no_telp"}, true},
+		{[]string{"Kolom=a=b"}, Mapping{"Kolom": "a=b"}, true},
+		{nil, Mapping{}, true},
+		{[]string{"nama"}, nil, false},
+		{[]string{"=nama"}, nil, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseMapping(tt.pairs)
+		if ok := err == nil; ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseMapping(%q) = %v, %v; want %v, ok %v", tt.pairs, got, err, tt.want, tt.ok)
+		}
+	}
+}
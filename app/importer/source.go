@@ -0,0 +1,84 @@
+package importer
+
+import (
+	"Mango/app/apperror"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// FormatFromFilename menentukan format dari ekstensi file.
+func FormatFromFilename(name string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	default:
+		return "", fmt.Errorf("unsupported file type %q (use .csv or .xlsx)", filepath.Ext(name))
+	}
+}
+
+// ErrInvalidFile dikembalikan jika isi file tidak bisa dibaca sebagai
+// CSV atau XLSX; kesalahannya ada di file, bukan di server.
+func ErrInvalidFile(detail string) *apperror.Error {
+	return apperror.Validation("invalid_file", "File cannot be imported: "+detail,
+		apperror.FieldError{Field: "file", Code: "format", Message: detail})
+}
+
+// readRows membaca seluruh baris (termasuk header) dari sheet pertama
+// file XLSX atau dari file CSV. File yang rusak menghasilkan
+// ErrInvalidFile; error baca lainnya dikembalikan apa adanya.
+func readRows(r io.Reader, format Format) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		reader := csv.NewReader(r)
+		reader.TrimLeadingSpace = true
+		reader.FieldsPerRecord = -1
+		rows, err := reader.ReadAll()
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, ErrInvalidFile(parseErr.Error())
+		}
+		return rows, err
+
+	case FormatXLSX:
+		// excelize membaca seluruh isi dulu agar error bacanya terpisah
+		// dari error format workbook
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		f, err := excelize.OpenReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrInvalidFile("not a valid XLSX workbook")
+		}
+		defer f.Close()
+
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, ErrInvalidFile("workbook has no sheets")
+		}
+		rows, err := f.GetRows(sheets[0])
+		if err != nil {
+			return nil, ErrInvalidFile("first sheet cannot be read")
+		}
+		return rows, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
@@ -0,0 +1,89 @@
+package importer
+
+import (
+	"Mango/app/apperror"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func xlsxFile(t *testing.T, rows [][]string) []byte {
+	t.Helper()
+	f := excelize.NewFile()
+	defer f.Close()
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		values := make([]any, len(row))
+		for j, v := range row {
+			values[j] = v
+		}
+		if err := f.SetSheetRow("Sheet1", cell, &values); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadRows(t *testing.T) {
+	want := [][]string{{"NIM", "Nama"}, {"081911133001", "Budi"}}
+	tests := []struct {
+		name   string
+		format Format
+		data   []byte
+	}{
+		{"csv", FormatCSV, []byte("NIM, Nama\n081911133001,Budi\n")},
+		{"xlsx", FormatXLSX, xlsxFile(t, want)},
+	}
+	for _, tt := range tests {
+		rows, err := readRows(bytes.NewReader(tt.data), tt.format)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(rows, want) {
+			t.Errorf("%s: rows = %q, want %q", tt.name, rows, want)
+		}
+	}
+}
+
+func TestReadRowsErrors(t *testing.T) {
+	readFailure := errors.New("connection reset")
+	tests := []struct {
+		name    string
+		format  Format
+		r       func() *bytes.Reader
+		invalid bool // ErrInvalidFile; selain itu error server
+	}{
+		{"bad csv quote", FormatCSV, func() *bytes.Reader { return bytes.NewReader([]byte("nim,nama\n\"081911133001,Budi\n")) }, true},
+		{"not xlsx", FormatXLSX, func() *bytes.Reader { return bytes.NewReader([]byte("nim,nama\n")) }, true},
+	}
+	for _, tt := range tests {
+		_, err := readRows(tt.r(), tt.format)
+		if got := apperror.Is(err, apperror.KindValidation) && apperror.From(err).Code == "invalid_file"; got != tt.invalid {
+			t.Errorf("%s: err = %v, want invalid_file %v", tt.name, err, tt.invalid)
+		}
+	}
+
+	// Error baca dari sumber file bukan kesalahan client
+	for _, format := range []Format{FormatCSV, FormatXLSX} {
+		_, err := readRows(iotest.ErrReader(readFailure), format)
+		if !errors.Is(err, readFailure) || apperror.Is(err, apperror.KindValidation) {
+			t.Errorf("%s: read failure = %v, want the underlying error", format, err)
+		}
+	}
+}
+
+func TestImportEmptyFile(t *testing.T) {
+	_, err := (&AlumniImporter{}).Import(t.Context(), strings.NewReader(""), FormatCSV, Options{DryRun: true})
+	if e := apperror.From(err); e.Kind != apperror.KindValidation || e.Code != "invalid_file" {
+		t.Errorf("err = %v, want invalid_file", err)
+	}
+}
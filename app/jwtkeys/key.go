@@ -0,0 +1,162 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Algoritma tanda tangan yang didukung.
+const (
+	RS256 = "RS256"
+	ES256 = "ES256"
+)
+
+// Algorithms adalah nilai JWT_ALG yang diterima.
+var Algorithms = []string{RS256, ES256}
+
+// Key adalah satu key tanda tangan. ID-nya dipakai sebagai header kid dan
+// sama dengan nama file tanpa .pem.
+type Key struct {
+	ID      string
+	Alg     string
+	Created time.Time
+	signer  crypto.Signer
+}
+
+// Method adalah metode tanda tangan jwt untuk key ini.
+func (k *Key) Method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Alg)
+}
+
+// Public adalah public key untuk verifikasi.
+func (k *Key) Public() crypto.PublicKey {
+	return k.signer.Public()
+}
+
+var validID = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// createdHeader adalah header PEM yang menyimpan waktu pembuatan key.
+const createdHeader = "Created"
+
+// generate membuat key baru dengan algoritma alg. ID berisi tanggal
+// pembuatan dan akhiran acak.
+func generate(alg string, now time.Time) (*Key, error) {
+	var signer crypto.Signer
+	var err error
+	switch alg {
+	case RS256:
+		signer, err = rsa.GenerateKey(rand.Reader, 2048)
+	case ES256:
+		signer, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q, want one of %v", alg, Algorithms)
+	}
+	if err != nil {
+		return nil, err
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	id := fmt.Sprintf("%s-%s", now.UTC().Format("20060102"), hex.EncodeToString(suffix))
+	return &Key{ID: id, Alg: alg, Created: now.UTC(), signer: signer}, nil
+}
+
+// load membaca private key PKCS#8 dari file PEM. Waktu pembuatan dibaca
+// dari header Created, atau waktu modifikasi file jika tidak ada.
+func load(path string) (*Key, error) {
+	id := filepath.Base(path)
+	id = id[:len(id)-len(filepath.Ext(id))]
+	if !validID.MatchString(id) {
+		return nil, fmt.Errorf("%s: key id must match %s", path, validID)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("%s: want a PKCS#8 \"PRIVATE KEY\" PEM block", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	k := &Key{ID: id}
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("%s: RSA key must be at least 2048 bits", path)
+		}
+		k.Alg, k.signer = RS256, key
+	case *ecdsa.PrivateKey:
+		if key.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("%s: EC key must use P-256", path)
+		}
+		k.Alg, k.signer = ES256, key
+	default:
+		return nil, fmt.Errorf("%s: unsupported key type %T", path, parsed)
+	}
+
+	if v, ok := block.Headers[createdHeader]; ok {
+		if k.Created, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return nil, fmt.Errorf("%s: invalid %s header: %w", path, createdHeader, err)
+		}
+	} else {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		k.Created = info.ModTime().UTC()
+	}
+	return k, nil
+}
+
+// save menulis key ke dir/<id>.pem dengan izin 0600. File ditulis ke file
+// sementara lalu di-rename agar replica lain tidak membaca file setengah
+// jadi.
+func (k *Key) save(dir string) error {
+	der, err := x509.MarshalPKCS8PrivateKey(k.signer)
+	if err != nil {
+		return err
+	}
+	block := &pem.Block{
+		Type:    "PRIVATE KEY",
+		Headers: map[string]string{createdHeader: k.Created.Format(time.RFC3339Nano)},
+		Bytes:   der,
+	}
+
+	tmp, err := os.CreateTemp(dir, ".jwt-key-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := pem.Encode(tmp, block); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, k.ID+".pem"))
+}
@@ -0,0 +1,307 @@
+// Package jwtkeys menandatangani dan memverifikasi JWT dengan key
+// asimetris (RS256 atau ES256). Private key disimpan sebagai file PEM di
+// satu direktori, satu file per key; nama file adalah kid. Semua key di
+// direktori diterima untuk verifikasi, key terbaru dipakai untuk
+// menandatangani, dan public key-nya dipublikasikan lewat JWKS sehingga
+// aplikasi lain bisa memverifikasi token tanpa bisa membuatnya.
+package jwtkeys
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// reloadOnMiss adalah jarak minimal antar reload saat token memakai kid
+// yang belum dikenal (key baru dari replica lain).
+const reloadOnMiss = time.Minute
+
+// Set adalah kumpulan key dari satu direktori.
+type Set struct {
+	dir string
+	// Issuer diisi ke claim iss token baru dan diwajibkan untuk token
+	// yang punya kid.
+	Issuer string
+	// PublishDelay adalah waktu key baru hanya dipublikasikan di JWKS
+	// sebelum dipakai menandatangani, agar aplikasi lain sempat
+	// memperbarui cache JWKS-nya.
+	PublishDelay time.Duration
+	// LegacySecret dan LegacyUntil membuat token HS256 lama tanpa kid
+	// tetap diterima selama masa transisi, hanya jika keduanya diisi:
+	// token harus kedaluwarsa paling lambat LegacyUntil dan, jika punya
+	// iat, dibuat sebelum key asimetris pertama. Setelah LegacyUntil tidak
+	// ada token HS256 yang diterima.
+	LegacySecret []byte
+	LegacyUntil  time.Time
+
+	mu         sync.RWMutex
+	keys       []*Key // urut dari yang paling lama
+	lastReload time.Time
+}
+
+var current atomic.Pointer[Set]
+
+// Use menjadikan s set yang dipakai Sign dan Parse.
+func Use(s *Set) {
+	current.Store(s)
+}
+
+// Current mengembalikan set yang sedang dipakai.
+func Current() (*Set, error) {
+	s := current.Load()
+	if s == nil {
+		return nil, errors.New("JWT signing keys are not loaded")
+	}
+	return s, nil
+}
+
+// Open membaca semua key di dir. Direktori dibuat (0700) jika belum ada;
+// set boleh kosong sampai Generate dipanggil.
+func Open(dir string) (*Set, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	s := &Set{dir: dir}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload membaca ulang direktori key, misalnya setelah replica lain
+// membuat key baru.
+func (s *Set) Reload() error {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.pem"))
+	if err != nil {
+		return err
+	}
+	keys := make([]*Key, 0, len(paths))
+	for _, path := range paths {
+		k, err := load(path)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b *Key) int { return a.Created.Compare(b.Created) })
+
+	s.mu.Lock()
+	s.keys = keys
+	s.lastReload = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+// Keys mengembalikan semua key, dari yang paling lama.
+func (s *Set) Keys() []*Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.keys)
+}
+
+// Generate membuat dan menyimpan key baru dengan algoritma alg.
+func (s *Set) Generate(alg string) (*Key, error) {
+	k, err := generate(alg, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := k.save(s.dir); err != nil {
+		return nil, err
+	}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Signing mengembalikan key untuk menandatangani pada waktu now: key
+// terbaru yang sudah dipublikasikan selama PublishDelay, atau key
+// terbaru jika belum ada yang memenuhi.
+func (s *Set) Signing(now time.Time) (*Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.signing(now)
+}
+
+func (s *Set) signing(now time.Time) (*Key, error) {
+	if len(s.keys) == 0 {
+		return nil, fmt.Errorf("no JWT signing key in %s", s.dir)
+	}
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		if !s.keys[i].Created.Add(s.PublishDelay).After(now) {
+			return s.keys[i], nil
+		}
+	}
+	return s.keys[len(s.keys)-1], nil
+}
+
+// Lookup mencari key berdasarkan kid. Jika tidak ada, direktori dibaca
+// ulang (paling sering sekali per reloadOnMiss).
+func (s *Set) Lookup(kid string) (*Key, bool) {
+	if k, ok := s.lookup(kid); ok {
+		return k, true
+	}
+	s.mu.RLock()
+	stale := time.Since(s.lastReload) >= reloadOnMiss
+	s.mu.RUnlock()
+	if stale && s.Reload() == nil {
+		return s.lookup(kid)
+	}
+	return nil, false
+}
+
+func (s *Set) lookup(kid string) (*Key, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, k := range s.keys {
+		if k.ID == kid {
+			return k, true
+		}
+	}
+	return nil, false
+}
+
+// Sign menandatangani claims dengan key aktif dan menambahkan header kid
+// serta claim iss dan iat.
+func (s *Set) Sign(claims jwt.MapClaims) (string, error) {
+	now := time.Now()
+	k, err := s.Signing(now)
+	if err != nil {
+		return "", err
+	}
+	if s.Issuer != "" {
+		claims["iss"] = s.Issuer
+	}
+	claims["iat"] = now.Unix()
+
+	token := jwt.NewWithClaims(k.Method(), claims)
+	token.Header["kid"] = k.ID
+	return token.SignedString(k.signer)
+}
+
+// Parse memverifikasi token dan mengisi claims. Token harus punya kid
+// yang dikenal, algoritma yang sama dengan key-nya, exp dan iss yang
+// sesuai. Token HS256 tanpa kid hanya diterima selama masa transisi
+// (lihat LegacyUntil); token lama itu belum punya iss, jadi iss hanya
+// diperiksa jika ada.
+func (s *Set) Parse(tokenString string, claims jwt.MapClaims) (*jwt.Token, error) {
+	now := time.Now()
+	methods := slices.Clone(Algorithms)
+	if s.legacy(now) {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok && s.legacy(now) {
+				return s.LegacySecret, nil
+			}
+			return nil, errors.New("token has no kid")
+		}
+		k, ok := s.Lookup(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown kid %q", kid)
+		}
+		if token.Method.Alg() != k.Alg {
+			return nil, fmt.Errorf("kid %q expects %s", kid, k.Alg)
+		}
+		return k.Public(), nil
+	}, jwt.WithValidMethods(methods), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+
+	_, legacy := token.Method.(*jwt.SigningMethodHMAC)
+	if iss, ok := claims["iss"]; s.Issuer != "" && (ok || !legacy) && iss != s.Issuer {
+		return nil, errors.New("unexpected issuer")
+	}
+	if legacy {
+		if err := s.checkLegacy(claims); err != nil {
+			return nil, err
+		}
+	}
+	return token, nil
+}
+
+// legacy melaporkan apakah token HS256 lama masih diterima pada now.
+func (s *Set) legacy(now time.Time) bool {
+	return len(s.LegacySecret) > 0 && now.Before(s.LegacyUntil)
+}
+
+// checkLegacy menolak token HS256 yang berlaku melewati LegacyUntil atau
+// dibuat setelah rotasi ke key asimetris; token seperti itu tidak
+// mungkin dibuat server ini.
+func (s *Set) checkLegacy(claims jwt.MapClaims) error {
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil || exp.After(s.LegacyUntil) {
+		return errors.New("legacy token outlives the transition period")
+	}
+	iat, err := claims.GetIssuedAt()
+	if err != nil {
+		return err
+	}
+	if iat != nil && !iat.Before(s.rotatedAt()) {
+		return errors.New("legacy token issued after key rotation")
+	}
+	return nil
+}
+
+// rotatedAt adalah waktu key asimetris pertama dibuat, atau LegacyUntil
+// jika belum ada key.
+func (s *Set) rotatedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.keys) == 0 {
+		return s.LegacyUntil
+	}
+	return s.keys[0].Created
+}
+
+// Rotate membuat key baru dengan algoritma alg jika key terbaru sudah
+// berumur every, lalu menghapus key lama yang tidak mungkin lagi dipakai
+// token yang masih berlaku: key yang penerusnya sudah menandatangani
+// lebih lama dari tokenTTL. every 0 mematikan pembuatan key baru.
+func (s *Set) Rotate(alg string, every, tokenTTL time.Duration) (created *Key, removed []string, err error) {
+	if err := s.Reload(); err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+
+	keys := s.Keys()
+	if every > 0 && (len(keys) == 0 || !keys[len(keys)-1].Created.Add(every).After(now)) {
+		if created, err = s.Generate(alg); err != nil {
+			return nil, nil, err
+		}
+		keys = s.Keys()
+	}
+
+	signing, err := s.Signing(now)
+	if err != nil {
+		return created, nil, err
+	}
+	retireBefore := signing.Created.Add(s.PublishDelay + tokenTTL)
+	if retireBefore.After(now) {
+		return created, nil, nil
+	}
+	for _, k := range keys {
+		if !k.Created.Before(signing.Created) {
+			break
+		}
+		if err := os.Remove(filepath.Join(s.dir, k.ID+".pem")); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return created, removed, err
+		}
+		removed = append(removed, k.ID)
+	}
+	if len(removed) > 0 {
+		err = s.Reload()
+	}
+	return created, removed, err
+}
@@ -0,0 +1,90 @@
+package jwtkeys
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newTestSet(t *testing.T) *Set {
+	t.Helper()
+	s, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Generate(ES256); err != nil {
+		t.Fatal(err)
+	}
+	s.Issuer = "mango"
+	return s
+}
+
+func hs256(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestParseSigned(t *testing.T) {
+	s := newTestSet(t)
+	token, err := s.Sign(jwt.MapClaims{"sub": "u1", "exp": time.Now().Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := jwt.MapClaims{}
+	if _, err := s.Parse(token, claims); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if claims["sub"] != "u1" {
+		t.Errorf("sub = %v, want u1", claims["sub"])
+	}
+
+	s.Issuer = "other"
+	if _, err := s.Parse(token, jwt.MapClaims{}); err == nil {
+		t.Error("token from another issuer accepted")
+	}
+}
+
+func TestParseLegacy(t *testing.T) {
+	now := time.Now()
+	rotated := now.Add(-time.Hour)
+	exp := now.Add(time.Hour).Unix()
+
+	tests := []struct {
+		name   string
+		secret string        // LegacySecret
+		until  time.Duration // LegacyUntil relatif terhadap now; 0 berarti kosong
+		claims jwt.MapClaims
+		ok     bool
+	}{
+		{"within transition", "old", 2 * time.Hour, jwt.MapClaims{"sub": "u1", "exp": exp}, true},
+		{"issued before rotation", "old", 2 * time.Hour, jwt.MapClaims{"sub": "u1", "exp": exp, "iat": rotated.Add(-time.Minute).Unix()}, true},
+		{"matching issuer", "old", 2 * time.Hour, jwt.MapClaims{"sub": "u1", "exp": exp, "iss": "mango"}, true},
+		{"secret without cutoff", "old", 0, jwt.MapClaims{"sub": "u1", "exp": exp}, false},
+		{"cutoff passed", "old", -time.Minute, jwt.MapClaims{"sub": "u1", "exp": exp}, false},
+		{"outlives cutoff", "old", 30 * time.Minute, jwt.MapClaims{"sub": "u1", "exp": exp}, false},
+		{"issued after rotation", "old", 2 * time.Hour, jwt.MapClaims{"sub": "u1", "exp": exp, "iat": now.Unix()}, false},
+		{"other issuer", "old", 2 * time.Hour, jwt.MapClaims{"sub": "u1", "exp": exp, "iss": "evil"}, false},
+		{"no exp", "old", 2 * time.Hour, jwt.MapClaims{"sub": "u1"}, false},
+		{"no secret", "", 2 * time.Hour, jwt.MapClaims{"sub": "u1", "exp": exp}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSet(t)
+			s.keys[0].Created = rotated
+			s.LegacySecret = []byte(tt.secret)
+			if tt.until != 0 {
+				s.LegacyUntil = now.Add(tt.until)
+			}
+
+			_, err := s.Parse(hs256(t, "old", tt.claims), jwt.MapClaims{})
+			if ok := err == nil; ok != tt.ok {
+				t.Errorf("accepted = %v, want %v (err %v)", ok, tt.ok, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Package mail mengirim email transaksional (verifikasi email dan reset
+// password). Email dirender dari template per bahasa, diantrekan di
+// koleksi email_outbox, lalu dikirim lewat SMTP oleh Dispatcher di
+// background sehingga request tidak menunggu server SMTP dan email yang
+// gagal dicoba lagi.
+package mail
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// Message adalah satu email siap kirim. ID dipakai sebagai Message-ID
+// agar pengiriman ulang email yang sama bisa dikenali penerima.
+type Message struct {
+	ID      string
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender mengirim satu email.
+type Sender interface {
+	Send(ctx context.Context, m Message) error
+}
+
+// Locales adalah bahasa template yang tersedia; yang pertama adalah
+// default.
+var Locales = []string{"id", "en"}
+
+// MatchLocale memilih bahasa dari header Accept-Language (misalnya
+// "en-US,en;q=0.9"), atau fallback jika tidak ada yang tersedia.
+func MatchLocale(acceptLanguage, fallback string) string {
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, _, _ := strings.Cut(part, ";")
+		lang, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+		if lang = strings.ToLower(lang); slices.Contains(Locales, lang) {
+			return lang
+		}
+	}
+	return fallback
+}
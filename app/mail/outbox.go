@@ -0,0 +1,106 @@
+package mail
+
+import (
+	model "Mango/app/Model"
+	"Mango/app/repository"
+	"context"
+	"log"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Outbox merender email dan mengantrekannya di email_outbox.
+type Outbox struct {
+	repo          *repository.OutboxRepository
+	defaultLocale string
+}
+
+// NewOutbox membuat outbox dengan bahasa default defaultLocale (salah satu
+// Locales; kosong berarti Locales[0]).
+func NewOutbox(repo *repository.OutboxRepository, defaultLocale string) *Outbox {
+	if !slices.Contains(Locales, defaultLocale) {
+		defaultLocale = Locales[0]
+	}
+	return &Outbox{repo: repo, defaultLocale: defaultLocale}
+}
+
+// Locale memilih bahasa email dari header Accept-Language.
+func (o *Outbox) Locale(acceptLanguage string) string {
+	return MatchLocale(acceptLanguage, o.defaultLocale)
+}
+
+// Enqueue merender template name dalam bahasa locale dan mengantrekannya
+// untuk dikirim ke to.
+func (o *Outbox) Enqueue(ctx context.Context, userID primitive.ObjectID, to, name, locale string, data Data) error {
+	subject, body, err := Render(name, locale, data)
+	if err != nil {
+		return err
+	}
+	return o.repo.Enqueue(ctx, &model.OutboxEmail{
+		UserID:   userID,
+		To:       model.Secret(to),
+		Template: name,
+		Locale:   locale,
+		Subject:  subject,
+		Body:     model.Secret(body),
+	})
+}
+
+// Dispatcher mengirim email yang antre di outbox. Kegagalan sementara
+// dicoba lagi dengan jeda yang berlipat ganda; setelah MaxAttempts
+// percobaan, atau jika gagal permanen, email ditandai failed.
+type Dispatcher struct {
+	repo        *repository.OutboxRepository
+	sender      Sender
+	MaxAttempts int
+	// Lease adalah waktu sebelum email yang sedang dikirim boleh diambil
+	// pengirim lain (jika pengirim pertama berhenti di tengah jalan).
+	Lease time.Duration
+}
+
+func NewDispatcher(repo *repository.OutboxRepository, sender Sender) *Dispatcher {
+	return &Dispatcher{repo: repo, sender: sender, MaxAttempts: 8, Lease: 5 * time.Minute}
+}
+
+// Drain mengirim semua email yang sudah waktunya dikirim lalu
+// mengembalikan jumlah yang terkirim dan yang gagal.
+func (d *Dispatcher) Drain(ctx context.Context) (sent, failed int, err error) {
+	for {
+		e, err := d.repo.Claim(ctx, d.Lease)
+		if err != nil || e == nil {
+			return sent, failed, err
+		}
+
+		sendErr := d.sender.Send(ctx, Message{ID: e.ID.Hex(), To: string(e.To), Subject: e.Subject, Body: string(e.Body)})
+		if sendErr == nil {
+			sent++
+			if err := d.repo.MarkSent(ctx, e.ID); err != nil {
+				return sent, failed, err
+			}
+			continue
+		}
+
+		failed++
+		var retryAt *time.Time
+		if !Permanent(sendErr) && e.Attempts < d.MaxAttempts {
+			next := time.Now().Add(backoff(e.Attempts))
+			retryAt = &next
+		}
+		log.Printf("⚠️  Sending email %s (attempt %d) failed: %v", e.ID.Hex(), e.Attempts, sendErr)
+		if err := d.repo.MarkFailed(ctx, e.ID, sendErr.Error(), retryAt); err != nil {
+			return sent, failed, err
+		}
+	}
+}
+
+// backoff adalah jeda sebelum percobaan berikutnya: 1 menit, lalu dua
+// kali lipat sampai paling lama 1 jam.
+func backoff(attempts int) time.Duration {
+	d := time.Minute
+	for i := 1; i < attempts && d < time.Hour; i++ {
+		d *= 2
+	}
+	return min(d, time.Hour)
+}
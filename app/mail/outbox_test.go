@@ -0,0 +1,157 @@
+package mail
+
+import (
+	model "Mango/app/Model"
+	"Mango/app/repository"
+	"context"
+	"errors"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, time.Minute},
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{6, 32 * time.Minute},
+		{7, time.Hour},
+		{50, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"invalid address", permanentError{errors.New("invalid recipient")}, true},
+		{"smtp 550", &textproto.Error{Code: 550, Msg: "mailbox unavailable"}, true},
+		{"wrapped smtp 554", errors.Join(errors.New("send"), &textproto.Error{Code: 554}), true},
+		{"smtp 421", &textproto.Error{Code: 421, Msg: "try again later"}, false},
+		{"network", errors.New("connection refused"), false},
+	}
+	for _, tt := range tests {
+		if got := Permanent(tt.err); got != tt.want {
+			t.Errorf("Permanent(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// fakeSender mengembalikan err untuk setiap email dan mencatat yang
+// dikirim.
+type fakeSender struct {
+	err  error
+	sent []Message
+}
+
+func (f *fakeSender) Send(_ context.Context, m Message) error {
+	f.sent = append(f.sent, m)
+	return f.err
+}
+
+func claimResponse(id primitive.ObjectID, attempts int) bson.D {
+	doc := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "to", Value: "budi@example.com"},
+		{Key: "subject", Value: "Hi"},
+		{Key: "body", Value: "Hello\n"},
+		{Key: "status", Value: model.EmailPending},
+		{Key: "attempts", Value: attempts},
+	}
+	return bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: doc}}
+}
+
+var (
+	emptyQueue = bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}}
+	updated    = mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1})
+)
+
+func TestDrain(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	tests := []struct {
+		name     string
+		attempts int
+		sendErr  error
+		sent     int
+		failed   int
+		status   string // status yang di-$set setelah kirim
+		retry    bool   // next_attempt_at dijadwalkan ulang
+	}{
+		{name: "sent", attempts: 1, sent: 1, status: model.EmailSent},
+		{name: "temporary failure retried", attempts: 1, sendErr: &textproto.Error{Code: 421}, failed: 1, retry: true},
+		{name: "permanent failure", attempts: 1, sendErr: &textproto.Error{Code: 550}, failed: 1, status: model.EmailFailed},
+		{name: "out of attempts", attempts: 8, sendErr: errors.New("timeout"), failed: 1, status: model.EmailFailed},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			id := primitive.NewObjectID()
+			mt.AddMockResponses(claimResponse(id, tt.attempts), updated, emptyQueue)
+			sender := &fakeSender{err: tt.sendErr}
+			d := NewDispatcher(&repository.OutboxRepository{Col: mt.Coll}, sender)
+
+			sent, failed, err := d.Drain(context.Background())
+			if err != nil {
+				mt.Fatalf("Drain: %v", err)
+			}
+			if sent != tt.sent || failed != tt.failed {
+				mt.Errorf("Drain = %d sent, %d failed, want %d, %d", sent, failed, tt.sent, tt.failed)
+			}
+			if len(sender.sent) != 1 {
+				mt.Fatalf("sender got %d emails, want 1", len(sender.sent))
+			}
+			if m := sender.sent[0]; m.ID != id.Hex() || m.To != "budi@example.com" || m.Body != "Hello\n" {
+				mt.Errorf("message = %+v", m)
+			}
+
+			events := mt.GetAllStartedEvents()
+			if len(events) != 3 || events[1].CommandName != "update" {
+				mt.Fatalf("commands = %d, want claim, update, claim", len(events))
+			}
+			update := events[1].Command.Lookup("updates").Array().Index(0).Value().Document()
+			if got := update.Lookup("q", "_id").ObjectID(); got != id {
+				mt.Errorf("updated %v, want %v", got, id)
+			}
+			set := update.Lookup("u", "$set").Document()
+			if status, _ := set.Lookup("status").StringValueOK(); status != tt.status {
+				mt.Errorf("status = %q, want %q", status, tt.status)
+			}
+			next, ok := set.Lookup("next_attempt_at").TimeOK()
+			if ok != tt.retry {
+				mt.Errorf("next_attempt_at set = %v, want %v", ok, tt.retry)
+			}
+			if ok {
+				if wait := time.Until(next); wait <= 0 || wait > backoff(tt.attempts) {
+					mt.Errorf("retry in %v, want within %v", wait, backoff(tt.attempts))
+				}
+			}
+		})
+	}
+
+	mt.Run("empty queue", func(mt *mtest.T) {
+		mt.AddMockResponses(emptyQueue)
+		sender := &fakeSender{}
+		d := NewDispatcher(&repository.OutboxRepository{Col: mt.Coll}, sender)
+
+		sent, failed, err := d.Drain(context.Background())
+		if sent != 0 || failed != 0 || err != nil || len(sender.sent) != 0 {
+			mt.Errorf("Drain = %d, %d, %v, want nothing sent", sent, failed, err)
+		}
+	})
+}
@@ -0,0 +1,171 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strings"
+	"time"
+)
+
+// Config adalah pengaturan server SMTP. Host kosong berarti pengiriman
+// email mati dan email hanya menunggu di outbox.
+type Config struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// FromEnv membaca SMTP_HOST, SMTP_PORT (default 587), SMTP_USERNAME,
+// SMTP_PASSWORD dan MAIL_FROM. Untuk pengujian lokal arahkan ke SMTP
+// sink seperti MailHog atau Mailpit (SMTP_HOST=localhost SMTP_PORT=1025).
+func FromEnv() (Config, error) {
+	cfg := Config{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     os.Getenv("SMTP_PORT"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("MAIL_FROM"),
+	}
+	if cfg.Port == "" {
+		cfg.Port = "587"
+	}
+	if cfg.Host == "" {
+		return cfg, nil
+	}
+	if _, err := mail.ParseAddress(cfg.From); err != nil {
+		return Config{}, fmt.Errorf("MAIL_FROM: %q is not an email address", cfg.From)
+	}
+	return cfg, nil
+}
+
+// SMTPSender mengirim email lewat server SMTP. STARTTLS dipakai jika
+// server mendukungnya; login (AUTH PLAIN) hanya dilakukan lewat koneksi
+// terenkripsi atau ke localhost.
+type SMTPSender struct {
+	cfg     Config
+	timeout time.Duration
+}
+
+func NewSMTPSender(cfg Config) *SMTPSender {
+	return &SMTPSender{cfg: cfg, timeout: 30 * time.Second}
+}
+
+// permanentError menandai kegagalan yang tidak akan berhasil jika dicoba
+// lagi, misalnya alamat tujuan tidak valid.
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent melaporkan apakah err tidak perlu dicoba lagi: alamat tidak
+// valid atau balasan SMTP 5xx.
+func Permanent(err error) bool {
+	var p permanentError
+	if errors.As(err, &p) {
+		return true
+	}
+	var reply *textproto.Error
+	return errors.As(err, &reply) && reply.Code >= 500
+}
+
+func (s *SMTPSender) Send(ctx context.Context, m Message) error {
+	to, err := mail.ParseAddress(m.To)
+	if err != nil {
+		return permanentError{fmt.Errorf("invalid recipient: %w", err)}
+	}
+	from, err := mail.ParseAddress(s.cfg.From)
+	if err != nil {
+		return permanentError{fmt.Errorf("invalid sender: %w", err)}
+	}
+	msg, err := s.compose(m, from, to)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(s.cfg.Host, s.cfg.Port))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// compose menyusun email teks UTF-8 dengan header yang aman dari header
+// injection (alamat dan subject di-encode).
+func (s *SMTPSender) compose(m Message, from, to *mail.Address) ([]byte, error) {
+	domain := from.Address[strings.LastIndex(from.Address, "@")+1:]
+
+	var buf bytes.Buffer
+	header := func(k, v string) { fmt.Fprintf(&buf, "%s: %s\r\n", k, v) }
+	header("From", from.String())
+	header("To", to.String())
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	if m.ID != "" {
+		header("Message-ID", fmt.Sprintf("<%s@%s>", m.ID, domain))
+	}
+	header("MIME-Version", "1.0")
+	header("Content-Type", `text/plain; charset="utf-8"`)
+	header("Content-Transfer-Encoding", "quoted-printable")
+	buf.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(m.Body, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
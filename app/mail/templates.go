@@ -0,0 +1,70 @@
+package mail
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Nama template email.
+const (
+	TemplateVerifyEmail   = "verify_email"
+	TemplatePasswordReset = "password_reset"
+)
+
+// Data adalah isi yang tersedia di template.
+type Data struct {
+	Username string
+	Link     string
+	Expires  time.Time
+}
+
+// File template bernama <template>.<locale>.tmpl dan mendefinisikan
+// blok "subject" dan "body".
+//
+//go:embed templates/*.tmpl
+var files embed.FS
+
+var templates = map[string]*template.Template{}
+
+func init() {
+	for _, locale := range Locales {
+		for _, name := range []string{TemplateVerifyEmail, TemplatePasswordReset} {
+			file := fmt.Sprintf("templates/%s.%s.tmpl", name, locale)
+			t := template.New(file).Funcs(template.FuncMap{"date": dateFormatter(locale)})
+			templates[name+"."+locale] = template.Must(t.ParseFS(files, file))
+		}
+	}
+}
+
+// Render menghasilkan subject dan isi email template dalam bahasa locale.
+func Render(name, locale string, data Data) (subject, body string, err error) {
+	t, ok := templates[name+"."+locale]
+	if !ok {
+		return "", "", fmt.Errorf("mail template %s.%s not found", name, locale)
+	}
+	var s, b strings.Builder
+	if err := t.ExecuteTemplate(&s, "subject", data); err != nil {
+		return "", "", err
+	}
+	if err := t.ExecuteTemplate(&b, "body", data); err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(s.String()), strings.TrimSpace(b.String()) + "\n", nil
+}
+
+var bulan = [...]string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+// dateFormatter menulis tanggal dan jam sesuai kebiasaan bahasa locale.
+func dateFormatter(locale string) func(time.Time) string {
+	if locale == "id" {
+		return func(t time.Time) string {
+			return fmt.Sprintf("%d %s %d pukul %s", t.Day(), bulan[t.Month()-1], t.Year(), t.Format("15.04 MST"))
+		}
+	}
+	return func(t time.Time) string {
+		return t.Format("2 January 2006 at 15:04 MST")
+	}
+}
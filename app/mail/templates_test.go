@@ -0,0 +1,89 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*3600)
+	data := Data{
+		Username: "budi",
+		Link:     "https://mango.example/verify?token=abc",
+		Expires:  time.Date(2025, time.March, 7, 9, 5, 0, 0, wib),
+	}
+
+	tests := []struct {
+		name, locale string
+		subject      string
+		expires      string
+	}{
+		{TemplateVerifyEmail, "id", "Verifikasi email akun Mango Anda", "7 Maret 2025 pukul 09.05 WIB"},
+		{TemplateVerifyEmail, "en", "Verify the email for your Mango account", "7 March 2025 at 09:05 WIB"},
+		{TemplatePasswordReset, "id", "Reset password akun Mango Anda", "7 Maret 2025 pukul 09.05 WIB"},
+		{TemplatePasswordReset, "en", "Reset the password for your Mango account", "7 March 2025 at 09:05 WIB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+"."+tt.locale, func(t *testing.T) {
+			subject, body, err := Render(tt.name, tt.locale, data)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if subject != tt.subject {
+				t.Errorf("subject = %q, want %q", subject, tt.subject)
+			}
+			for _, want := range []string{data.Username, data.Link, tt.expires} {
+				if !strings.Contains(body, want) {
+					t.Errorf("body does not contain %q:\n%s", want, body)
+				}
+			}
+			if strings.HasPrefix(body, "\n") || !strings.HasSuffix(body, ".\n") {
+				t.Errorf("body is not trimmed to end with one newline: %q", body)
+			}
+		})
+	}
+}
+
+func TestRenderUnknown(t *testing.T) {
+	tests := []struct{ name, locale string }{
+		{"welcome", "id"},
+		{TemplateVerifyEmail, "fr"},
+		{TemplateVerifyEmail, ""},
+	}
+	for _, tt := range tests {
+		if _, _, err := Render(tt.name, tt.locale, Data{}); err == nil {
+			t.Errorf("Render(%q, %q) succeeded, want error", tt.name, tt.locale)
+		}
+	}
+}
+
+func TestRenderEscaping(t *testing.T) {
+	// email berupa teks biasa: nama tidak boleh di-escape seperti HTML
+	_, body, err := Render(TemplateVerifyEmail, "en", Data{Username: "O'Brien <ob>", Expires: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body, "Hello O'Brien <ob>,") {
+		t.Errorf("body = %q, want username unescaped", body)
+	}
+}
+
+func TestMatchLocale(t *testing.T) {
+	tests := []struct {
+		header, fallback, want string
+	}{
+		{"", "id", "id"},
+		{"", "en", "en"},
+		{"en-US,en;q=0.9", "id", "en"},
+		{"EN", "id", "en"},
+		{"fr-FR, id;q=0.8, en;q=0.5", "en", "id"},
+		{"fr, de", "id", "id"},
+		{"*", "en", "en"},
+	}
+	for _, tt := range tests {
+		if got := MatchLocale(tt.header, tt.fallback); got != tt.want {
+			t.Errorf("MatchLocale(%q, %q) = %q, want %q", tt.header, tt.fallback, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	migrationsCollection = "schema_migrations"
+	lockCollection       = "schema_migrations_lock"
+	lockID               = "migrate"
+
+	// Lock yang tidak diperbarui selama ini dianggap milik proses yang
+	// mati. Selama migration berjalan locked_at diperbarui setiap
+	// lockRefreshEvery.
+	staleLockAfter   = 15 * time.Minute
+	lockRefreshEvery = staleLockAfter / 3
+)
+
+var (
+	ErrLocked   = errors.New("another migration is in progress")
+	ErrLockLost = errors.New("migration lock was taken over by another process")
+)
+
+// Migration adalah satu langkah perubahan skema yang dijalankan sekali.
+// Version harus unik dan menentukan urutan eksekusi.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, db *mongo.Database) error
+	// Affected (opsional) menghitung dokumen yang akan diubah, untuk dry-run.
+	Affected func(ctx context.Context, db *mongo.Database) (int64, error)
+}
+
+// Record adalah dokumen di koleksi schema_migrations.
+type Record struct {
+	Version    int       `bson:"_id"`
+	Name       string    `bson:"name"`
+	AppliedAt  time.Time `bson:"applied_at"`
+	DurationMs int64     `bson:"duration_ms"`
+}
+
+// Status menggabungkan migration terdaftar dengan catatan penerapannya.
+type Status struct {
+	Migration Migration
+	Applied   *Record
+}
+
+type Runner struct {
+	db         *mongo.Database
+	migrations []Migration
+}
+
+func NewRunner(db *mongo.Database, migrations []Migration) (*Runner, error) {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Version == sorted[i-1].Version {
+			return nil, fmt.Errorf("duplicate migration version %d", sorted[i].Version)
+		}
+	}
+	return &Runner{db: db, migrations: sorted}, nil
+}
+
+// Status mengembalikan semua migration beserta status penerapannya.
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Status, 0, len(r.migrations))
+	for _, m := range r.migrations {
+		st := Status{Migration: m}
+		if rec, ok := applied[m.Version]; ok {
+			st.Applied = &rec
+		}
+		result = append(result, st)
+	}
+	return result, nil
+}
+
+// Pending mengembalikan migration yang belum diterapkan, berurutan.
+func (r *Runner) Pending(ctx context.Context) ([]Migration, error) {
+	statuses, err := r.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, st := range statuses {
+		if st.Applied == nil {
+			pending = append(pending, st.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up menerapkan semua migration yang belum dijalankan di bawah lock.
+// Berhenti pada error pertama; migration sebelumnya tetap tercatat.
+func (r *Runner) Up(ctx context.Context, logf func(format string, args ...any)) ([]Migration, error) {
+	l, err := r.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer l.release()
+
+	// Migration dibatalkan jika lock diambil alih proses lain
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	stop := l.keepAlive(ctx, cancel)
+	defer stop()
+
+	pending, err := r.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range pending {
+		logf("→ %04d %s", m.Version, m.Name)
+		start := time.Now()
+		if err := m.Up(ctx, r.db); err != nil {
+			if cause := context.Cause(ctx); cause != nil {
+				err = cause
+			}
+			return done, fmt.Errorf("migration %04d %s: %w", m.Version, m.Name, err)
+		}
+		if err := l.check(ctx); err != nil {
+			return done, fmt.Errorf("migration %04d %s: %w", m.Version, m.Name, err)
+		}
+
+		rec := Record{
+			Version:    m.Version,
+			Name:       m.Name,
+			AppliedAt:  time.Now(),
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if _, err := r.db.Collection(migrationsCollection).InsertOne(ctx, rec); err != nil {
+			return done, fmt.Errorf("record migration %04d: %w", m.Version, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+func (r *Runner) applied(ctx context.Context) (map[int]Record, error) {
+	cursor, err := r.db.Collection(migrationsCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	var records []Record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]Record, len(records))
+	for _, rec := range records {
+		applied[rec.Version] = rec
+	}
+	return applied, nil
+}
+
+// lease adalah lock migration yang sedang dipegang proses ini.
+type lease struct {
+	col   *mongo.Collection
+	owner string
+}
+
+func (l *lease) filter() bson.M {
+	return bson.M{"_id": lockID, "owner": l.owner}
+}
+
+// release melepas lock jika masih dipegang.
+func (l *lease) release() {
+	_, _ = l.col.DeleteOne(context.Background(), l.filter())
+}
+
+// check memastikan lock masih dipegang, sebelum hasil migration dicatat.
+func (l *lease) check(ctx context.Context) error {
+	n, err := l.col.CountDocuments(ctx, l.filter())
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// keepAlive memperbarui locked_at setiap lockRefreshEvery sampai stop
+// dipanggil, agar migration yang lama tidak dianggap basi. Jika lock
+// sudah diambil alih, cancel dipanggil dengan ErrLockLost.
+func (l *lease) keepAlive(ctx context.Context, cancel context.CancelCauseFunc) (stop func()) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(lockRefreshEvery)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				res, err := l.col.UpdateOne(ctx, l.filter(), bson.M{"$set": bson.M{"locked_at": time.Now()}})
+				// Error sementara dicoba lagi; lock baru basi setelah staleLockAfter
+				if err == nil && res.MatchedCount == 0 {
+					cancel(ErrLockLost)
+					return
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-exited
+	}
+}
+
+// lock mengambil lock global dengan insert dokumen ber-_id tetap.
+// Lock basi (proses mati) diambil alih setelah staleLockAfter.
+func (r *Runner) lock(ctx context.Context) (*lease, error) {
+	col := r.db.Collection(lockCollection)
+	host, _ := os.Hostname()
+	owner := fmt.Sprintf("%s:%d", host, os.Getpid())
+
+	for attempt := 0; attempt < 2; attempt++ {
+		_, err := col.InsertOne(ctx, bson.M{"_id": lockID, "owner": owner, "locked_at": time.Now()})
+		if err == nil {
+			return &lease{col: col, owner: owner}, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, err
+		}
+
+		res, err := col.DeleteOne(ctx, bson.M{"_id": lockID, "locked_at": bson.M{"$lt": time.Now().Add(-staleLockAfter)}})
+		if err != nil {
+			return nil, err
+		}
+		if res.DeletedCount == 0 {
+			break
+		}
+	}
+
+	var holder struct {
+		Owner    string    `bson:"owner"`
+		LockedAt time.Time `bson:"locked_at"`
+	}
+	_ = col.FindOne(ctx, bson.M{"_id": lockID}).Decode(&holder)
+	return nil, fmt.Errorf("%w (held by %s, last refreshed %s)", ErrLocked, holder.Owner, holder.LockedAt.Format(time.RFC3339))
+}
@@ -0,0 +1,239 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func versions(migrations []Migration) []int {
+	out := make([]int, len(migrations))
+	for i, m := range migrations {
+		out[i] = m.Version
+	}
+	return out
+}
+
+func TestNewRunner(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []int
+		want    []int
+		wantErr string
+	}{
+		{name: "empty", in: nil, want: []int{}},
+		{name: "already ordered", in: []int{1, 2, 3}, want: []int{1, 2, 3}},
+		{name: "sorted by version", in: []int{3, 1, 10, 2}, want: []int{1, 2, 3, 10}},
+		{name: "duplicate version", in: []int{1, 2, 1}, wantErr: "duplicate migration version 1"},
+		{name: "duplicate after sorting", in: []int{5, 3, 4, 3}, wantErr: "duplicate migration version 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := make([]Migration, len(tt.in))
+			for i, v := range tt.in {
+				in[i] = Migration{Version: v}
+			}
+			r, err := NewRunner(nil, in)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("NewRunner error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewRunner: %v", err)
+			}
+			if got := versions(r.migrations); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+			// slice milik pemanggil tidak boleh ikut terurut
+			for i, m := range in {
+				if m.Version != tt.in[i] {
+					t.Fatalf("input reordered to %v", versions(in))
+				}
+			}
+		})
+	}
+}
+
+func TestAll(t *testing.T) {
+	all := All()
+	if _, err := NewRunner(nil, all); err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]bool{}
+	for i, m := range all {
+		// versi baru ditambahkan di akhir dan tidak boleh melompat
+		if m.Version != i+1 {
+			t.Errorf("migration #%d has version %d, want %d", i, m.Version, i+1)
+		}
+		if m.Name == "" || names[m.Name] {
+			t.Errorf("migration %d has empty or duplicate name %q", m.Version, m.Name)
+		}
+		names[m.Name] = true
+		if m.Up == nil {
+			t.Errorf("migration %d has no Up", m.Version)
+		}
+	}
+}
+
+func appliedResponse(versions ...int) bson.D {
+	docs := make([]bson.D, len(versions))
+	for i, v := range versions {
+		docs[i] = bson.D{{Key: "_id", Value: v}, {Key: "name", Value: "m"}, {Key: "applied_at", Value: time.Now()}}
+	}
+	return mtest.CreateCursorResponse(0, "test."+migrationsCollection, mtest.FirstBatch, docs...)
+}
+
+// countResponse adalah balasan aggregate CountDocuments.
+func countResponse(n int) bson.D {
+	return mtest.CreateCursorResponse(0, "test."+lockCollection, mtest.FirstBatch, bson.D{{Key: "n", Value: n}})
+}
+
+func TestPending(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("skips applied versions in order", func(mt *mtest.T) {
+		mt.AddMockResponses(appliedResponse(3, 1))
+		r, err := NewRunner(mt.DB, []Migration{{Version: 4}, {Version: 3}, {Version: 2}, {Version: 1}})
+		if err != nil {
+			mt.Fatal(err)
+		}
+		pending, err := r.Pending(context.Background())
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if got := versions(pending); !reflect.DeepEqual(got, []int{2, 4}) {
+			mt.Errorf("Pending = %v, want [2 4]", got)
+		}
+	})
+}
+
+func TestUp(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("runs pending in order and records each", func(mt *mtest.T) {
+		var ran []int
+		step := func(v int) Migration {
+			return Migration{Version: v, Name: "step", Up: func(context.Context, *mongo.Database) error {
+				ran = append(ran, v)
+				return nil
+			}}
+		}
+		r, err := NewRunner(mt.DB, []Migration{step(3), step(1), step(2)})
+		if err != nil {
+			mt.Fatal(err)
+		}
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(), // insert lock
+			appliedResponse(1),
+			countResponse(1), mtest.CreateSuccessResponse(), // versi 2
+			countResponse(1), mtest.CreateSuccessResponse(), // versi 3
+			mtest.CreateSuccessResponse(), // release lock
+		)
+
+		done, err := r.Up(context.Background(), func(string, ...any) {})
+		if err != nil {
+			mt.Fatalf("Up: %v", err)
+		}
+		if got := versions(done); !reflect.DeepEqual(got, []int{2, 3}) {
+			mt.Errorf("done = %v, want [2 3]", got)
+		}
+		if !reflect.DeepEqual(ran, []int{2, 3}) {
+			mt.Errorf("ran = %v, want [2 3]", ran)
+		}
+
+		var recorded []int64
+		for _, ev := range mt.GetAllStartedEvents() {
+			if ev.CommandName == "insert" && ev.Command.Lookup("insert").StringValue() == migrationsCollection {
+				recorded = append(recorded, ev.Command.Lookup("documents").Array().Index(0).Value().Document().Lookup("_id").AsInt64())
+			}
+		}
+		if !reflect.DeepEqual(recorded, []int64{2, 3}) {
+			mt.Errorf("recorded = %v, want [2 3]", recorded)
+		}
+	})
+
+	mt.Run("stops at the first failure", func(mt *mtest.T) {
+		boom := errors.New("boom")
+		var ran []int
+		r, err := NewRunner(mt.DB, []Migration{
+			{Version: 1, Name: "ok", Up: func(context.Context, *mongo.Database) error { ran = append(ran, 1); return nil }},
+			{Version: 2, Name: "broken", Up: func(context.Context, *mongo.Database) error { ran = append(ran, 2); return boom }},
+			{Version: 3, Name: "never", Up: func(context.Context, *mongo.Database) error { ran = append(ran, 3); return nil }},
+		})
+		if err != nil {
+			mt.Fatal(err)
+		}
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(),
+			appliedResponse(),
+			countResponse(1), mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+		)
+
+		done, err := r.Up(context.Background(), func(string, ...any) {})
+		if !errors.Is(err, boom) || err.Error() != "migration 0002 broken: boom" {
+			mt.Errorf("Up error = %v, want migration 0002 broken: boom", err)
+		}
+		if got := versions(done); !reflect.DeepEqual(got, []int{1}) {
+			mt.Errorf("done = %v, want [1]", got)
+		}
+		if !reflect.DeepEqual(ran, []int{1, 2}) {
+			mt.Errorf("ran = %v, want [1 2]", ran)
+		}
+	})
+
+	mt.Run("lock lost before recording", func(mt *mtest.T) {
+		r, err := NewRunner(mt.DB, []Migration{
+			{Version: 1, Name: "slow", Up: func(context.Context, *mongo.Database) error { return nil }},
+		})
+		if err != nil {
+			mt.Fatal(err)
+		}
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(),
+			appliedResponse(),
+			countResponse(0),
+			mtest.CreateSuccessResponse(),
+		)
+
+		done, err := r.Up(context.Background(), func(string, ...any) {})
+		if !errors.Is(err, ErrLockLost) || len(done) != 0 {
+			mt.Errorf("Up = %v, %v, want ErrLockLost and nothing recorded", versions(done), err)
+		}
+	})
+
+	mt.Run("locked by another process", func(mt *mtest.T) {
+		r, err := NewRunner(mt.DB, []Migration{
+			{Version: 1, Name: "never", Up: func(context.Context, *mongo.Database) error {
+				mt.Error("migration ran without the lock")
+				return nil
+			}},
+		})
+		if err != nil {
+			mt.Fatal(err)
+		}
+		holder := bson.D{{Key: "_id", Value: lockID}, {Key: "owner", Value: "other:1"}, {Key: "locked_at", Value: time.Now()}}
+		mt.AddMockResponses(
+			mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "duplicate key"}),
+			bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}}, // lock belum basi
+			mtest.CreateCursorResponse(0, "test."+lockCollection, mtest.FirstBatch, holder),
+		)
+
+		_, err = r.Up(context.Background(), func(string, ...any) {})
+		if !errors.Is(err, ErrLocked) {
+			mt.Fatalf("Up error = %v, want ErrLocked", err)
+		}
+		if want := "held by other:1"; !strings.Contains(err.Error(), want) {
+			mt.Errorf("Up error = %v, want it to mention %q", err, want)
+		}
+	})
+}
@@ -0,0 +1,548 @@
+package migration
+
+import (
+	model "Mango/app/Model"
+	"Mango/app/crypt"
+	"Mango/app/repository"
+	"Mango/app/search"
+	"Mango/app/vocab"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// All berisi seluruh migration aplikasi. Tambahkan entri baru di akhir
+// dengan Version berikutnya; jangan ubah migration yang sudah dirilis.
+func All() []Migration {
+	return []Migration{
+		{
+			Version:  1,
+			Name:     "alumni_rename_update_at",
+			Up:       renameField("alumni", "Update_at", "updated_at"),
+			Affected: countWithField("alumni", "Update_at"),
+		},
+		{
+			Version:  2,
+			Name:     "pekerjaan_rename_updated_at",
+			Up:       renameField("pekerjaan_alumni", "Updated_at", "updated_at"),
+			Affected: countWithField("pekerjaan_alumni", "Updated_at"),
+		},
+		{
+			Version:  3,
+			Name:     "pekerjaan_unset_empty_optional_fields",
+			Up:       unsetEmptyStrings("pekerjaan_alumni", "lokasi_kerja", "gaji_range"),
+			Affected: countEmptyStrings("pekerjaan_alumni", "lokasi_kerja", "gaji_range"),
+		},
+		{
+			Version:  4,
+			Name:     "uploads_rename_userid",
+			Up:       renameField("uploads", "userid", "user_id"),
+			Affected: countWithField("uploads", "userid"),
+		},
+		{
+			Version:  5,
+			Name:     "pekerjaan_month_dates",
+			Up:       pekerjaanMonthDates,
+			Affected: countLegacyPekerjaanPeriods,
+		},
+		{
+			// gaji_range berupa teks bebas dan tidak bisa dipetakan ke
+			// gaji terstruktur secara otomatis; disimpan untuk ditinjau.
+			Version:  6,
+			Name:     "pekerjaan_keep_legacy_gaji_range",
+			Up:       renameField("pekerjaan_alumni", "gaji_range", "gaji_range_legacy"),
+			Affected: countWithField("pekerjaan_alumni", "gaji_range"),
+		},
+		{
+			Version:  7,
+			Name:     "pekerjaan_link_companies",
+			Up:       pekerjaanLinkCompanies,
+			Affected: countUnlinkedPekerjaan,
+		},
+		{
+			Version: 8,
+			Name:    "vocabularies_seed_kbli_provinsi",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return vocab.Seed(ctx, repository.NewVocabularyRepository(db))
+			},
+		},
+		{
+			// Nilai yang tidak cocok dibiarkan; lihat `vocab-map`
+			Version: 9,
+			Name:    "map_free_text_to_vocabularies",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				_, err := vocab.MapExisting(ctx, db)
+				return err
+			},
+		},
+		{
+			// Setelah ini text index dibuat oleh ensure-indexes
+			Version:  10,
+			Name:     "alumni_search_fields",
+			Up:       alumniSearchFields,
+			Affected: countAlumniWithoutSearch,
+		},
+		{
+			// Versi awal bertanggal updated_at (atau created_at); asOf
+			// sebelum waktu itu tidak bisa dijawab
+			Version:  11,
+			Name:     "alumni_baseline_revisions",
+			Up:       alumniBaselineRevisions,
+			Affected: countAlumniWithoutRevision,
+		},
+		{
+			// Membutuhkan keyring (KEYRING_FILE). Index lama di email dan
+			// text index yang memuat email dihapus; jalankan ensure-indexes
+			// setelahnya.
+			Version:  12,
+			Name:     "encrypt_sensitive_fields",
+			Up:       encryptSensitiveFields,
+			Affected: countPlaintextFields,
+		},
+		{
+			// alumni_id user yang bukan hasil SSO atau admin bisa diisi
+			// sendiri lewat /auth/register; tautan SSO lama dibuat ulang
+			// saat login berikutnya.
+			Version:  13,
+			Name:     "drop_unverified_alumni_links",
+			Up:       dropUnverifiedAlumniLinks,
+			Affected: countUnverifiedAlumniLinks,
+		},
+		{
+			// Password lama disimpan apa adanya; login hanya menerima
+			// hash bcrypt setelah migration ini.
+			Version:  14,
+			Name:     "hash_plaintext_passwords",
+			Up:       hashPlaintextPasswords,
+			Affected: countPlaintextPasswords,
+		},
+	}
+}
+
+// plaintextPassword cocok dengan user yang password-nya belum berupa
+// hash bcrypt ($2a$, $2b$ atau $2y$).
+var plaintextPassword = bson.M{"password": bson.M{"$gt": "", "$not": bson.M{"$regex": `^\$2[aby]\$`}}}
+
+func hashPlaintextPasswords(ctx context.Context, db *mongo.Database) error {
+	col := db.Collection("Users")
+	cursor, err := col.Find(ctx, plaintextPassword, options.Find().SetProjection(bson.M{"password": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var u model.User
+		if err := cursor.Decode(&u); err != nil {
+			return err
+		}
+		if model.IsPasswordHash(u.Password) {
+			continue
+		}
+		// bcrypt hanya memakai 72 byte pertama, baik saat hash maupun saat login
+		hash, err := model.HashPassword(truncate(u.Password, model.MaxPasswordLength))
+		if err != nil {
+			return err
+		}
+		// Filter password lama agar password yang baru diganti tidak ditimpa
+		filter := bson.M{"_id": u.ID, "password": u.Password}
+		if _, err := col.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"password": hash}}); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func countPlaintextPasswords(ctx context.Context, db *mongo.Database) (int64, error) {
+	return db.Collection("Users").CountDocuments(ctx, plaintextPassword)
+}
+
+// truncate memotong s menjadi paling banyak n byte.
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+var unverifiedAlumniLink = bson.M{"alumni_id": bson.M{"$exists": true}, "alumni_link": bson.M{"$exists": false}}
+
+func dropUnverifiedAlumniLinks(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("Users").UpdateMany(ctx, unverifiedAlumniLink, bson.M{"$unset": bson.M{"alumni_id": ""}})
+	return err
+}
+
+func countUnverifiedAlumniLinks(ctx context.Context, db *mongo.Database) (int64, error) {
+	return db.Collection("Users").CountDocuments(ctx, unverifiedAlumniLink)
+}
+
+// renameField memindahkan nilai field lama ke field baru. Jika dokumen
+// sudah punya keduanya, nilai yang lebih besar (paling baru) dipakai.
+func renameField(collection, from, to string) func(context.Context, *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		pipeline := mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{to: bson.M{"$max": bson.A{"$" + from, "$" + to}}}}},
+			{{Key: "$unset", Value: from}},
+		}
+		_, err := db.Collection(collection).UpdateMany(ctx, bson.M{from: bson.M{"$exists": true}}, pipeline)
+		return err
+	}
+}
+
+func countWithField(collection, field string) func(context.Context, *mongo.Database) (int64, error) {
+	return func(ctx context.Context, db *mongo.Database) (int64, error) {
+		return db.Collection(collection).CountDocuments(ctx, bson.M{field: bson.M{"$exists": true}})
+	}
+}
+
+// unsetEmptyStrings menghapus field opsional yang tersimpan sebagai ""
+// karena tag omitempty sebelumnya tidak terbaca.
+func unsetEmptyStrings(collection string, fields ...string) func(context.Context, *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		for _, f := range fields {
+			_, err := db.Collection(collection).UpdateMany(ctx, bson.M{f: ""}, bson.M{"$unset": bson.M{f: ""}})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func countEmptyStrings(collection string, fields ...string) func(context.Context, *mongo.Database) (int64, error) {
+	return func(ctx context.Context, db *mongo.Database) (int64, error) {
+		or := bson.A{}
+		for _, f := range fields {
+			or = append(or, bson.M{f: ""})
+		}
+		return db.Collection(collection).CountDocuments(ctx, bson.M{"$or": or})
+	}
+}
+
+// pekerjaanMonthDates mengubah tahun (int) menjadi date dengan presisi
+// bulan: tanggal_kerja menjadi Januari dan tanggal_selesai menjadi
+// Desember tahun itu; tanggal_selesai 0 dihapus. Status kosong diisi
+// "ended" atau "current" sesuai tanggal_selesai.
+func pekerjaanMonthDates(ctx context.Context, db *mongo.Database) error {
+	col := db.Collection("pekerjaan_alumni")
+	toDate := func(field string, month int) bson.M {
+		return bson.M{"$dateFromParts": bson.M{"year": bson.M{"$toInt": "$" + field}, "month": month}}
+	}
+
+	steps := []struct {
+		filter bson.M
+		update any
+	}{
+		{
+			bson.M{"tanggal_kerja": bson.M{"$type": "number", "$gt": 0}},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{"tanggal_kerja": toDate("tanggal_kerja", 1)}}}},
+		},
+		{
+			bson.M{"tanggal_kerja": bson.M{"$type": "number", "$lte": 0}},
+			bson.M{"$unset": bson.M{"tanggal_kerja": ""}},
+		},
+		{
+			bson.M{"tanggal_selesai": bson.M{"$type": "number", "$gt": 0}},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{"tanggal_selesai": toDate("tanggal_selesai", 12)}}}},
+		},
+		{
+			bson.M{"tanggal_selesai": bson.M{"$type": "number", "$lte": 0}},
+			bson.M{"$unset": bson.M{"tanggal_selesai": ""}},
+		},
+		{
+			bson.M{"status": bson.M{"$in": bson.A{"", nil}}},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{"status": bson.M{"$cond": bson.A{
+				bson.M{"$and": bson.A{
+					bson.M{"$eq": bson.A{bson.M{"$type": "$tanggal_selesai"}, "date"}},
+					bson.M{"$lt": bson.A{"$tanggal_selesai", model.CurrentMonth().Time}},
+				}},
+				model.StatusEnded,
+				model.StatusCurrent,
+			}}}}}},
+		},
+	}
+	for _, step := range steps {
+		if _, err := col.UpdateMany(ctx, step.filter, step.update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func countLegacyPekerjaanPeriods(ctx context.Context, db *mongo.Database) (int64, error) {
+	return db.Collection("pekerjaan_alumni").CountDocuments(ctx, bson.M{"$or": bson.A{
+		bson.M{"tanggal_kerja": bson.M{"$type": "number"}},
+		bson.M{"tanggal_selesai": bson.M{"$type": "number"}},
+		bson.M{"status": bson.M{"$in": bson.A{"", nil}}},
+	}})
+}
+
+var unlinkedPekerjaan = bson.M{"company_id": bson.M{"$exists": false}, "nama_perusahaan": bson.M{"$gt": ""}}
+
+// pekerjaanLinkCompanies membuat perusahaan dari nama_perusahaan yang
+// belum tertaut lalu mengisi company_id. Ejaan dengan key yang sama
+// ("PT Telkom", "telkom") menjadi satu perusahaan dengan ejaan terbanyak
+// sebagai nama; duplikat lain ("Telkom Indonesia") disatukan admin lewat
+// merge.
+func pekerjaanLinkCompanies(ctx context.Context, db *mongo.Database) error {
+	jobs := db.Collection("pekerjaan_alumni")
+	companies := db.Collection("companies")
+
+	cursor, err := jobs.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: unlinkedPekerjaan}},
+		{{Key: "$group", Value: bson.M{"_id": "$nama_perusahaan", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+	})
+	if err != nil {
+		return err
+	}
+	var names []struct {
+		Name string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &names); err != nil {
+		return err
+	}
+
+	spellings := map[string][]string{}
+	var keys []string
+	for _, n := range names {
+		key := model.CompanyKey(n.Name)
+		if key == "" {
+			continue
+		}
+		if _, ok := spellings[key]; !ok {
+			keys = append(keys, key)
+		}
+		spellings[key] = append(spellings[key], n.Name)
+	}
+
+	for _, key := range keys {
+		var company model.Company
+		err := companies.FindOne(ctx, bson.M{"$or": bson.A{bson.M{"name_key": key}, bson.M{"alias_keys": key}}}).Decode(&company)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			now := time.Now().Unix()
+			company = model.Company{ID: primitive.NewObjectID(), Name: spellings[key][0], CreatedAt: now, UpdatedAt: now}
+			company.SetKeys()
+			_, err = companies.InsertOne(ctx, company)
+		}
+		if err != nil {
+			return err
+		}
+
+		filter := bson.M{"company_id": bson.M{"$exists": false}, "nama_perusahaan": bson.M{"$in": spellings[key]}}
+		if _, err := jobs.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"company_id": company.ID}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func countUnlinkedPekerjaan(ctx context.Context, db *mongo.Database) (int64, error) {
+	return db.Collection("pekerjaan_alumni").CountDocuments(ctx, unlinkedPekerjaan)
+}
+
+// alumniSearchFields mengisi field search setiap alumni: kata nama untuk
+// pencarian salah ketik serta perusahaan dan jabatan dari riwayat
+// pekerjaan. Aman dijalankan ulang.
+func alumniSearchFields(ctx context.Context, db *mongo.Database) error {
+	col := db.Collection("alumni")
+	cursor, err := col.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"nama": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var a model.Alumni
+		if err := cursor.Decode(&a); err != nil {
+			return err
+		}
+		if _, err := col.UpdateByID(ctx, a.ID, bson.M{"$set": bson.M{"search.name_keys": search.Keys(a.Nama)}}); err != nil {
+			return err
+		}
+		if err := repository.SyncAlumniSearch(ctx, db, a.ID); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func countAlumniWithoutSearch(ctx context.Context, db *mongo.Database) (int64, error) {
+	return db.Collection("alumni").CountDocuments(ctx, bson.M{"search.name_keys": bson.M{"$exists": false}})
+}
+
+func alumniBaselineRevisions(ctx context.Context, db *mongo.Database) error {
+	col := db.Collection("alumni")
+	revisions := repository.NewAlumniRevisionRepository(db).Col
+	cursor, err := col.Find(ctx, bson.M{"revision": bson.M{"$exists": false}})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var a model.Alumni
+		if err := cursor.Decode(&a); err != nil {
+			return err
+		}
+		a.Revision = 1
+		a.Search = nil
+		at := max(a.UpdatedAt, a.CreatedAt)
+		rev := model.AlumniRevision{
+			ID:       primitive.NewObjectID(),
+			AlumniID: a.ID,
+			Version:  1,
+			At:       time.Unix(at, 0).UTC(),
+			Action:   model.AuditCreate,
+			Snapshot: a,
+		}
+		if _, err := revisions.InsertOne(ctx, rev); err != nil && !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+		if _, err := col.UpdateByID(ctx, a.ID, bson.M{"$set": bson.M{"revision": 1}}); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func countAlumniWithoutRevision(ctx context.Context, db *mongo.Database) (int64, error) {
+	return db.Collection("alumni").CountDocuments(ctx, bson.M{"revision": bson.M{"$exists": false}})
+}
+
+// plaintext berisi koleksi dan kondisi dokumen yang field sensitifnya
+// belum terenkripsi.
+var plaintext = []struct {
+	collection string
+	filter     bson.M
+}{
+	{"alumni", anyString("email", "no_telp", "alamat")},
+	{"Users", anyString("email")},
+	{"pekerjaan_alumni", bson.M{"gaji": bson.M{"$type": "object"}}},
+	{"alumni_revisions", anyString("snapshot.email", "snapshot.no_telp", "snapshot.alamat")},
+}
+
+func anyString(fields ...string) bson.M {
+	or := bson.A{}
+	for _, f := range fields {
+		or = append(or, bson.M{f: bson.M{"$type": "string", "$gt": ""}})
+	}
+	return bson.M{"$or": or}
+}
+
+// encryptSensitiveFields mengenkripsi email, telepon, alamat dan gaji yang
+// masih plaintext serta mengisi blind index email. Dokumen dibaca dengan
+// model (yang menerima plaintext) lalu field-nya ditulis ulang sehingga
+// terenkripsi. Nilai field tersebut di audit log disamarkan. Aman
+// dijalankan ulang.
+func encryptSensitiveFields(ctx context.Context, db *mongo.Database) error {
+	for _, p := range plaintext {
+		col := db.Collection(p.collection)
+		cursor, err := col.Find(ctx, p.filter)
+		if err != nil {
+			return err
+		}
+
+		for cursor.Next(ctx) {
+			var id primitive.ObjectID
+			var set bson.M
+			switch p.collection {
+			case "alumni":
+				var a model.Alumni
+				if err := cursor.Decode(&a); err != nil {
+					return err
+				}
+				id = a.ID
+				set = bson.M{"email": a.Email, "no_telp": a.No_telp, "alamat": a.Alamat, "email_bidx": crypt.EmailIndex(string(a.Email))}
+			case "Users":
+				var u model.User
+				if err := cursor.Decode(&u); err != nil {
+					return err
+				}
+				id = u.ID
+				set = bson.M{"email": u.Email, "email_bidx": crypt.EmailIndex(string(u.Email))}
+			case "pekerjaan_alumni":
+				var j model.Pekerjaan
+				if err := cursor.Decode(&j); err != nil {
+					return err
+				}
+				id = j.ID
+				set = bson.M{"gaji": j.Gaji}
+			case "alumni_revisions":
+				var rev model.AlumniRevision
+				if err := cursor.Decode(&rev); err != nil {
+					return err
+				}
+				id = rev.ID
+				set = bson.M{"snapshot": rev.Snapshot}
+			}
+			if _, err := col.UpdateByID(ctx, id, bson.M{"$set": set}); err != nil {
+				cursor.Close(ctx)
+				return err
+			}
+		}
+		err = cursor.Err()
+		cursor.Close(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := maskAuditValues(ctx, db, "email", "no_telp", "alamat", "gaji.min", "gaji.max", "gaji.currency", "gaji.period"); err != nil {
+		return err
+	}
+	if err := dropIndexes(ctx, db.Collection("alumni"), "email", "alumni_text"); err != nil {
+		return err
+	}
+	return dropIndexes(ctx, db.Collection("Users"), "email_unique")
+}
+
+func countPlaintextFields(ctx context.Context, db *mongo.Database) (int64, error) {
+	var total int64
+	for _, p := range plaintext {
+		n, err := db.Collection(p.collection).CountDocuments(ctx, p.filter)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// maskAuditValues mengganti nilai before/after field di audit log dengan
+// penanda tersamar, seperti yang dilakukan audit.Diff untuk password.
+func maskAuditValues(ctx context.Context, db *mongo.Database, fields ...string) error {
+	col := db.Collection("audit_log")
+	for _, side := range []string{"before", "after"} {
+		opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: bson.A{
+			bson.M{"c.field": bson.M{"$in": fields}, "c." + side: bson.M{"$nin": bson.A{nil, "***"}}},
+		}})
+		_, err := col.UpdateMany(ctx, bson.M{"changes.field": bson.M{"$in": fields}},
+			bson.M{"$set": bson.M{"changes.$[c]." + side: "***"}}, opts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropIndexes menghapus index yang sudah tidak dipakai; index yang tidak
+// ada dilewati.
+func dropIndexes(ctx context.Context, col *mongo.Collection, names ...string) error {
+	for _, name := range names {
+		_, err := col.Indexes().DropOne(ctx, name)
+		var cmdErr mongo.CommandError
+		if errors.As(err, &cmdErr) && (cmdErr.Name == "IndexNotFound" || cmdErr.Name == "NamespaceNotFound") {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,362 @@
+// Package oidctest adalah identity provider OIDC tiruan untuk development
+// dan pengujian lokal SSO tanpa identity provider universitas. Provider
+// ini mendukung discovery, authorization code flow dengan PKCE S256 dan
+// JWKS; user dipilih atau diketik di halaman login tanpa password.
+package oidctest
+
+import (
+	"Mango/app/jwtkeys"
+	"Mango/app/oidc"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	codeTTL    = time.Minute
+	idTokenTTL = 5 * time.Minute
+)
+
+// Identity adalah user di provider tiruan beserta claim-nya.
+type Identity struct {
+	Subject           string   `json:"sub"`
+	Email             string   `json:"email,omitempty"`
+	EmailVerified     bool     `json:"email_verified"`
+	Name              string   `json:"name,omitempty"`
+	PreferredUsername string   `json:"preferred_username,omitempty"`
+	NIM               string   `json:"nim,omitempty"`
+	Groups            []string `json:"groups,omitempty"`
+}
+
+// Server adalah provider tiruan; semua endpoint berada di bawah Issuer.
+type Server struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string // kosong berarti client publik (tanpa secret)
+	// Users ditampilkan sebagai pilihan di halaman login
+	Users []Identity
+
+	keys *jwtkeys.Set
+	mux  *http.ServeMux
+
+	mu    sync.Mutex
+	codes map[string]grant
+}
+
+// grant adalah authorization code yang belum ditukar.
+type grant struct {
+	identity    Identity
+	redirectURI string
+	challenge   string
+	nonce       string
+	expires     time.Time
+}
+
+// New membuat provider dengan key tanda tangan di keyDir (dibuat jika
+// belum ada).
+func New(issuer, clientID, clientSecret, keyDir string) (*Server, error) {
+	keys, err := jwtkeys.Open(keyDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys.Keys()) == 0 {
+		if _, err := keys.Generate(jwtkeys.RS256); err != nil {
+			return nil, err
+		}
+	}
+	keys.Issuer = issuer
+
+	s := &Server{
+		Issuer:       issuer,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		keys:         keys,
+		codes:        map[string]grant{},
+	}
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("GET /.well-known/openid-configuration", s.discovery)
+	s.mux.HandleFunc("GET /authorize", s.authorizeForm)
+	s.mux.HandleFunc("POST /authorize", s.authorize)
+	s.mux.HandleFunc("POST /token", s.token)
+	s.mux.HandleFunc("GET /jwks", s.jwks)
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Issuer boleh memakai path, misalnya http://localhost:9000/realms/unair
+	if path, err := url.Parse(s.Issuer); err == nil && path.Path != "" {
+		http.StripPrefix(strings.TrimSuffix(path.Path, "/"), s.mux).ServeHTTP(w, r)
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) endpoint(path string) string {
+	return strings.TrimSuffix(s.Issuer, "/") + path
+}
+
+func (s *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                s.Issuer,
+		"authorization_endpoint":                s.endpoint("/authorize"),
+		"token_endpoint":                        s.endpoint("/token"),
+		"jwks_uri":                              s.endpoint("/jwks"),
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{jwtkeys.RS256},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"grant_types_supported":                 []string{"authorization_code"},
+		"claims_supported":                      []string{"sub", "email", "email_verified", "name", "preferred_username", "nim", "groups"},
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.keys.JWKS())
+}
+
+var loginPage = template.Must(template.New("login").Parse(`<!doctype html>
+<html><head><meta charset="utf-8"><title>Mock OIDC login</title></head>
+<body style="font-family: sans-serif; max-width: 32em; margin: 2em auto">
+<h1>Mock OIDC login</h1>
+<p>Client <code>{{.Query.client_id}}</code> meminta login. Tidak ada password.</p>
+{{range $i, $u := .Users}}
+<form method="post">{{template "hidden" $.Query}}<input type="hidden" name="user" value="{{$i}}">
+<button type="submit">{{$u.PreferredUsername}} ({{$u.Email}}{{if $u.Groups}}; {{range $u.Groups}}{{.}} {{end}}{{end}})</button>
+</form>
+{{end}}
+<h2>User lain</h2>
+<form method="post">{{template "hidden" .Query}}
+<p><label>sub <input name="sub" required></label></p>
+<p><label>email <input name="email" type="email"></label>
+<label><input type="checkbox" name="email_verified" value="true" checked> email_verified</label></p>
+<p><label>name <input name="name"></label></p>
+<p><label>preferred_username <input name="preferred_username"></label></p>
+<p><label>nim <input name="nim"></label></p>
+<p><label>groups (dipisah koma) <input name="groups"></label></p>
+<button type="submit">Login</button>
+<button type="submit" name="deny" value="1">Tolak</button>
+</form>
+</body></html>
+{{define "hidden"}}{{range $k, $v := .}}<input type="hidden" name="{{$k}}" value="{{$v}}">{{end}}{{end}}`))
+
+// authParams adalah parameter authorization request yang dibawa dari
+// halaman login ke POST /authorize.
+var authParams = []string{"response_type", "client_id", "redirect_uri", "scope", "state", "nonce", "code_challenge", "code_challenge_method"}
+
+func (s *Server) authorizeForm(w http.ResponseWriter, r *http.Request) {
+	query := map[string]string{}
+	for _, k := range authParams {
+		query[k] = r.URL.Query().Get(k)
+	}
+	if msg := s.checkAuthRequest(query); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = loginPage.Execute(w, map[string]interface{}{"Query": query, "Users": s.Users})
+}
+
+// checkAuthRequest memeriksa authorization request; PKCE S256 wajib
+// agar client yang lupa mengirimnya ketahuan saat pengujian.
+func (s *Server) checkAuthRequest(q map[string]string) string {
+	switch {
+	case q["response_type"] != "code":
+		return "response_type must be code"
+	case q["client_id"] != s.ClientID:
+		return "unknown client_id"
+	case q["redirect_uri"] == "":
+		return "redirect_uri is required"
+	case !strings.Contains(" "+q["scope"]+" ", " openid "):
+		return "scope must include openid"
+	case q["code_challenge"] == "" || q["code_challenge_method"] != "S256":
+		return "PKCE with code_challenge_method=S256 is required"
+	}
+	return ""
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query := map[string]string{}
+	for _, k := range authParams {
+		query[k] = r.PostForm.Get(k)
+	}
+	if msg := s.checkAuthRequest(query); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	back := url.Values{}
+	if query["state"] != "" {
+		back.Set("state", query["state"])
+	}
+
+	if r.PostForm.Get("deny") != "" {
+		back.Set("error", "access_denied")
+		http.Redirect(w, r, withQuery(query["redirect_uri"], back), http.StatusFound)
+		return
+	}
+
+	identity, ok := s.identityFromForm(r)
+	if !ok {
+		http.Error(w, "sub is required", http.StatusBadRequest)
+		return
+	}
+	code := randomString()
+	s.mu.Lock()
+	for c, g := range s.codes {
+		if time.Now().After(g.expires) {
+			delete(s.codes, c)
+		}
+	}
+	s.codes[code] = grant{
+		identity:    identity,
+		redirectURI: query["redirect_uri"],
+		challenge:   query["code_challenge"],
+		nonce:       query["nonce"],
+		expires:     time.Now().Add(codeTTL),
+	}
+	s.mu.Unlock()
+
+	back.Set("code", code)
+	http.Redirect(w, r, withQuery(query["redirect_uri"], back), http.StatusFound)
+}
+
+// identityFromForm membaca user pilihan (field user) atau isian form.
+func (s *Server) identityFromForm(r *http.Request) (Identity, bool) {
+	if i := r.PostForm.Get("user"); i != "" {
+		n, err := strconv.Atoi(i)
+		if err != nil || n < 0 || n >= len(s.Users) {
+			return Identity{}, false
+		}
+		return s.Users[n], true
+	}
+	identity := Identity{
+		Subject:           strings.TrimSpace(r.PostForm.Get("sub")),
+		Email:             strings.TrimSpace(r.PostForm.Get("email")),
+		EmailVerified:     r.PostForm.Get("email_verified") == "true",
+		Name:              strings.TrimSpace(r.PostForm.Get("name")),
+		PreferredUsername: strings.TrimSpace(r.PostForm.Get("preferred_username")),
+		NIM:               strings.TrimSpace(r.PostForm.Get("nim")),
+	}
+	for _, g := range strings.Split(r.PostForm.Get("groups"), ",") {
+		if g = strings.TrimSpace(g); g != "" {
+			identity.Groups = append(identity.Groups, g)
+		}
+	}
+	return identity, identity.Subject != ""
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request", err.Error())
+		return
+	}
+	if !s.authenticateClient(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="mock-oidc"`)
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type", "only authorization_code is supported")
+		return
+	}
+
+	// Code hanya bisa ditukar sekali
+	s.mu.Lock()
+	g, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	s.mu.Unlock()
+	switch {
+	case !ok || time.Now().After(g.expires):
+		tokenError(w, "invalid_grant", "unknown or expired code")
+		return
+	case r.PostForm.Get("redirect_uri") != g.redirectURI:
+		tokenError(w, "invalid_grant", "redirect_uri does not match the authorization request")
+		return
+	case oidc.Challenge(r.PostForm.Get("code_verifier")) != g.challenge:
+		tokenError(w, "invalid_grant", "code_verifier does not match code_challenge")
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"sub":            g.identity.Subject,
+		"aud":            s.ClientID,
+		"exp":            time.Now().Add(idTokenTTL).Unix(),
+		"email_verified": g.identity.EmailVerified,
+	}
+	for k, v := range map[string]string{
+		"nonce":              g.nonce,
+		"email":              g.identity.Email,
+		"name":               g.identity.Name,
+		"preferred_username": g.identity.PreferredUsername,
+		"nim":                g.identity.NIM,
+	} {
+		if v != "" {
+			claims[k] = v
+		}
+	}
+	if len(g.identity.Groups) > 0 {
+		claims["groups"] = g.identity.Groups
+	}
+	idToken, err := s.keys.Sign(claims)
+	if err != nil {
+		tokenError(w, "server_error", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   int(idTokenTTL.Seconds()),
+		"id_token":     idToken,
+	})
+}
+
+// authenticateClient menerima client_secret_basic, client_secret_post,
+// atau hanya client_id untuk client publik.
+func (s *Server) authenticateClient(r *http.Request) bool {
+	id, secret, basic := r.BasicAuth()
+	if basic {
+		id, _ = url.QueryUnescape(id)
+		secret, _ = url.QueryUnescape(secret)
+	} else {
+		id, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	return id == s.ClientID && subtle.ConstantTimeCompare([]byte(secret), []byte(s.ClientSecret)) == 1
+}
+
+func tokenError(w http.ResponseWriter, code, description string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code, "error_description": description})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func withQuery(rawURL string, q url.Values) string {
+	sep := "?"
+	if strings.Contains(rawURL, "?") {
+		sep = "&"
+	}
+	return rawURL + sep + q.Encode()
+}
+
+func randomString() string {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
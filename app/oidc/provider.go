@@ -0,0 +1,275 @@
+package oidc
+
+import (
+	"Mango/app/jwtkeys"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// metadataTTL adalah lama discovery document di-cache.
+	metadataTTL = time.Hour
+	// reloadOnMiss adalah jarak minimal antar pengambilan ulang JWKS saat
+	// ID token memakai kid yang belum dikenal (provider merotasi key).
+	reloadOnMiss = time.Minute
+	// clockSkew adalah toleransi selisih jam dengan provider.
+	clockSkew = time.Minute
+)
+
+// signingMethods adalah algoritma ID token yang diterima. HS256 tidak
+// termasuk karena client secret bukan rahasia yang cukup untuk itu.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "ES256", "ES384"}
+
+// metadata adalah bagian discovery document yang dipakai.
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider adalah identity provider OIDC. Discovery document dan JWKS
+// diambil saat pertama dibutuhkan lalu di-cache.
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu     sync.Mutex
+	meta   *metadata
+	metaAt time.Time
+	keys   map[string]jwtkeys.JWK
+	keysAt time.Time
+}
+
+func NewProvider(cfg Config) *Provider {
+	return &Provider{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Issuer adalah issuer provider (claim iss ID token).
+func (p *Provider) Issuer() string {
+	return p.cfg.Issuer
+}
+
+// RedirectURL adalah URL callback yang didaftarkan di provider.
+func (p *Provider) RedirectURL() string {
+	return p.cfg.RedirectURL
+}
+
+// AuthCodeURL adalah URL halaman login provider untuk flow.
+func (p *Provider) AuthCodeURL(ctx context.Context, flow Flow) (string, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"scope":                 {strings.Join(p.cfg.Scopes, " ")},
+		"state":                 {flow.State},
+		"nonce":                 {flow.Nonce},
+		"code_challenge":        {Challenge(flow.Verifier)},
+		"code_challenge_method": {"S256"},
+	}
+	sep := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return meta.AuthorizationEndpoint + sep + q.Encode(), nil
+}
+
+// Exchange menukar authorization code dari callback dengan token, lalu
+// memverifikasi ID token-nya: tanda tangan, iss, aud, exp dan nonce flow.
+func (p *Provider) Exchange(ctx context.Context, code string, flow Flow) (*Claims, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"code_verifier": {flow.Verifier},
+	}
+	if p.cfg.ClientSecret == "" {
+		form.Set("client_id", p.cfg.ClientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		// client_secret_basic (RFC 6749 2.3.1): id dan secret di-URL-encode dulu
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	var token struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := p.do(req, &token)
+	if err != nil {
+		return nil, fmt.Errorf("token endpoint: %w", err)
+	}
+	if token.Error != "" {
+		return nil, fmt.Errorf("token endpoint: %s: %s", token.Error, token.ErrorDescription)
+	}
+	if status != http.StatusOK || token.IDToken == "" {
+		return nil, fmt.Errorf("token endpoint: status %d without id_token", status)
+	}
+	return p.verify(ctx, token.IDToken, flow.Nonce)
+}
+
+// verify memeriksa ID token dan nonce-nya.
+func (p *Provider) verify(ctx context.Context, idToken, nonce string) (*Claims, error) {
+	raw := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(idToken, raw, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		jwk, err := p.key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if jwk.Alg != "" && jwk.Alg != token.Method.Alg() {
+			return nil, fmt.Errorf("key %q expects %s", kid, jwk.Alg)
+		}
+		return jwk.PublicKey()
+	},
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithIssuer(p.cfg.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("id token: %w", err)
+	}
+
+	// Token untuk beberapa audience harus ditujukan (azp) ke client ini
+	if aud, _ := raw.GetAudience(); len(aud) > 1 && raw["azp"] != p.cfg.ClientID {
+		return nil, errors.New("id token: azp is not this client")
+	}
+	if got, _ := raw["nonce"].(string); got == "" || got != nonce {
+		return nil, errors.New("id token: nonce mismatch")
+	}
+	claims := newClaims(raw)
+	if claims.Subject == "" {
+		return nil, errors.New("id token: missing sub")
+	}
+	return claims, nil
+}
+
+// discover mengambil discovery document issuer. Issuer di dokumen harus
+// sama persis dengan yang dikonfigurasi.
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.meta != nil && time.Since(p.metaAt) < metadataTTL {
+		return p.meta, nil
+	}
+
+	endpoint := strings.TrimSuffix(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	var meta metadata
+	status, err := p.do(req, &meta)
+	if err == nil && status != http.StatusOK {
+		err = fmt.Errorf("status %d", status)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("oidc discovery %s: %w", endpoint, err)
+	}
+	if meta.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("oidc discovery: issuer is %q, want %q", meta.Issuer, p.cfg.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, errors.New("oidc discovery: missing authorization, token or jwks endpoint")
+	}
+	p.meta, p.metaAt = &meta, time.Now()
+	return p.meta, nil
+}
+
+// key mencari public key provider berdasarkan kid. JWKS diambil ulang
+// jika kid belum dikenal (paling sering sekali per reloadOnMiss). Token
+// tanpa kid hanya diterima jika provider punya tepat satu key.
+func (p *Provider) key(ctx context.Context, kid string) (jwtkeys.JWK, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return jwtkeys.JWK{}, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if k, ok := p.lookup(kid); ok {
+		return k, nil
+	}
+	if p.keys != nil && time.Since(p.keysAt) < reloadOnMiss {
+		return jwtkeys.JWK{}, fmt.Errorf("unknown key %q", kid)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, meta.JWKSURI, nil)
+	if err != nil {
+		return jwtkeys.JWK{}, err
+	}
+	var set jwtkeys.JWKS
+	status, err := p.do(req, &set)
+	if err == nil && status != http.StatusOK {
+		err = fmt.Errorf("status %d", status)
+	}
+	if err != nil {
+		return jwtkeys.JWK{}, fmt.Errorf("oidc jwks: %w", err)
+	}
+	p.keys, p.keysAt = map[string]jwtkeys.JWK{}, time.Now()
+	for _, k := range set.Keys {
+		if k.Use == "" || k.Use == "sig" {
+			p.keys[k.Kid] = k
+		}
+	}
+	if k, ok := p.lookup(kid); ok {
+		return k, nil
+	}
+	return jwtkeys.JWK{}, fmt.Errorf("unknown key %q", kid)
+}
+
+func (p *Provider) lookup(kid string) (jwtkeys.JWK, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, k := range p.keys {
+			return k, true
+		}
+	}
+	k, ok := p.keys[kid]
+	return k, ok && kid != ""
+}
+
+// do mengirim req dan membaca jawaban JSON ke out.
+func (p *Provider) do(req *http.Request, out interface{}) (int, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return resp.StatusCode, fmt.Errorf("status %d: invalid JSON response", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
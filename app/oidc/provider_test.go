@@ -0,0 +1,207 @@
+package oidc_test
+
+import (
+	"Mango/app/oidc"
+	"Mango/app/oidc/oidctest"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const redirectURL = "http://app.test/callback"
+
+// newIdP menjalankan provider tiruan dengan issuer di URL server uji.
+func newIdP(t *testing.T, clientSecret string) *oidctest.Server {
+	t.Helper()
+	return newIdPWithKeys(t, clientSecret, t.TempDir())
+}
+
+// newIdPWithKeys seperti newIdP dengan key tanda tangan di keyDir.
+func newIdPWithKeys(t *testing.T, clientSecret, keyDir string) *oidctest.Server {
+	t.Helper()
+	var idp *oidctest.Server
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idp.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	idp, err := oidctest.New(srv.URL, "mango", clientSecret, keyDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp.Users = []oidctest.Identity{{
+		Subject:           "u-1",
+		Email:             "Budi@Unair.ac.id",
+		EmailVerified:     true,
+		Name:              "Budi",
+		PreferredUsername: "budi",
+		NIM:               "081911133001",
+		Groups:            []string{"alumni", "staff"},
+	}}
+	return idp
+}
+
+// login mengikuti AuthCodeURL, memilih user pertama di halaman login dan
+// mengembalikan code dari redirect ke callback.
+func login(t *testing.T, p *oidc.Provider, flow oidc.Flow) string {
+	t.Helper()
+	authURL, err := p.AuthCodeURL(context.Background(), flow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("code_challenge") != oidc.Challenge(flow.Verifier) || q.Get("code_challenge_method") != "S256" {
+		t.Fatalf("authorization URL without PKCE: %s", authURL)
+	}
+	if q.Get("state") != flow.State || q.Get("nonce") != flow.Nonce {
+		t.Fatalf("authorization URL without state or nonce: %s", authURL)
+	}
+
+	form := url.Values{"user": {"0"}}
+	for k := range q {
+		form.Set(k, q.Get(k))
+	}
+	u.RawQuery = ""
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.PostForm(u.String(), form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize: status %d", resp.StatusCode)
+	}
+
+	back, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || !strings.HasPrefix(back.String(), redirectURL) {
+		t.Fatalf("authorize redirected to %q", resp.Header.Get("Location"))
+	}
+	if back.Query().Get("state") != flow.State {
+		t.Fatalf("state = %q, want %q", back.Query().Get("state"), flow.State)
+	}
+	return back.Query().Get("code")
+}
+
+func newFlow(t *testing.T) oidc.Flow {
+	t.Helper()
+	flow, err := oidc.NewFlow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return flow
+}
+
+func config(idp *oidctest.Server, secret string) oidc.Config {
+	return oidc.Config{
+		Issuer:       idp.Issuer,
+		ClientID:     idp.ClientID,
+		ClientSecret: secret,
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+	}
+}
+
+func TestExchange(t *testing.T) {
+	for _, secret := range []string{"s3cret/+=", ""} {
+		idp := newIdP(t, secret)
+		p := oidc.NewProvider(config(idp, secret))
+		flow := newFlow(t)
+
+		claims, err := p.Exchange(context.Background(), login(t, p, flow), flow)
+		if err != nil {
+			t.Fatalf("secret %q: Exchange: %v", secret, err)
+		}
+		if claims.Subject != "u-1" || claims.Email != "budi@unair.ac.id" || !claims.EmailVerified {
+			t.Errorf("claims = %+v", claims)
+		}
+		if claims.String("nim") != "081911133001" || !slices.Equal(claims.Strings("groups"), []string{"alumni", "staff"}) {
+			t.Errorf("nim = %q, groups = %v", claims.String("nim"), claims.Strings("groups"))
+		}
+	}
+}
+
+func TestExchangeRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string // secret yang dipakai client
+		tamper func(code *string, flow *oidc.Flow)
+	}{
+		{"bad verifier", "s3cret", func(_ *string, f *oidc.Flow) { f.Verifier = "another-verifier" }},
+		{"bad nonce", "s3cret", func(_ *string, f *oidc.Flow) { f.Nonce = "another-nonce" }},
+		{"wrong secret", "wrong", func(*string, *oidc.Flow) {}},
+		{"unknown code", "s3cret", func(c *string, _ *oidc.Flow) { *c = "forged" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := newIdP(t, "s3cret")
+			p := oidc.NewProvider(config(idp, tt.secret))
+			flow := newFlow(t)
+			code := login(t, p, flow)
+
+			tt.tamper(&code, &flow)
+			if claims, err := p.Exchange(context.Background(), code, flow); err == nil {
+				t.Errorf("Exchange accepted: %+v", claims)
+			}
+		})
+	}
+}
+
+func TestExchangeCodeOnce(t *testing.T) {
+	idp := newIdP(t, "s3cret")
+	p := oidc.NewProvider(config(idp, "s3cret"))
+	flow := newFlow(t)
+	code := login(t, p, flow)
+
+	if _, err := p.Exchange(context.Background(), code, flow); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Exchange(context.Background(), code, flow); err == nil {
+		t.Error("code exchanged twice")
+	}
+}
+
+func TestIssuerMismatch(t *testing.T) {
+	idp := newIdP(t, "s3cret")
+	cfg := config(idp, "s3cret")
+	cfg.Issuer += "/"
+	p := oidc.NewProvider(cfg)
+
+	if _, err := p.AuthCodeURL(context.Background(), newFlow(t)); err == nil {
+		t.Error("discovery accepted a different issuer")
+	}
+}
+
+func TestIDTokenFromOtherIssuer(t *testing.T) {
+	// Provider lain dengan key yang sama tetapi issuer berbeda
+	keyDir := t.TempDir()
+	other := newIdPWithKeys(t, "s3cret", keyDir)
+	op := oidc.NewProvider(config(other, "s3cret"))
+	flow := newFlow(t)
+	code := login(t, op, flow)
+
+	// Token endpoint provider yang dikonfigurasi diteruskan ke provider lain
+	idp := newIdPWithKeys(t, "s3cret", keyDir)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			other.ServeHTTP(w, r)
+			return
+		}
+		idp.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	idp.Issuer = srv.URL
+
+	p := oidc.NewProvider(config(idp, "s3cret"))
+	_, err := p.Exchange(context.Background(), code, flow)
+	if err == nil || !strings.Contains(err.Error(), "iss") {
+		t.Errorf("Exchange err = %v, want an issuer error", err)
+	}
+}
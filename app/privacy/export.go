@@ -0,0 +1,185 @@
+// Package privacy menyusun salinan data pribadi milik satu subject untuk
+// memenuhi hak akses data (UU PDP).
+package privacy
+
+import (
+	model "Mango/app/Model"
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// FormatVersion naik jika struktur isi ZIP berubah.
+const FormatVersion = 1
+
+// Account adalah data akun user tanpa password.
+type Account struct {
+	ID        primitive.ObjectID `json:"id"`
+	Username  string             `json:"username"`
+	Email     string             `json:"email"`
+	Role      string             `json:"role"`
+	AlumniID  primitive.ObjectID `json:"alumni_id,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// Bundle adalah seluruh data yang tersimpan tentang satu subject.
+type Bundle struct {
+	Subject   model.DataSubject
+	Accounts  []Account
+	Alumni    *model.Alumni
+	Pekerjaan []model.Pekerjaan
+	Uploads   []model.Files
+	Revisions []model.AlumniRevision
+	Erasures  []model.ErasureRequest
+}
+
+// Manifest adalah manifest.json di dalam ZIP.
+type Manifest struct {
+	FormatVersion int                `json:"format_version"`
+	GeneratedAt   time.Time          `json:"generated_at"`
+	UserID        primitive.ObjectID `json:"user_id,omitempty"`
+	AlumniID      primitive.ObjectID `json:"alumni_id,omitempty"`
+	Files         []string           `json:"files"`
+	MissingFiles  []string           `json:"missing_files,omitempty"`
+}
+
+// Collect membaca semua data subject, termasuk yang ada di tempat sampah.
+func Collect(ctx context.Context, db *mongo.Database, subject model.DataSubject) (*Bundle, error) {
+	b := &Bundle{Subject: subject}
+
+	users := bson.M{"_id": subject.UserID}
+	if !subject.AlumniID.IsZero() {
+		users = bson.M{"$or": bson.A{users, bson.M{"alumni_id": subject.AlumniID}}}
+	}
+	var accounts []model.User
+	if err := findAll(ctx, db.Collection("Users"), users, &accounts); err != nil {
+		return nil, err
+	}
+	owners := bson.A{}
+	for _, u := range accounts {
+		b.Accounts = append(b.Accounts, Account{
+			ID: u.ID, Username: u.Username, Email: string(u.Email), Role: u.Role, AlumniID: u.AlumniID, CreatedAt: u.CreatedAt,
+		})
+		owners = append(owners, u.ID)
+	}
+
+	if !subject.AlumniID.IsZero() {
+		var a model.Alumni
+		err := db.Collection("alumni").FindOne(ctx, bson.M{"_id": subject.AlumniID}).Decode(&a)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, err
+		}
+		if err == nil {
+			a.Search = nil
+			b.Alumni = &a
+		}
+		byAlumni := bson.M{"alumni_id": subject.AlumniID}
+		if err := findAll(ctx, db.Collection("pekerjaan_alumni"), byAlumni, &b.Pekerjaan); err != nil {
+			return nil, err
+		}
+		if err := findAll(ctx, db.Collection("alumni_revisions"), byAlumni, &b.Revisions); err != nil {
+			return nil, err
+		}
+		owners = append(owners, subject.AlumniID)
+	}
+
+	if err := findAll(ctx, db.Collection("uploads"), bson.M{"user_id": bson.M{"$in": owners}}, &b.Uploads); err != nil {
+		return nil, err
+	}
+	if !subject.UserID.IsZero() {
+		if err := findAll(ctx, db.Collection("erasure_requests"), bson.M{"user_id": subject.UserID}, &b.Erasures); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// WriteZip menulis bundle sebagai ZIP: satu file JSON per jenis data,
+// file upload di folder files/ dan manifest.json. File upload yang tidak
+// ada di disk dicatat di manifest.
+func (b *Bundle) WriteZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	manifest := Manifest{
+		FormatVersion: FormatVersion,
+		GeneratedAt:   time.Now().UTC(),
+		UserID:        b.Subject.UserID,
+		AlumniID:      b.Subject.AlumniID,
+	}
+
+	for _, part := range []struct {
+		name string
+		data any
+	}{
+		{"accounts.json", b.Accounts},
+		{"alumni.json", b.Alumni},
+		{"pekerjaan.json", b.Pekerjaan},
+		{"uploads.json", b.Uploads},
+		{"revisions.json", b.Revisions},
+		{"erasure_requests.json", b.Erasures},
+	} {
+		if err := writeJSON(zw, part.name, part.data); err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, part.name)
+	}
+
+	for _, f := range b.Uploads {
+		name := fmt.Sprintf("files/%s_%s", f.ID.Hex(), filepath.Base(f.Filename))
+		if err := copyFile(zw, name, f.Filepath); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				manifest.MissingFiles = append(manifest.MissingFiles, name)
+				continue
+			}
+			return err
+		}
+		manifest.Files = append(manifest.Files, name)
+	}
+
+	if err := writeJSON(zw, "manifest.json", manifest); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func findAll(ctx context.Context, col *mongo.Collection, filter bson.M, result any) error {
+	cursor, err := col.Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+	return cursor.All(ctx, result)
+}
+
+func writeJSON(zw *zip.Writer, name string, data any) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(data)
+}
+
+func copyFile(zw *zip.Writer, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
@@ -0,0 +1,137 @@
+package privacy
+
+import (
+	model "Mango/app/Model"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Viewer adalah pihak yang melihat data alumni. Viewer kosong hanya
+// melihat field public.
+type Viewer struct {
+	Admin    bool
+	Alumnus  bool               // viewer adalah sesama alumni
+	AlumniID primitive.ObjectID // alumni milik viewer, jika tertaut
+}
+
+// ViewerOf membentuk Viewer dari user yang login (nil untuk anonim).
+// Hanya tautan alumni yang diverifikasi server (SSO atau admin) yang
+// membuat user dianggap alumni dan pemilik datanya.
+func ViewerOf(user *model.User) Viewer {
+	if user == nil {
+		return Viewer{}
+	}
+	alumniID := user.LinkedAlumni()
+	return Viewer{Admin: user.Role == "admin", Alumnus: !alumniID.IsZero(), AlumniID: alumniID}
+}
+
+// Audience membentuk Viewer tiruan untuk tingkat visibilitas tertentu,
+// misalnya export yang akan dibagikan ke sesama alumni.
+func Audience(level string) Viewer {
+	switch level {
+	case model.VisibilityAdmin:
+		return Viewer{Admin: true}
+	case model.VisibilityAlumni:
+		return Viewer{Alumnus: true}
+	}
+	return Viewer{}
+}
+
+// full melaporkan apakah viewer melihat data owner tanpa pembatasan.
+func (v Viewer) full(owner primitive.ObjectID) bool {
+	return v.Admin || (!v.AlumniID.IsZero() && v.AlumniID == owner)
+}
+
+func (v Viewer) sees(level string) bool {
+	switch level {
+	case model.VisibilityPublic:
+		return true
+	case model.VisibilityAlumni:
+		return v.Alumnus || v.Admin
+	}
+	return false
+}
+
+// Alumni menyaring field kontak a sesuai pengaturan privasinya. Field
+// yang tidak boleh dilihat dikosongkan, nomor telepon disamarkan, dan
+// pengaturan privasi hanya terlihat oleh admin dan pemiliknya.
+func (v Viewer) Alumni(a *model.Alumni) {
+	if v.full(a.ID) {
+		return
+	}
+	p := a.Privacy.Resolve()
+	a.Email = model.Secret(v.field(string(a.Email), p.Email))
+	a.Alamat = model.Secret(v.field(string(a.Alamat), p.Alamat))
+	a.No_telp = model.Secret(v.phone(string(a.No_telp), p))
+	a.Privacy = nil
+}
+
+// Hit menyaring hasil pencarian, termasuk fragmen highlight field yang
+// disembunyikan.
+func (v Viewer) Hit(h *model.SearchHit) {
+	v.Alumni(&h.Alumni)
+	if h.Alumni.Email == "" {
+		delete(h.Highlights, "email")
+	}
+}
+
+// Doc menyaring dokumen alumni mentah (untuk export) dengan aturan yang
+// sama seperti Alumni.
+func (v Viewer) Doc(doc bson.M) {
+	owner, _ := doc["_id"].(primitive.ObjectID)
+	if v.full(owner) {
+		return
+	}
+	var settings *model.AlumniPrivacy
+	if raw, ok := doc["privacy"]; ok {
+		if b, err := bson.Marshal(raw); err == nil {
+			settings = &model.AlumniPrivacy{}
+			_ = bson.Unmarshal(b, settings)
+		}
+	}
+	p := settings.Resolve()
+
+	str := func(key string) string {
+		s, _ := doc[key].(string)
+		return s
+	}
+	doc["email"] = v.field(str("email"), p.Email)
+	doc["alamat"] = v.field(str("alamat"), p.Alamat)
+	doc["no_telp"] = v.phone(str("no_telp"), p)
+	delete(doc, "privacy")
+}
+
+func (v Viewer) field(value, level string) string {
+	if v.sees(level) {
+		return value
+	}
+	return ""
+}
+
+func (v Viewer) phone(value string, p model.AlumniPrivacy) string {
+	if !v.sees(p.NoTelp) {
+		return ""
+	}
+	if p.ShowFullPhone {
+		return value
+	}
+	return MaskPhone(value)
+}
+
+// MaskPhone menyamarkan nomor telepon dan hanya menyisakan 4 karakter
+// pertama dan 3 digit terakhir, misalnya "0812*****789".
+func MaskPhone(phone string) string {
+	r := []rune(phone)
+	if len(r) == 0 {
+		return ""
+	}
+	if len(r) < 8 {
+		return strings.Repeat("*", len(r))
+	}
+	for i := 4; i < len(r)-3; i++ {
+		r[i] = '*'
+	}
+	return string(r)
+}
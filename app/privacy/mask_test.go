@@ -0,0 +1,102 @@
+package privacy
+
+import (
+	model "Mango/app/Model"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMaskPhone(t *testing.T) {
+	tests := map[string]string{
+		"081234567890":   "0812*****890",
+		"+6281234567890": "+628*******890",
+		"02112345":       "0211*345",
+		"0211234":        "*******",
+		"":               "",
+	}
+	for in, want := range tests {
+		if got := MaskPhone(in); got != want {
+			t.Errorf("MaskPhone(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestViewerOf(t *testing.T) {
+	alumniID := primitive.NewObjectID()
+	link := &model.AlumniLink{Via: model.AlumniLinkSSO, LinkedAt: time.Now()}
+
+	tests := []struct {
+		name string
+		user *model.User
+		want Viewer
+	}{
+		{"anonymous", nil, Viewer{}},
+		{"admin", &model.User{Role: "admin"}, Viewer{Admin: true}},
+		{"linked alumnus", &model.User{Role: "user", AlumniID: alumniID, AlumniLink: link}, Viewer{Alumnus: true, AlumniID: alumniID}},
+		{"unverified alumni_id", &model.User{Role: "user", AlumniID: alumniID}, Viewer{}},
+	}
+	for _, tt := range tests {
+		if got := ViewerOf(tt.user); got != tt.want {
+			t.Errorf("%s: ViewerOf = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestViewerAlumni(t *testing.T) {
+	owner := primitive.NewObjectID()
+	alumni := func(p *model.AlumniPrivacy) model.Alumni {
+		return model.Alumni{ID: owner, Email: "budi@example.com", No_telp: "081234567890", Alamat: "Surabaya", Privacy: p}
+	}
+	type contact struct{ email, phone, alamat string }
+
+	tests := []struct {
+		name    string
+		viewer  Viewer
+		privacy *model.AlumniPrivacy
+		want    contact
+	}{
+		{"anonymous, defaults", Viewer{}, nil, contact{}},
+		{"alumnus, defaults", Viewer{Alumnus: true, AlumniID: primitive.NewObjectID()}, nil, contact{"budi@example.com", "0812*****890", ""}},
+		{"owner", Viewer{Alumnus: true, AlumniID: owner}, nil, contact{"budi@example.com", "081234567890", "Surabaya"}},
+		{"admin", Viewer{Admin: true}, &model.AlumniPrivacy{Email: model.VisibilityAdmin}, contact{"budi@example.com", "081234567890", "Surabaya"}},
+		{"public with full phone", Viewer{}, &model.AlumniPrivacy{Email: model.VisibilityPublic, NoTelp: model.VisibilityPublic, ShowFullPhone: true}, contact{"budi@example.com", "081234567890", ""}},
+		{"alumnus, admin only", Viewer{Alumnus: true}, &model.AlumniPrivacy{Email: model.VisibilityAdmin, NoTelp: model.VisibilityAdmin}, contact{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := alumni(tt.privacy)
+			tt.viewer.Alumni(&a)
+			got := contact{string(a.Email), string(a.No_telp), string(a.Alamat)}
+			if got != tt.want {
+				t.Errorf("contact = %+v, want %+v", got, tt.want)
+			}
+			if full := tt.viewer.Admin || tt.viewer.AlumniID == owner; !full && a.Privacy != nil {
+				t.Error("privacy settings visible to another viewer")
+			}
+		})
+	}
+}
+
+func TestViewerDoc(t *testing.T) {
+	doc := bson.M{
+		"_id":     primitive.NewObjectID(),
+		"email":   "budi@example.com",
+		"no_telp": "081234567890",
+		"alamat":  "Surabaya",
+		"privacy": bson.M{"email": model.VisibilityPublic},
+	}
+	Audience(model.VisibilityPublic).Doc(doc)
+
+	want := bson.M{"email": "budi@example.com", "no_telp": "", "alamat": ""}
+	for k, v := range want {
+		if doc[k] != v {
+			t.Errorf("%s = %q, want %q", k, doc[k], v)
+		}
+	}
+	if _, ok := doc["privacy"]; ok {
+		t.Error("privacy settings left in the document")
+	}
+}
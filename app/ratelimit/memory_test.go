@@ -0,0 +1,139 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// clock adalah jam palsu untuk MemoryStore.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestStore() (*MemoryStore, *clock) {
+	c := &clock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := NewMemoryStore()
+	s.now = c.now
+	return s, c
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	limit := Limit{Burst: 3, Per: 3 * time.Second}
+
+	type step struct {
+		after time.Duration // dimajukan sebelum Take
+		key   string
+		want  bool
+		retry time.Duration // RetryAfter yang diharapkan jika ditolak
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"burst then reject", []step{
+			{0, "a", true, 0}, {0, "a", true, 0}, {0, "a", true, 0}, {0, "a", false, time.Second},
+		}},
+		{"refills over time", []step{
+			{0, "a", true, 0}, {0, "a", true, 0}, {0, "a", true, 0},
+			{500 * time.Millisecond, "a", false, 500 * time.Millisecond},
+			{500 * time.Millisecond, "a", true, 0},
+			{0, "a", false, time.Second},
+		}},
+		{"keys are independent", []step{
+			{0, "a", true, 0}, {0, "a", true, 0}, {0, "a", true, 0}, {0, "a", false, time.Second},
+			{0, "b", true, 0},
+		}},
+		{"never exceeds burst", []step{
+			{time.Hour, "a", true, 0}, {0, "a", true, 0}, {0, "a", true, 0}, {0, "a", false, time.Second},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, c := newTestStore()
+			for i, st := range tt.steps {
+				c.advance(st.after)
+				res, err := s.Take(context.Background(), st.key, limit)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if res.Allowed != st.want {
+					t.Fatalf("step %d: allowed = %v, want %v", i, res.Allowed, st.want)
+				}
+				if !res.Allowed && res.RetryAfter != st.retry {
+					t.Errorf("step %d: retry after = %v, want %v", i, res.RetryAfter, st.retry)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreDisabled(t *testing.T) {
+	s, _ := newTestStore()
+	for i := 0; i < 10; i++ {
+		if res, _ := s.Take(context.Background(), "a", Limit{}); !res.Allowed {
+			t.Fatal("disabled limit rejected a request")
+		}
+	}
+	if len(s.buckets) != 0 {
+		t.Errorf("disabled limit created %d buckets", len(s.buckets))
+	}
+}
+
+func TestMemoryStoreReset(t *testing.T) {
+	s, _ := newTestStore()
+	limit := Limit{Burst: 1, Per: time.Minute}
+	ctx := context.Background()
+
+	s.Take(ctx, "a", limit)
+	if res, _ := s.Take(ctx, "a", limit); res.Allowed {
+		t.Fatal("second request within the limit allowed")
+	}
+	if err := s.Reset(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := s.Take(ctx, "a", limit); !res.Allowed {
+		t.Error("request after reset rejected")
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	s, c := newTestStore()
+	ctx := context.Background()
+	s.Take(ctx, "short", Limit{Burst: 1, Per: time.Second})
+	s.Take(ctx, "long", Limit{Burst: 1, Per: time.Hour})
+
+	c.advance(sweepEvery + time.Second)
+	s.Take(ctx, "other", Limit{Burst: 1, Per: time.Second})
+
+	if _, ok := s.buckets["short"]; ok {
+		t.Error("refilled bucket not swept")
+	}
+	if _, ok := s.buckets["long"]; !ok {
+		t.Error("bucket still refilling was swept")
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Limit
+		ok   bool
+	}{
+		{"5/1m", Limit{Burst: 5, Per: time.Minute}, true},
+		{" 100/1h ", Limit{Burst: 100, Per: time.Hour}, true},
+		{"off", Limit{}, true},
+		{"0", Limit{}, true},
+		{"5", Limit{}, false},
+		{"-1/1m", Limit{}, false},
+		{"5/0s", Limit{}, false},
+		{"5/soon", Limit{}, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.in)
+		if ok := err == nil; ok != tt.ok || got != tt.want {
+			t.Errorf("ParseLimit(%q) = %v, %v; want %v, ok %v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoStore menyimpan bucket di koleksi rate_limits sehingga limit
+// berlaku bersama untuk semua replica. Setiap pengambilan token adalah
+// satu update atomik; bucket yang lama tidak dipakai (sudah penuh lagi)
+// dihapus oleh TTL index.
+type MongoStore struct {
+	Col *mongo.Collection
+}
+
+func NewMongoStore(db *mongo.Database) *MongoStore {
+	return &MongoStore{Col: db.Collection("rate_limits")}
+}
+
+func (s *MongoStore) Collection() *mongo.Collection {
+	return s.Col
+}
+
+// Indexes: TTL di expires_at.
+func (s *MongoStore) Indexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+		},
+	}
+}
+
+func (s *MongoStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	if !limit.Enabled() {
+		return Result{Allowed: true}, nil
+	}
+
+	now := time.Now()
+	burst := float64(limit.Burst)
+	elapsed := bson.M{"$divide": bson.A{
+		bson.M{"$subtract": bson.A{now, bson.M{"$ifNull": bson.A{"$updated_at", now}}}},
+		1000,
+	}}
+	refilled := bson.M{"$min": bson.A{burst, bson.M{"$add": bson.A{
+		bson.M{"$ifNull": bson.A{"$tokens", burst}},
+		bson.M{"$multiply": bson.A{elapsed, limit.rate()}},
+	}}}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"tokens": refilled, "updated_at": now}}},
+		{{Key: "$set", Value: bson.M{"allowed": bson.M{"$gte": bson.A{"$tokens", 1}}}}},
+		{{Key: "$set", Value: bson.M{
+			"tokens":     bson.M{"$cond": bson.A{"$allowed", bson.M{"$subtract": bson.A{"$tokens", 1}}, "$tokens"}},
+			"expires_at": now.Add(limit.Per),
+		}}},
+	}
+
+	var b struct {
+		Tokens  float64 `bson:"tokens"`
+		Allowed bool    `bson:"allowed"`
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := s.Col.FindOneAndUpdate(ctx, bson.M{"_id": key}, pipeline, opts).Decode(&b)
+	if mongo.IsDuplicateKeyError(err) {
+		// Dua upsert pertama yang bersamaan; yang kalah cukup diulang
+		err = s.Col.FindOneAndUpdate(ctx, bson.M{"_id": key}, pipeline, opts).Decode(&b)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+
+	if !b.Allowed {
+		return Result{RetryAfter: limit.wait(b.Tokens)}, nil
+	}
+	return Result{Allowed: true}, nil
+}
+
+func (s *MongoStore) Reset(ctx context.Context, key string) error {
+	_, err := s.Col.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
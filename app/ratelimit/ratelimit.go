@@ -0,0 +1,78 @@
+// Package ratelimit membatasi laju permintaan dengan token bucket per
+// key (misalnya IP atau username). Bucket bisa disimpan di memori untuk
+// satu instance, atau di MongoDB agar batasnya berlaku bersama untuk
+// semua replica.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit adalah kapasitas bucket: Burst permintaan sekaligus, terisi
+// kembali Burst token setiap Per. Limit kosong berarti tanpa batas.
+type Limit struct {
+	Burst int
+	Per   time.Duration
+}
+
+// Enabled melaporkan apakah limit membatasi sesuatu.
+func (l Limit) Enabled() bool {
+	return l.Burst > 0 && l.Per > 0
+}
+
+// rate adalah token yang terisi per detik.
+func (l Limit) rate() float64 {
+	return float64(l.Burst) / l.Per.Seconds()
+}
+
+// wait adalah waktu sampai bucket berisi satu token lagi.
+func (l Limit) wait(tokens float64) time.Duration {
+	seconds := (1 - tokens) / l.rate()
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
+
+func (l Limit) String() string {
+	if !l.Enabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", l.Burst, l.Per)
+}
+
+// ParseLimit membaca limit berbentuk "N/durasi", misalnya "5/1m" atau
+// "100/1h". "off" dan "0" mematikan limit.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "off" || s == "0" {
+		return Limit{}, nil
+	}
+	count, per, ok := strings.Cut(s, "/")
+	burst, err := strconv.Atoi(count)
+	if !ok || err != nil || burst <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, want N/duration such as 5/1m", s)
+	}
+	d, err := time.ParseDuration(per)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, want N/duration such as 5/1m", s)
+	}
+	return Limit{Burst: burst, Per: d}, nil
+}
+
+// Result adalah hasil satu pengambilan token.
+type Result struct {
+	Allowed bool
+	// RetryAfter adalah waktu tunggu sampai permintaan berikutnya boleh
+	// (hanya diisi jika ditolak).
+	RetryAfter time.Duration
+}
+
+// Store menyimpan bucket. Take mengambil satu token dari bucket key;
+// Reset mengosongkan catatan key (bucket kembali penuh).
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+	Reset(ctx context.Context, key string) error
+}
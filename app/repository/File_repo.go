@@ -1,10 +1,13 @@
 package repository
 
 import (
+	"Mango/app/apperror"
 	model "Mango/app/Model"
 	"context"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type Filerepository struct {
@@ -17,8 +20,24 @@ func NewUploadRepository(db *mongo.Database) *Filerepository {
 }
 
 
+func (r *Filerepository) Collection() *mongo.Collection {
+	return r.Col
+}
+
+// Indexes: file dicari per user dan jenis file.
+func (r *Filerepository) Indexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "type", Value: 1}},
+			Options: options.Index().SetName("user_id_type"),
+		},
+	}
+}
+
 func (r *Filerepository) Save(ctx context.Context, file *model.Files) error {
-	_, err := r.Col.InsertOne(ctx, file)
-	return err
+	if _, err := r.Col.InsertOne(ctx, file); err != nil {
+		return apperror.Internal(err)
+	}
+	return nil
 }
 
@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"context"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Dependents menghitung data aktif yang bergantung pada alumni: pekerjaan,
+// akun user yang tertaut dan upload milik alumni atau akun tersebut.
+func (r *AlumniRepository) Dependents(ctx context.Context, id primitive.ObjectID) (model.Dependents, error) {
+	var d model.Dependents
+
+	jobs := active()
+	jobs["alumni_id"] = id
+	n, err := r.jobs().CountDocuments(ctx, jobs)
+	if err != nil {
+		return d, apperror.Internal(err)
+	}
+	d.Pekerjaan = n
+
+	users := active()
+	users["alumni_id"] = id
+	userIDs, err := r.users().Distinct(ctx, "_id", users)
+	if err != nil {
+		return d, apperror.Internal(err)
+	}
+	d.Users = int64(len(userIDs))
+
+	owners := append(bson.A{id}, userIDs...)
+	if d.Uploads, err = r.uploads().CountDocuments(ctx, bson.M{"user_id": bson.M{"$in": owners}}); err != nil {
+		return d, apperror.Internal(err)
+	}
+	return d, nil
+}
+
+// Delete memindahkan alumni ke tempat sampah dan menangani data yang
+// bergantung sesuai policy (lihat model.DeleteCascade). Semua perubahan
+// dijalankan dalam satu transaksi jika deployment mendukung.
+func (r *AlumniRepository) Delete(ctx context.Context, id, by primitive.ObjectID, policy string) error {
+	return RunInTransaction(ctx, r.Col.Database(), func(ctx context.Context) error {
+		if n, err := r.Col.CountDocuments(ctx, withID(active(), id)); err != nil {
+			return apperror.Internal(err)
+		} else if n == 0 {
+			return ErrAlumniNotFound()
+		}
+
+		if policy == model.DeleteBlock {
+			deps, err := r.Dependents(ctx, id)
+			if err != nil {
+				return err
+			}
+			if deps.Any() {
+				return ErrAlumniHasDependents(deps)
+			}
+		}
+
+		mark := bson.M{"deleted_at": time.Now().Unix(), "deleted_by": by}
+		var deleted model.Alumni
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+		err := r.Col.FindOneAndUpdate(ctx, withID(active(), id), bson.M{"$set": mark, "$inc": bson.M{"revision": 1}}, opts).Decode(&deleted)
+		if err != nil {
+			return apperror.Internal(err)
+		}
+		if err := r.Revisions().save(ctx, deleted, model.AuditDelete, by); err != nil {
+			return err
+		}
+
+		mark["deleted_with"] = id
+		dependents := active()
+		dependents["alumni_id"] = id
+		if _, err := r.jobs().UpdateMany(ctx, dependents, bson.M{"$set": mark}); err != nil {
+			return apperror.Internal(err)
+		}
+
+		switch policy {
+		case model.DeleteCascade:
+			_, err := r.users().UpdateMany(ctx, dependents, bson.M{"$set": mark})
+			if err != nil {
+				return apperror.Internal(err)
+			}
+		case model.DeleteDetach:
+			_, err := r.users().UpdateMany(ctx, dependents, bson.M{"$unset": bson.M{"alumni_id": "", "alumni_link": ""}})
+			if err != nil {
+				return apperror.Internal(err)
+			}
+		}
+		return nil
+	})
+}
+
+// Trash mengembalikan alumni di tempat sampah, yang terbaru dihapus lebih
+// dulu.
+func (r *AlumniRepository) Trash(ctx context.Context, limit int) ([]model.Alumni, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := r.Col.Find(ctx, trashed(), opts)
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+
+	result := []model.Alumni{}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return result, nil
+}
+
+// Restore mengeluarkan alumni dari tempat sampah beserta pekerjaan dan
+// akun user yang terhapus bersamanya. Akun yang dilepas (detach) tidak
+// ditautkan kembali. by adalah user yang memulihkan.
+func (r *AlumniRepository) Restore(ctx context.Context, id, by primitive.ObjectID) (*model.Alumni, error) {
+	restore := bson.M{"$unset": bson.M{"deleted_at": "", "deleted_by": "", "deleted_with": ""}}
+
+	var a model.Alumni
+	err := RunInTransaction(ctx, r.Col.Database(), func(ctx context.Context) error {
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+		update := bson.M{"$unset": restore["$unset"], "$inc": bson.M{"revision": 1}}
+		if err := r.Col.FindOneAndUpdate(ctx, withID(trashed(), id), update, opts).Decode(&a); err != nil {
+			return mapFindError(err, ErrAlumniNotInTrash)
+		}
+		if err := r.Revisions().save(ctx, a, model.AuditRestore, by); err != nil {
+			return err
+		}
+
+		with := bson.M{"deleted_with": id}
+		if _, err := r.jobs().UpdateMany(ctx, with, restore); err != nil {
+			return apperror.Internal(err)
+		}
+		if _, err := r.users().UpdateMany(ctx, with, restore); err != nil {
+			return apperror.Internal(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Purge menghapus permanen alumni yang sudah di tempat sampah sebelum
+// waktu before (unix), beserta seluruh pekerjaan dan riwayat versinya,
+// akun user yang ikut terhapus dan upload milik keduanya. Dengan dryRun hanya menghitung.
+//
+// File upload tidak bisa ikut transaksi, jadi ditangani dengan langkah
+// kompensasi: file dipindahkan ke nama sementara sebelum transaksi,
+// dikembalikan jika transaksi gagal dan baru dihapus setelah commit.
+func (r *AlumniRepository) Purge(ctx context.Context, before int64, dryRun bool) (model.PurgeReport, error) {
+	var report model.PurgeReport
+
+	ids, err := r.Col.Distinct(ctx, "_id", bson.M{"deleted_at": bson.M{"$lt": before}})
+	if err != nil {
+		return report, apperror.Internal(err)
+	}
+	if len(ids) == 0 {
+		return report, nil
+	}
+	userIDs, err := r.users().Distinct(ctx, "_id", bson.M{"deleted_with": bson.M{"$in": ids}})
+	if err != nil {
+		return report, apperror.Internal(err)
+	}
+
+	jobs := bson.M{"alumni_id": bson.M{"$in": ids}}
+	uploads := bson.M{"user_id": bson.M{"$in": append(append(bson.A{}, ids...), userIDs...)}}
+
+	var files []model.Files
+	cursor, err := r.uploads().Find(ctx, uploads, options.Find().SetProjection(bson.M{"file_path": 1}))
+	if err != nil {
+		return report, apperror.Internal(err)
+	}
+	if err := cursor.All(ctx, &files); err != nil {
+		return report, apperror.Internal(err)
+	}
+
+	if dryRun {
+		report.Alumni = int64(len(ids))
+		report.Users = int64(len(userIDs))
+		report.Uploads = int64(len(files))
+		if report.Pekerjaan, err = r.jobs().CountDocuments(ctx, jobs); err != nil {
+			return report, apperror.Internal(err)
+		}
+		return report, nil
+	}
+
+	staged := stageFiles(files)
+	err = RunInTransaction(ctx, r.Col.Database(), func(ctx context.Context) error {
+		deleted, err := r.jobs().DeleteMany(ctx, jobs)
+		if err != nil {
+			return err
+		}
+		report.Pekerjaan = deleted.DeletedCount
+
+		if deleted, err = r.uploads().DeleteMany(ctx, uploads); err != nil {
+			return err
+		}
+		report.Uploads = deleted.DeletedCount
+
+		if deleted, err = r.users().DeleteMany(ctx, bson.M{"_id": bson.M{"$in": userIDs}}); err != nil {
+			return err
+		}
+		report.Users = deleted.DeletedCount
+
+		if deleted, err = r.Col.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+			return err
+		}
+		report.Alumni = deleted.DeletedCount
+
+		// Riwayat versi ikut dihapus agar data pribadi tidak tertinggal
+		_, err = r.Revisions().Col.DeleteMany(ctx, bson.M{"alumni_id": bson.M{"$in": ids}})
+		return err
+	})
+	if err != nil {
+		staged.rollback()
+		return model.PurgeReport{}, apperror.Internal(err)
+	}
+	staged.commit()
+	return report, nil
+}
+
+// stagedFiles adalah file upload yang sudah dipindahkan ke nama sementara
+// menunggu hasil transaksi.
+type stagedFiles map[string]string // path asli -> path sementara
+
+func stageFiles(files []model.Files) stagedFiles {
+	staged := stagedFiles{}
+	for _, f := range files {
+		if f.Filepath == "" {
+			continue
+		}
+		tmp := f.Filepath + ".purging"
+		if err := os.Rename(f.Filepath, tmp); err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				log.Printf("⚠️  cannot stage %s for deletion: %v", f.Filepath, err)
+			}
+			continue
+		}
+		staged[f.Filepath] = tmp
+	}
+	return staged
+}
+
+// commit menghapus file sementara. File yang gagal dihapus akan terlihat
+// di consistency-check.
+func (s stagedFiles) commit() {
+	for _, tmp := range s {
+		if err := os.Remove(tmp); err != nil {
+			log.Printf("⚠️  cannot delete %s: %v", tmp, err)
+		}
+	}
+}
+
+// rollback mengembalikan file ke nama aslinya.
+func (s stagedFiles) rollback() {
+	for path, tmp := range s {
+		if err := os.Rename(tmp, path); err != nil {
+			log.Printf("❌ cannot restore %s from %s: %v", path, tmp, err)
+		}
+	}
+}
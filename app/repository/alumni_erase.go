@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Erase menghapus data pribadi subject (hak penghapusan UU PDP):
+//   - alumni dianonimkan: NIM, nama, email, telepon dan alamat dihapus;
+//     jurusan, angkatan dan tahun lulus tetap untuk statistik
+//   - pekerjaan tetap ada untuk statistik, tanpa deskripsi bebas
+//   - akun user dan upload milik alumni atau akunnya dihapus beserta file,
+//     juga token dan email di outbox untuk akun tersebut
+//   - riwayat versi alumni diganti satu versi hasil anonimisasi
+//   - audit log tentang subject dibersihkan dari nilai field dan username
+//
+// Seperti Purge, file dipindahkan sebelum transaksi dan baru dihapus
+// setelah commit.
+func (r *AlumniRepository) Erase(ctx context.Context, subject model.DataSubject, by primitive.ObjectID) (model.ErasureReport, error) {
+	var report model.ErasureReport
+
+	userIDs := bson.A{}
+	if !subject.UserID.IsZero() {
+		userIDs = append(userIDs, subject.UserID)
+	}
+	if !subject.AlumniID.IsZero() {
+		linked, err := r.users().Distinct(ctx, "_id", bson.M{"alumni_id": subject.AlumniID, "_id": bson.M{"$ne": subject.UserID}})
+		if err != nil {
+			return report, apperror.Internal(err)
+		}
+		userIDs = append(userIDs, linked...)
+	}
+
+	owners := append(bson.A{}, userIDs...)
+	if !subject.AlumniID.IsZero() {
+		owners = append(owners, subject.AlumniID)
+	}
+	uploads := bson.M{"user_id": bson.M{"$in": owners}}
+	var files []model.Files
+	cursor, err := r.uploads().Find(ctx, uploads)
+	if err != nil {
+		return report, apperror.Internal(err)
+	}
+	if err := cursor.All(ctx, &files); err != nil {
+		return report, apperror.Internal(err)
+	}
+
+	// Semua ID yang mungkin muncul sebagai entity_id di audit log
+	entities := append(bson.A{}, owners...)
+	for _, f := range files {
+		entities = append(entities, f.ID)
+	}
+	if !subject.AlumniID.IsZero() {
+		jobIDs, err := r.jobs().Distinct(ctx, "_id", bson.M{"alumni_id": subject.AlumniID})
+		if err != nil {
+			return report, apperror.Internal(err)
+		}
+		entities = append(entities, jobIDs...)
+	}
+
+	staged := stageFiles(files)
+	err = RunInTransaction(ctx, r.Col.Database(), func(ctx context.Context) error {
+		report = model.ErasureReport{}
+
+		deleted, err := r.uploads().DeleteMany(ctx, uploads)
+		if err != nil {
+			return err
+		}
+		report.Uploads = deleted.DeletedCount
+
+		if deleted, err = r.users().DeleteMany(ctx, bson.M{"_id": bson.M{"$in": userIDs}}); err != nil {
+			return err
+		}
+		report.Users = deleted.DeletedCount
+
+		owned := bson.M{"user_id": bson.M{"$in": userIDs}}
+		if _, err := r.Col.Database().Collection("user_tokens").DeleteMany(ctx, owned); err != nil {
+			return err
+		}
+		if _, err := r.Col.Database().Collection("email_outbox").DeleteMany(ctx, owned); err != nil {
+			return err
+		}
+
+		if !subject.AlumniID.IsZero() {
+			if err := r.anonymise(ctx, subject.AlumniID, by, &report); err != nil {
+				return err
+			}
+		}
+
+		audit := r.Col.Database().Collection("audit_log")
+		scrubbed, err := audit.UpdateMany(ctx, bson.M{"entity_id": bson.M{"$in": entities}}, bson.M{"$unset": bson.M{"changes": ""}})
+		if err != nil {
+			return err
+		}
+		report.AuditEntries = scrubbed.ModifiedCount
+		if scrubbed, err = audit.UpdateMany(ctx, bson.M{"actor_id": bson.M{"$in": userIDs}}, bson.M{"$unset": bson.M{"actor": ""}}); err != nil {
+			return err
+		}
+		report.AuditEntries += scrubbed.ModifiedCount
+		return nil
+	})
+	if err != nil {
+		staged.rollback()
+		return model.ErasureReport{}, apperror.From(err)
+	}
+	staged.commit()
+	return report, nil
+}
+
+// anonymise menghapus identitas alumni dan deskripsi pekerjaannya, lalu
+// mengganti seluruh riwayat versinya dengan versi hasil anonimisasi.
+func (r *AlumniRepository) anonymise(ctx context.Context, id, by primitive.ObjectID, report *model.ErasureReport) error {
+	now := time.Now().Unix()
+	update := bson.M{
+		"$set": bson.M{
+			"nama":       model.ErasedName,
+			"email":      "",
+			"no_telp":    "",
+			"alamat":     "",
+			"erased_at":  now,
+			"updated_at": now,
+		},
+		"$unset": bson.M{"nim": "", "email_bidx": "", "search": ""},
+		"$inc":   bson.M{"revision": 1},
+	}
+
+	var a model.Alumni
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if err := r.Col.FindOneAndUpdate(ctx, bson.M{"_id": id}, update, opts).Decode(&a); err != nil {
+		return mapFindError(err, ErrAlumniNotFound)
+	}
+	report.Alumni = 1
+
+	jobs, err := r.jobs().UpdateMany(ctx, bson.M{"alumni_id": id}, bson.M{
+		"$unset": bson.M{"deskripsi": "", "gaji_range_legacy": ""},
+		"$set":   bson.M{"updated_at": now},
+	})
+	if err != nil {
+		return err
+	}
+	report.Pekerjaan = jobs.ModifiedCount
+
+	revisions := r.Revisions()
+	deleted, err := revisions.Col.DeleteMany(ctx, bson.M{"alumni_id": id})
+	if err != nil {
+		return err
+	}
+	report.Revisions = deleted.DeletedCount
+	return revisions.save(ctx, a, model.AuditErase, by)
+}
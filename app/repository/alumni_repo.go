@@ -1,13 +1,19 @@
 package repository
 
 import (
+	"Mango/app/apperror"
 	model "Mango/app/Model"
+	"Mango/app/crypt"
+	"Mango/app/search"
 	"context"
+	"errors"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type AlumniRepository struct {
@@ -18,14 +24,44 @@ func NewAlumniRepository(db *mongo.Database) *AlumniRepository {
 	return &AlumniRepository{Col: db.Collection("alumni")}
 }
 
-func (r *AlumniRepository) GetAllAlumni(ctx context.Context) ([]model.Alumni, error) {
+func (r *AlumniRepository) Collection() *mongo.Collection {
+	return r.Col
+}
+
+// Indexes: NIM unik (jika diisi), pencarian berdasarkan blind index
+// email, text index untuk pencarian dan kata nama untuk kandidat salah
+// ketik.
+func (r *AlumniRepository) Indexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "nim", Value: 1}},
+			Options: options.Index().SetName("nim_unique").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"nim": bson.M{"$gt": ""}}),
+		},
+		{
+			Keys:    bson.D{{Key: "email_bidx", Value: 1}},
+			Options: options.Index().SetName("email_bidx").SetSparse(true),
+		},
+		textIndex(),
+		{
+			Keys:    bson.D{{Key: "search.name_keys", Value: 1}},
+			Options: options.Index().SetName("search_name_keys"),
+		},
+		{
+			Keys:    bson.D{{Key: "deleted_at", Value: -1}},
+			Options: options.Index().SetName("deleted_at").SetSparse(true),
+		},
+	}
+}
+
+func (r *AlumniRepository) GetAllAlumni(ctx context.Context, filter model.AlumniFilter) ([]model.Alumni, error) {
 	// Buat slice untuk menampung semua data alumni
 	var alumniList []model.Alumni
 
-	// Lakukan query untuk mengambil semua dokumen di koleksi
-	cursor, err := r.Col.Find(ctx, bson.M{})
+	// Lakukan query untuk mengambil dokumen yang sesuai filter
+	cursor, err := r.Col.Find(ctx, alumniQuery(filter))
 	if err != nil {
-		return nil, err
+		return nil, apperror.Internal(err)
 	}
 	defer cursor.Close(ctx)
 
@@ -33,60 +69,326 @@ func (r *AlumniRepository) GetAllAlumni(ctx context.Context) ([]model.Alumni, er
 	for cursor.Next(ctx) {
 		var a model.Alumni
 		if err := cursor.Decode(&a); err != nil {
-			return nil, err
+			return nil, apperror.Internal(err)
 		}
 		alumniList = append(alumniList, a)
 	}
 
 	// Cek apakah ada error selama iterasi cursor
 	if err := cursor.Err(); err != nil {
-		return nil, err
+		return nil, apperror.Internal(err)
 	}
 
 	return alumniList, nil
 }
 
+// Cursor mengembalikan cursor alumni yang sesuai filter, diurutkan per NIM,
+// untuk dibaca satu per satu (export).
+func (r *AlumniRepository) Cursor(ctx context.Context, filter model.AlumniFilter) (*mongo.Cursor, error) {
+	cursor, err := r.Col.Find(ctx, alumniQuery(filter), options.Find().SetSort(bson.D{{Key: "nim", Value: 1}}))
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return cursor, nil
+}
+
+// CursorWithCurrentJob seperti Cursor, tetapi setiap dokumen alumni
+// dilengkapi field current_job: pekerjaan yang belum selesai dengan
+// tanggal mulai paling akhir (null jika tidak ada).
+func (r *AlumniRepository) CursorWithCurrentJob(ctx context.Context, filter model.AlumniFilter) (*mongo.Cursor, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: alumniQuery(filter)}},
+		{{Key: "$sort", Value: bson.D{{Key: "nim", Value: 1}}}},
+		lookupJobs("current_job", currentJobFilter(),
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "tanggal_kerja", Value: -1}}}},
+			bson.D{{Key: "$limit", Value: 1}},
+		),
+		{{Key: "$set", Value: bson.M{"current_job": bson.M{"$first": "$current_job"}}}},
+	}
+
+	cursor, err := r.Col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return cursor, nil
+}
+
 func (r *AlumniRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Alumni, error) {
 	var a model.Alumni
-	err := r.Col.FindOne(ctx, bson.M{"_id": id}).Decode(&a)
-	return &a, err
+	err := r.Col.FindOne(ctx, withID(active(), id)).Decode(&a)
+	if err != nil {
+		return nil, mapFindError(err, ErrAlumniNotFound)
+	}
+	return &a, nil
+}
+
+// FindByNIM mencari alumni aktif berdasarkan NIM (huruf besar, seperti
+// yang disimpan).
+func (r *AlumniRepository) FindByNIM(ctx context.Context, nim string) (*model.Alumni, error) {
+	nim = strings.ToUpper(strings.TrimSpace(nim))
+	if nim == "" {
+		return nil, ErrAlumniNotFound()
+	}
+	var a model.Alumni
+	filter := active()
+	filter["nim"] = nim
+	if err := r.Col.FindOne(ctx, filter).Decode(&a); err != nil {
+		return nil, mapFindError(err, ErrAlumniNotFound)
+	}
+	return &a, nil
+}
+
+// FindByEmail mencari alumni aktif lewat blind index email. Email tidak
+// unik di alumni, jadi alumni hanya dikembalikan jika tepat satu yang
+// cocok.
+func (r *AlumniRepository) FindByEmail(ctx context.Context, email string) (*model.Alumni, error) {
+	idx := crypt.EmailIndex(email)
+	if idx == "" {
+		return nil, ErrAlumniNotFound()
+	}
+	filter := active()
+	filter["email_bidx"] = idx
+	var found []model.Alumni
+	cursor, err := r.Col.Find(ctx, filter, options.Find().SetLimit(2))
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	if err := cursor.All(ctx, &found); err != nil {
+		return nil, apperror.Internal(err)
+	}
+	if len(found) != 1 {
+		return nil, ErrAlumniNotFound()
+	}
+	return &found[0], nil
 }
 
-func (r *AlumniRepository) Create(ctx context.Context, alum *model.Alumni) error {
+// Create menyimpan alumni baru beserta versi pertamanya. by adalah user
+// yang membuat (boleh NilObjectID).
+func (r *AlumniRepository) Create(ctx context.Context, alum *model.Alumni, by primitive.ObjectID) error {
 	alum.ID = primitive.NewObjectID()
 	alum.CreatedAt = time.Now().Unix()
-	_, err := r.Col.InsertOne(ctx, alum)
-	return err
+	alum.Revision = 1
+	alum.EmailIndex = crypt.EmailIndex(string(alum.Email))
+	alum.Search = &model.AlumniSearch{NameKeys: search.Keys(alum.Nama)}
+	return RunInTransaction(ctx, r.Col.Database(), func(ctx context.Context) error {
+		if _, err := r.Col.InsertOne(ctx, alum); err != nil {
+			return r.nimConflict(ctx, alum.NIM, err)
+		}
+		return r.Revisions().save(ctx, *alum, model.AuditCreate, by)
+	})
 }
 
-func (r *AlumniRepository) Update(ctx context.Context, id primitive.ObjectID, alum *model.Alumni) error {
+// Update menimpa field alumni dan menyimpan hasilnya sebagai versi baru.
+// Dokumen setelah diperbarui dikembalikan.
+func (r *AlumniRepository) Update(ctx context.Context, id primitive.ObjectID, alum *model.Alumni, by primitive.ObjectID) (*model.Alumni, error) {
+	return r.update(ctx, id, alum, by, model.AuditUpdate)
+}
+
+// Revert mengembalikan field alumni ke isi versi tertentu. Hasilnya
+// disimpan sebagai versi baru sehingga riwayat tidak hilang.
+func (r *AlumniRepository) Revert(ctx context.Context, id primitive.ObjectID, version int, by primitive.ObjectID) (*model.Alumni, error) {
+	rev, err := r.Revisions().Find(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	return r.update(ctx, id, &rev.Snapshot, by, model.AuditRevert)
+}
+
+func (r *AlumniRepository) update(ctx context.Context, id primitive.ObjectID, alum *model.Alumni, by primitive.ObjectID, action string) (*model.Alumni, error) {
 	update := bson.M{
 		"$set": bson.M{
-			"nim":        alum.NIM,
-			"nama":       alum.Nama,
-			"jurusan":    alum.Jurusan,
-			"updated_at": time.Now().Unix(),
+			"nim":              alum.NIM,
+			"nama":             alum.Nama,
+			"jurusan":          alum.Jurusan,
+			"angkatan":         alum.Angkatan,
+			"tahun_lulus":      alum.Tahun_lulus,
+			"email":            alum.Email,
+			"email_bidx":       crypt.EmailIndex(string(alum.Email)),
+			"no_telp":          alum.No_telp,
+			"alamat":           alum.Alamat,
+			"updated_at":       time.Now().Unix(),
+			"search.name_keys": search.Keys(alum.Nama),
 		},
 	}
+	setVocabCodes(update, alum)
+	update["$inc"] = bson.M{"revision": 1}
 
-	_, err := r.Col.UpdateOne(ctx, bson.M{"_id": id}, update)
-	return err
+	var after model.Alumni
+	err := RunInTransaction(ctx, r.Col.Database(), func(ctx context.Context) error {
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+		err := r.Col.FindOneAndUpdate(ctx, withID(active(), id), update, opts).Decode(&after)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrAlumniNotFound()
+		}
+		if err != nil {
+			return r.nimConflict(ctx, alum.NIM, err)
+		}
+		return r.Revisions().save(ctx, after, action, by)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &after, nil
 }
 
-func (r *AlumniRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
-	// Filter berdasarkan _id
-	filter := bson.M{"_id": id}
+// UpdatePrivacy menyimpan pengaturan privasi alumni sebagai versi baru.
+func (r *AlumniRepository) UpdatePrivacy(ctx context.Context, id primitive.ObjectID, p model.AlumniPrivacy, by primitive.ObjectID) (*model.Alumni, error) {
+	update := bson.M{
+		"$set": bson.M{"privacy": p, "updated_at": time.Now().Unix()},
+		"$inc": bson.M{"revision": 1},
+	}
+
+	var after model.Alumni
+	err := RunInTransaction(ctx, r.Col.Database(), func(ctx context.Context) error {
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+		err := r.Col.FindOneAndUpdate(ctx, withID(active(), id), update, opts).Decode(&after)
+		if err != nil {
+			return mapFindError(err, ErrAlumniNotFound)
+		}
+		return r.Revisions().save(ctx, after, model.AuditUpdate, by)
+	})
+	if err != nil {
+		return nil, apperror.From(err)
+	}
+	return &after, nil
+}
+
+// ExistingNIMs mengembalikan NIM dari daftar yang sudah tersimpan.
+func (r *AlumniRepository) ExistingNIMs(ctx context.Context, nims []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(nims) == 0 {
+		return existing, nil
+	}
 
-	// Jalankan operasi DeleteOne
-	result, err := r.Col.DeleteOne(ctx, filter)
+	opts := options.Find().SetProjection(bson.M{"nim": 1})
+	filter := active()
+	filter["nim"] = bson.M{"$in": nims}
+	cursor, err := r.Col.Find(ctx, filter, opts)
 	if err != nil {
-		return err
+		return nil, apperror.Internal(err)
 	}
+	defer cursor.Close(ctx)
 
-	// Jika tidak ada dokumen yang dihapus
-	if result.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
+	for cursor.Next(ctx) {
+		var a model.Alumni
+		if err := cursor.Decode(&a); err != nil {
+			return nil, apperror.Internal(err)
+		}
+		existing[a.NIM] = true
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, apperror.Internal(err)
 	}
+	return existing, nil
+}
+
+// UpsertByNIM membuat alumni baru atau memperbarui alumni dengan NIM
+// yang sama, dan mengembalikan dokumen sebelum diperbarui (nil jika
+// dokumen baru dibuat). Alumni di tempat sampah tidak ditimpa; NIM-nya
+// dilaporkan sebagai konflik. Hasilnya disimpan sebagai versi baru.
+func (r *AlumniRepository) UpsertByNIM(ctx context.Context, alum *model.Alumni, by primitive.ObjectID) (before *model.Alumni, err error) {
+	newID := primitive.NewObjectID()
+	update := upsertByNIMUpdate(alum, newID, time.Now().Unix())
 
-	return nil
+	filter := active()
+	filter["nim"] = alum.NIM
+	err = RunInTransaction(ctx, r.Col.Database(), func(ctx context.Context) error {
+		before = nil
+		var prev model.Alumni
+		opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before)
+		err := r.Col.FindOneAndUpdate(ctx, filter, update, opts).Decode(&prev)
+		action := model.AuditUpdate
+		switch {
+		case errors.Is(err, mongo.ErrNoDocuments):
+			alum.ID = newID
+			action = model.AuditCreate
+		case err != nil:
+			return r.nimConflict(ctx, alum.NIM, err)
+		default:
+			alum.ID = prev.ID
+			before = &prev
+		}
+
+		var after model.Alumni
+		if err := r.Col.FindOne(ctx, bson.M{"_id": alum.ID}).Decode(&after); err != nil {
+			return apperror.Internal(err)
+		}
+		return r.Revisions().save(ctx, after, action, by)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return before, nil
+}
+
+// upsertByNIMUpdate adalah update UpsertByNIM; newID dan now hanya
+// dipakai jika dokumen baru dibuat.
+func upsertByNIMUpdate(alum *model.Alumni, newID primitive.ObjectID, now int64) bson.M {
+	update := bson.M{
+		"$set": bson.M{
+			"nama":             alum.Nama,
+			"jurusan":          alum.Jurusan,
+			"angkatan":         alum.Angkatan,
+			"tahun_lulus":      alum.Tahun_lulus,
+			"email":            alum.Email,
+			"email_bidx":       crypt.EmailIndex(string(alum.Email)),
+			"no_telp":          alum.No_telp,
+			"alamat":           alum.Alamat,
+			"updated_at":       now,
+			"search.name_keys": search.Keys(alum.Nama),
+		},
+		"$setOnInsert": bson.M{"_id": newID, "created_at": now},
+	}
+	setVocabCodes(update, alum)
+	update["$inc"] = bson.M{"revision": 1}
+	return update
+}
+
+// nimConflict memetakan error tulis; pelanggaran NIM unik oleh alumni di
+// tempat sampah dilaporkan terpisah agar admin tahu harus me-restore.
+func (r *AlumniRepository) nimConflict(ctx context.Context, nim string, err error) error {
+	mapped := mapWriteError(err, conflictRule{"nim_unique", ErrNIMTaken})
+	if apperror.Is(mapped, apperror.KindConflict) {
+		filter := trashed()
+		filter["nim"] = nim
+		if n, _ := r.Col.CountDocuments(ctx, filter); n > 0 {
+			return ErrNIMInTrash()
+		}
+	}
+	return mapped
+}
+
+// Revisions adalah riwayat versi alumni.
+func (r *AlumniRepository) Revisions() *AlumniRevisionRepository {
+	return NewAlumniRevisionRepository(r.Col.Database())
+}
+
+// jobs, users dan uploads adalah koleksi yang bergantung pada alumni.
+func (r *AlumniRepository) jobs() *mongo.Collection {
+	return r.Col.Database().Collection("pekerjaan_alumni")
+}
+
+func (r *AlumniRepository) users() *mongo.Collection {
+	return r.Col.Database().Collection("Users")
+}
+
+func (r *AlumniRepository) uploads() *mongo.Collection {
+	return r.Col.Database().Collection("uploads")
+}
+
+// setVocabCodes menyimpan kode jurusan dan fakultas hasil pencocokan
+// kosakata, atau menghapusnya jika jurusan tidak tertaut.
+func setVocabCodes(update bson.M, alum *model.Alumni) {
+	set := update["$set"].(bson.M)
+	unset := bson.M{}
+	for field, value := range map[string]string{"kode_jurusan": alum.KodeJurusan, "fakultas": alum.Fakultas} {
+		if value != "" {
+			set[field] = value
+		} else {
+			unset[field] = ""
+		}
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
 }
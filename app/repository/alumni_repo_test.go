@@ -0,0 +1,82 @@
+package repository
+
+import (
+	model "Mango/app/Model"
+	"Mango/app/crypt"
+	"Mango/app/search"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestUpsertByNIMUpdate(t *testing.T) {
+	k, err := crypt.NewKeyring()
+	if err != nil {
+		t.Fatal(err)
+	}
+	crypt.Use(k)
+
+	newID := primitive.NewObjectID()
+	tests := []struct {
+		name      string
+		alum      model.Alumni
+		wantKeys  []string
+		wantSet   bson.M
+		wantUnset []string
+	}{
+		{
+			name:     "renamed alumni gets new name keys",
+			alum:     model.Alumni{NIM: "081911133001", Nama: "Budhi Santosa", Email: "budi@example.com", KodeJurusan: "SI", Fakultas: "FST"},
+			wantKeys: search.Keys("Budhi Santosa"),
+			wantSet:  bson.M{"nama": "Budhi Santosa", "kode_jurusan": "SI", "fakultas": "FST"},
+		},
+		{
+			name:      "unlinked jurusan clears codes",
+			alum:      model.Alumni{NIM: "081911133002", Nama: "Siti  Aminah", Jurusan: "Teknik Antah"},
+			wantKeys:  []string{"siti", "aminah"},
+			wantSet:   bson.M{"nama": "Siti  Aminah", "jurusan": "Teknik Antah"},
+			wantUnset: []string{"kode_jurusan", "fakultas"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			update := upsertByNIMUpdate(&tt.alum, newID, 1700000000)
+
+			set := update["$set"].(bson.M)
+			if got := set["search.name_keys"]; !reflect.DeepEqual(got, tt.wantKeys) {
+				t.Errorf("search.name_keys = %v, want %v", got, tt.wantKeys)
+			}
+			for field, want := range tt.wantSet {
+				if set[field] != want {
+					t.Errorf("$set.%s = %v, want %v", field, set[field], want)
+				}
+			}
+			if set["email_bidx"] != crypt.EmailIndex(string(tt.alum.Email)) {
+				t.Error("email_bidx does not match the email")
+			}
+			if _, ok := set["nim"]; ok {
+				t.Error("nim is set; it comes from the filter")
+			}
+
+			unset, _ := update["$unset"].(bson.M)
+			if len(unset) != len(tt.wantUnset) {
+				t.Errorf("$unset = %v, want %v", unset, tt.wantUnset)
+			}
+			for _, field := range tt.wantUnset {
+				if _, ok := unset[field]; !ok {
+					t.Errorf("%s not unset", field)
+				}
+			}
+
+			insert := update["$setOnInsert"].(bson.M)
+			if insert["_id"] != newID || insert["created_at"] != int64(1700000000) {
+				t.Errorf("$setOnInsert = %v", insert)
+			}
+			if !reflect.DeepEqual(update["$inc"], bson.M{"revision": 1}) {
+				t.Errorf("$inc = %v", update["$inc"])
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AuditRepository struct {
+	Col *mongo.Collection
+}
+
+func NewAuditRepository(db *mongo.Database) *AuditRepository {
+	return &AuditRepository{Col: db.Collection("audit_log")}
+}
+
+func (r *AuditRepository) Collection() *mongo.Collection {
+	return r.Col
+}
+
+// Indexes: audit dicari per entitas, per actor dan per rentang waktu.
+func (r *AuditRepository) Indexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "entity", Value: 1}, {Key: "entity_id", Value: 1}, {Key: "at", Value: -1}},
+			Options: options.Index().SetName("entity_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "actor_id", Value: 1}, {Key: "at", Value: -1}},
+			Options: options.Index().SetName("actor_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "at", Value: -1}},
+			Options: options.Index().SetName("at"),
+		},
+	}
+}
+
+func (r *AuditRepository) Create(ctx context.Context, e *model.AuditEntry) error {
+	if _, err := r.Col.InsertOne(ctx, e); err != nil {
+		return apperror.Internal(err)
+	}
+	return nil
+}
+
+// List mengembalikan entri yang cocok filter, yang terbaru lebih dulu.
+func (r *AuditRepository) List(ctx context.Context, f model.AuditFilter) ([]model.AuditEntry, error) {
+	q := bson.M{}
+	if f.Entity != "" {
+		q["entity"] = f.Entity
+	}
+	if !f.EntityID.IsZero() {
+		q["entity_id"] = f.EntityID
+	}
+	if !f.ActorID.IsZero() {
+		q["actor_id"] = f.ActorID
+	} else if f.Actor != "" {
+		q["actor"] = f.Actor
+	}
+	if f.Action != "" {
+		q["action"] = f.Action
+	}
+	at := bson.M{}
+	if !f.From.IsZero() {
+		at["$gte"] = f.From
+	}
+	if !f.To.IsZero() {
+		at["$lt"] = f.To
+	}
+	if len(at) > 0 {
+		q["at"] = at
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "at", Value: -1}}).SetLimit(int64(f.Limit))
+	cursor, err := r.Col.Find(ctx, q, opts)
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+
+	result := []model.AuditEntry{}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return result, nil
+}
+
+// Purge menghapus entri yang lebih lama dari before.
+func (r *AuditRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.Col.DeleteMany(ctx, bson.M{"at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, apperror.Internal(err)
+	}
+	return result.DeletedCount, nil
+}
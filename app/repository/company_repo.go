@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CompanyRepository struct {
+	Col *mongo.Collection
+}
+
+func NewCompanyRepository(db *mongo.Database) *CompanyRepository {
+	return &CompanyRepository{Col: db.Collection("companies")}
+}
+
+func (r *CompanyRepository) Collection() *mongo.Collection {
+	return r.Col
+}
+
+// Indexes: nama ternormalisasi harus unik; alias dicari saat menautkan
+// pekerjaan ke perusahaan.
+func (r *CompanyRepository) Indexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "name_key", Value: 1}},
+			Options: options.Index().SetName("name_key_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "alias_keys", Value: 1}},
+			Options: options.Index().SetName("alias_keys"),
+		},
+	}
+}
+
+// List mengembalikan perusahaan urut nama; q mencari di nama dan alias.
+func (r *CompanyRepository) List(ctx context.Context, q string) ([]model.Company, error) {
+	filter := bson.M{}
+	if q != "" {
+		re := containsCI(q)
+		filter["$or"] = bson.A{bson.M{"name": re}, bson.M{"aliases": re}}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	return r.find(ctx, filter, opts)
+}
+
+// Candidates mengembalikan nama dan alias semua perusahaan untuk
+// pencocokan fuzzy. Direktori perusahaan cukup kecil untuk dinilai di
+// memori.
+func (r *CompanyRepository) Candidates(ctx context.Context) ([]model.Company, error) {
+	opts := options.Find().SetProjection(bson.M{"name": 1, "aliases": 1, "name_key": 1, "alias_keys": 1})
+	return r.find(ctx, bson.M{}, opts)
+}
+
+func (r *CompanyRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Company, error) {
+	var company model.Company
+	if err := r.Col.FindOne(ctx, bson.M{"_id": id}).Decode(&company); err != nil {
+		return nil, mapFindError(err, ErrCompanyNotFound)
+	}
+	return &company, nil
+}
+
+// FindByName mencari perusahaan yang nama atau aliasnya sama dengan name
+// setelah dinormalisasi.
+func (r *CompanyRepository) FindByName(ctx context.Context, name string) (*model.Company, error) {
+	key := model.CompanyKey(name)
+	if key == "" {
+		return nil, ErrCompanyNotFound()
+	}
+	var company model.Company
+	err := r.Col.FindOne(ctx, bson.M{"$or": bson.A{bson.M{"name_key": key}, bson.M{"alias_keys": key}}}).Decode(&company)
+	if err != nil {
+		return nil, mapFindError(err, ErrCompanyNotFound)
+	}
+	return &company, nil
+}
+
+func (r *CompanyRepository) Create(ctx context.Context, c *model.Company) error {
+	c.ID = primitive.NewObjectID()
+	c.SetKeys()
+	c.CreatedAt = time.Now().Unix()
+	c.UpdatedAt = c.CreatedAt
+	if _, err := r.Col.InsertOne(ctx, c); err != nil {
+		return mapWriteError(err, conflictRule{"name_key_unique", ErrCompanyExists})
+	}
+	return nil
+}
+
+func (r *CompanyRepository) Update(ctx context.Context, id primitive.ObjectID, c *model.Company) error {
+	c.SetKeys()
+	update := bson.M{"$set": bson.M{
+		"name":       c.Name,
+		"aliases":    c.Aliases,
+		"industry":   c.Industry,
+		"location":   c.Location,
+		"website":    c.Website,
+		"name_key":   c.NameKey,
+		"alias_keys": c.AliasKeys,
+		"updated_at": time.Now().Unix(),
+	}}
+	result, err := r.Col.UpdateByID(ctx, id, update)
+	if err != nil {
+		return mapWriteError(err, conflictRule{"name_key_unique", ErrCompanyExists})
+	}
+	if result.MatchedCount == 0 {
+		return ErrCompanyNotFound()
+	}
+	return nil
+}
+
+// AddAliases menambahkan alias (beserta key-nya) tanpa duplikat.
+func (r *CompanyRepository) AddAliases(ctx context.Context, id primitive.ObjectID, aliases []string) error {
+	c, err := r.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	seen := map[string]bool{c.NameKey: true}
+	for _, k := range c.AliasKeys {
+		seen[k] = true
+	}
+	for _, a := range aliases {
+		if k := model.CompanyKey(a); k != "" && !seen[k] {
+			seen[k] = true
+			c.Aliases = append(c.Aliases, a)
+		}
+	}
+	return r.Update(ctx, id, c)
+}
+
+func (r *CompanyRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.Col.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return apperror.Internal(err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrCompanyNotFound()
+	}
+	return nil
+}
+
+func (r *CompanyRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]model.Company, error) {
+	cursor, err := r.Col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	companies := []model.Company{}
+	if err := cursor.All(ctx, &companies); err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return companies, nil
+}
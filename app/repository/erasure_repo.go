@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErasureRepository menyimpan permintaan penghapusan data pribadi.
+type ErasureRepository struct {
+	Col *mongo.Collection
+}
+
+func NewErasureRepository(db *mongo.Database) *ErasureRepository {
+	return &ErasureRepository{Col: db.Collection("erasure_requests")}
+}
+
+func (r *ErasureRepository) Collection() *mongo.Collection {
+	return r.Col
+}
+
+// Indexes: satu permintaan pending per user; admin melihat per status.
+func (r *ErasureRepository) Indexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("user_pending_unique").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": model.ErasurePending}),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "requested_at", Value: -1}},
+			Options: options.Index().SetName("status_requested_at"),
+		},
+	}
+}
+
+func (r *ErasureRepository) Create(ctx context.Context, req *model.ErasureRequest) error {
+	req.ID = primitive.NewObjectID()
+	req.Status = model.ErasurePending
+	req.RequestedAt = time.Now().UTC()
+	if _, err := r.Col.InsertOne(ctx, req); err != nil {
+		return mapWriteError(err, conflictRule{"user_pending_unique", ErrErasurePending})
+	}
+	return nil
+}
+
+func (r *ErasureRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.ErasureRequest, error) {
+	var req model.ErasureRequest
+	if err := r.Col.FindOne(ctx, bson.M{"_id": id}).Decode(&req); err != nil {
+		return nil, mapFindError(err, ErrErasureRequestNotFound)
+	}
+	return &req, nil
+}
+
+// List mengembalikan permintaan dengan status tertentu (semua jika
+// kosong) milik userID (semua user jika NilObjectID), terbaru lebih dulu.
+func (r *ErasureRepository) List(ctx context.Context, userID primitive.ObjectID, status string, limit int) ([]model.ErasureRequest, error) {
+	filter := bson.M{}
+	if !userID.IsZero() {
+		filter["user_id"] = userID
+	}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "requested_at", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := r.Col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+
+	result := []model.ErasureRequest{}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return result, nil
+}
+
+// Decide menutup permintaan yang masih pending dengan status akhir.
+func (r *ErasureRepository) Decide(ctx context.Context, id primitive.ObjectID, status string, by primitive.ObjectID, note string, report *model.ErasureReport) (*model.ErasureRequest, error) {
+	set := bson.M{"status": status, "decided_at": time.Now().UTC(), "decided_by": by}
+	if note != "" {
+		set["note"] = note
+	}
+	if report != nil {
+		set["report"] = report
+	}
+
+	var req model.ErasureRequest
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := r.Col.FindOneAndUpdate(ctx, bson.M{"_id": id, "status": model.ErasurePending}, bson.M{"$set": set}, opts).Decode(&req)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if _, err := r.FindByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrErasureNotPending()
+	}
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return &req, nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Error domain yang dikembalikan repository ketika dokumen tidak ditemukan.
+func ErrAlumniNotFound() *apperror.Error {
+	return apperror.NotFound("alumni_not_found", "Alumni not found")
+}
+
+func ErrPekerjaanNotFound() *apperror.Error {
+	return apperror.NotFound("pekerjaan_not_found", "Pekerjaan not found")
+}
+
+func ErrNoCurrentJob() *apperror.Error {
+	return apperror.NotFound("no_current_job", "Alumni has no current job")
+}
+
+func ErrCompanyNotFound() *apperror.Error {
+	return apperror.NotFound("company_not_found", "Company not found")
+}
+
+func ErrTermNotFound() *apperror.Error {
+	return apperror.NotFound("term_not_found", "Vocabulary term not found")
+}
+
+func ErrAlumniNotInTrash() *apperror.Error {
+	return apperror.NotFound("alumni_not_in_trash", "Alumni not found in trash")
+}
+
+func ErrPekerjaanNotInTrash() *apperror.Error {
+	return apperror.NotFound("pekerjaan_not_in_trash", "Pekerjaan not found in trash")
+}
+
+func ErrRevisionNotFound() *apperror.Error {
+	return apperror.NotFound("revision_not_found", "Alumni revision not found")
+}
+
+// ErrNoRevisionAsOf dikembalikan jika alumni belum ada (atau sudah
+// dihapus) pada waktu yang diminta.
+func ErrNoRevisionAsOf() *apperror.Error {
+	return apperror.NotFound("no_revision_as_of", "Alumni did not exist at the requested time")
+}
+
+func ErrErasureRequestNotFound() *apperror.Error {
+	return apperror.NotFound("erasure_request_not_found", "Erasure request not found")
+}
+
+func ErrUserNotFound() *apperror.Error {
+	return apperror.NotFound("user_not_found", "User not found")
+}
+
+// ErrTokenInvalid dikembalikan jika token verifikasi atau reset tidak
+// ada, kedaluwarsa atau sudah dipakai.
+func ErrTokenInvalid() *apperror.Error {
+	return apperror.Validation("invalid_token", "Token is invalid or has expired")
+}
+
+// Error domain untuk pelanggaran index unik.
+// ErrPasswordTooLong dikembalikan jika password melebihi batas bcrypt.
+func ErrPasswordTooLong() *apperror.Error {
+	return apperror.Validation("validation_failed", "Request validation failed", apperror.FieldError{
+		Field:   "password",
+		Code:    "max",
+		Message: fmt.Sprintf("must be at most %d bytes", model.MaxPasswordLength),
+	})
+}
+
+func ErrUsernameTaken() *apperror.Error {
+	return apperror.Conflict("username_taken", "Username is already registered")
+}
+
+func ErrEmailTaken() *apperror.Error {
+	return apperror.Conflict("email_taken", "Email is already registered")
+}
+
+// ErrSSOAlreadyLinked dikembalikan jika akun SSO sudah dipakai user lain
+// atau user sudah terhubung dengan akun SSO lain.
+func ErrSSOAlreadyLinked() *apperror.Error {
+	return apperror.Conflict("sso_already_linked", "Account is already linked to a different single sign-on identity")
+}
+
+func ErrNIMTaken() *apperror.Error {
+	return apperror.Conflict("nim_taken", "An alumni with this NIM already exists")
+}
+
+func ErrNIMInTrash() *apperror.Error {
+	return apperror.Conflict("nim_in_trash", "An alumni with this NIM is in the trash; restore it instead")
+}
+
+// ErrAlumniDeleted dikembalikan saat memulihkan pekerjaan milik alumni
+// yang masih di tempat sampah.
+func ErrAlumniDeleted() *apperror.Error {
+	return apperror.Conflict("alumni_deleted", "The alumni of this pekerjaan is in the trash; restore the alumni first")
+}
+
+// ErrAlumniHasDependents dikembalikan oleh policy block.
+func ErrAlumniHasDependents(d model.Dependents) *apperror.Error {
+	return apperror.Conflict("alumni_has_dependents", fmt.Sprintf(
+		"Alumni still has %d pekerjaan, %d linked user account(s) and %d upload(s); remove them first or use policy cascade or detach",
+		d.Pekerjaan, d.Users, d.Uploads))
+}
+
+func ErrErasurePending() *apperror.Error {
+	return apperror.Conflict("erasure_pending", "You already have a pending erasure request")
+}
+
+// ErrErasureNotPending dikembalikan saat memutuskan permintaan yang sudah
+// disetujui atau ditolak.
+func ErrErasureNotPending() *apperror.Error {
+	return apperror.Conflict("erasure_not_pending", "Erasure request has already been decided")
+}
+
+// ErrAlumniAlreadyLinked dikembalikan saat menautkan alumni yang sudah
+// tertaut ke user lain.
+func ErrAlumniAlreadyLinked() *apperror.Error {
+	return apperror.Conflict("alumni_already_linked", "Alumni is already linked to another user")
+}
+
+func ErrEmailAlreadyVerified() *apperror.Error {
+	return apperror.Conflict("email_already_verified", "Email is already verified")
+}
+
+func ErrMFAAlreadyEnabled() *apperror.Error {
+	return apperror.Conflict("mfa_already_enabled", "Two-factor authentication is already enabled")
+}
+
+func ErrCompanyExists() *apperror.Error {
+	return apperror.Conflict("company_exists", "A company with this name already exists")
+}
+
+func ErrTermCodeTaken() *apperror.Error {
+	return apperror.Conflict("term_code_taken", "A term with this code already exists")
+}
+
+// mapFindError menerjemahkan mongo.ErrNoDocuments menjadi error not found
+// dan error lain menjadi error internal.
+func mapFindError(err error, notFound func() *apperror.Error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return notFound()
+	}
+	return apperror.Internal(err)
+}
+
+// conflictRule memetakan nama index unik ke error conflict-nya.
+type conflictRule struct {
+	index string
+	err   func() *apperror.Error
+}
+
+// mapWriteError menerjemahkan duplicate key error menjadi 409 Conflict
+// berdasarkan nama index yang dilanggar; error lain menjadi internal.
+func mapWriteError(err error, rules ...conflictRule) error {
+	if !mongo.IsDuplicateKeyError(err) {
+		return apperror.Internal(err)
+	}
+	for _, rule := range rules {
+		if strings.Contains(err.Error(), rule.index) {
+			e := rule.err()
+			e.Err = err
+			return e
+		}
+	}
+	return &apperror.Error{Kind: apperror.KindConflict, Code: "duplicate", Message: "Record already exists", Err: err}
+}
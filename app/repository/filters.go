@@ -0,0 +1,87 @@
+package repository
+
+import (
+	model "Mango/app/Model"
+	"Mango/app/crypt"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// containsCI membuat kondisi regex "mengandung" yang tidak peka huruf besar.
+func containsCI(s string) primitive.Regex {
+	return primitive.Regex{Pattern: regexp.QuoteMeta(s), Options: "i"}
+}
+
+// alumniQuery menerjemahkan AlumniFilter menjadi filter MongoDB. Email
+// terenkripsi sehingga hanya bisa dicari persis lewat blind index.
+func alumniQuery(f model.AlumniFilter) bson.M {
+	q := active()
+	if f.Q != "" {
+		re := containsCI(f.Q)
+		q["$or"] = bson.A{
+			bson.M{"nama": re},
+			bson.M{"nim": re},
+			bson.M{"email_bidx": crypt.EmailIndex(f.Q)},
+		}
+	}
+	if f.Jurusan != "" {
+		q["jurusan"] = f.Jurusan
+	}
+	if f.Angkatan != 0 {
+		q["angkatan"] = f.Angkatan
+	}
+	if f.TahunLulus != 0 {
+		q["tahun_lulus"] = f.TahunLulus
+	}
+	return q
+}
+
+// active adalah kondisi dokumen yang tidak ada di tempat sampah. Semua
+// query alumni dan pekerjaan untuk pengguna dimulai dari sini.
+func active() bson.M {
+	return bson.M{"deleted_at": bson.M{"$exists": false}}
+}
+
+// trashed adalah kondisi dokumen di tempat sampah.
+func trashed() bson.M {
+	return bson.M{"deleted_at": bson.M{"$exists": true}}
+}
+
+// withID menambahkan _id ke filter.
+func withID(filter bson.M, id primitive.ObjectID) bson.M {
+	filter["_id"] = id
+	return filter
+}
+
+// currentJobFilter adalah kondisi pekerjaan yang masih berjalan: belum
+// punya tanggal_selesai atau tanggal_selesai belum lewat. Studi lanjut
+// tidak dihitung sebagai pekerjaan.
+func currentJobFilter() bson.M {
+	q := active()
+	q["$or"] = bson.A{
+		bson.M{"tanggal_selesai": nil},
+		bson.M{"tanggal_selesai": bson.M{"$gte": model.CurrentMonth().Time}},
+	}
+	q["status"] = bson.M{"$ne": model.StatusFurtherStudy}
+	return q
+}
+
+// pekerjaanQuery menerjemahkan PekerjaanFilter menjadi filter MongoDB.
+func pekerjaanQuery(f model.PekerjaanFilter) bson.M {
+	q := active()
+	if id, err := primitive.ObjectIDFromHex(f.AlumniID); err == nil {
+		q["alumni_id"] = id
+	}
+	if f.Perusahaan != "" {
+		q["nama_perusahaan"] = containsCI(f.Perusahaan)
+	}
+	if f.BidangIndustri != "" {
+		q["bidang_industri"] = f.BidangIndustri
+	}
+	if f.LokasiKerja != "" {
+		q["lokasi_kerja"] = f.LokasiKerja
+	}
+	return q
+}
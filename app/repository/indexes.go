@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// IndexedRepository dimiliki repository yang mendeklarasikan index
+// untuk koleksinya sendiri.
+type IndexedRepository interface {
+	Collection() *mongo.Collection
+	Indexes() []mongo.IndexModel
+}
+
+// EnsureIndexes membuat index yang dideklarasikan setiap repository.
+// Aman dipanggil berulang kali; index yang sudah ada dengan spesifikasi
+// sama tidak dibuat ulang. Index dibuat satu per satu agar satu index
+// yang gagal (misalnya index unik di atas data ganda) tidak menghalangi
+// yang lain; semua kegagalan dikembalikan bersama.
+func EnsureIndexes(ctx context.Context, repos ...IndexedRepository) error {
+	var failed []error
+	for _, r := range repos {
+		col := r.Collection()
+		var names []string
+		for _, m := range r.Indexes() {
+			name, err := col.Indexes().CreateOne(ctx, m)
+			if err != nil {
+				failed = append(failed, fmt.Errorf("index %s on %s: %w", indexName(m), col.Name(), err))
+				continue
+			}
+			names = append(names, name)
+		}
+		if len(names) > 0 {
+			log.Printf("✅ Indexes on %s: %v", col.Name(), names)
+		}
+	}
+	return errors.Join(failed...)
+}
+
+// indexName adalah nama index yang dideklarasikan, atau key-nya jika
+// tidak diberi nama.
+func indexName(m mongo.IndexModel) string {
+	if m.Options != nil && m.Options.Name != nil {
+		return *m.Options.Name
+	}
+	return fmt.Sprint(m.Keys)
+}
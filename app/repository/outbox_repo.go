@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// sentRetention adalah lama email terkirim disimpan di outbox.
+const sentRetention = 30 * 24 * time.Hour
+
+// OutboxRepository menyimpan email yang menunggu dikirim. Pengirim di
+// background mengambil email satu per satu dengan Claim, sehingga
+// beberapa replica bisa mengirim bersamaan tanpa mengirim dua kali.
+type OutboxRepository struct {
+	Col *mongo.Collection
+}
+
+func NewOutboxRepository(db *mongo.Database) *OutboxRepository {
+	return &OutboxRepository{Col: db.Collection("email_outbox")}
+}
+
+func (r *OutboxRepository) Collection() *mongo.Collection {
+	return r.Col
+}
+
+// Indexes: antrean per status dan waktu kirim; email terkirim dihapus TTL
+// setelah sentRetention.
+func (r *OutboxRepository) Indexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+			Options: options.Index().SetName("status_next_attempt"),
+		},
+		{
+			Keys:    bson.D{{Key: "sent_at", Value: 1}},
+			Options: options.Index().SetName("sent_at_ttl").SetExpireAfterSeconds(int32(sentRetention.Seconds())),
+		},
+	}
+}
+
+// Enqueue menambahkan email ke antrean untuk segera dikirim.
+func (r *OutboxRepository) Enqueue(ctx context.Context, e *model.OutboxEmail) error {
+	now := time.Now().UTC()
+	e.ID = primitive.NewObjectID()
+	e.Status = model.EmailPending
+	e.Attempts = 0
+	e.CreatedAt = now
+	e.NextAttemptAt = now
+	if _, err := r.Col.InsertOne(ctx, e); err != nil {
+		return apperror.Internal(err)
+	}
+	return nil
+}
+
+// Claim mengambil satu email yang sudah waktunya dikirim dan menunda
+// percobaan berikutnya selama lease. Jika pengirim berhenti sebelum
+// MarkSent atau MarkFailed, email diambil lagi setelah lease habis.
+// Mengembalikan nil jika antrean kosong.
+func (r *OutboxRepository) Claim(ctx context.Context, lease time.Duration) (*model.OutboxEmail, error) {
+	now := time.Now().UTC()
+	filter := bson.M{"status": model.EmailPending, "next_attempt_at": bson.M{"$lte": now}}
+	update := bson.M{
+		"$set": bson.M{"next_attempt_at": now.Add(lease)},
+		"$inc": bson.M{"attempts": 1},
+	}
+
+	var e model.OutboxEmail
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+	err := r.Col.FindOneAndUpdate(ctx, filter, update, opts).Decode(&e)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return &e, nil
+}
+
+func (r *OutboxRepository) MarkSent(ctx context.Context, id primitive.ObjectID) error {
+	update := bson.M{
+		"$set":   bson.M{"status": model.EmailSent, "sent_at": time.Now().UTC()},
+		"$unset": bson.M{"last_error": ""},
+	}
+	if _, err := r.Col.UpdateByID(ctx, id, update); err != nil {
+		return apperror.Internal(err)
+	}
+	return nil
+}
+
+// MarkFailed mencatat kegagalan kirim. Jika retryAt nil email tidak
+// dicoba lagi (status failed).
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id primitive.ObjectID, reason string, retryAt *time.Time) error {
+	set := bson.M{"last_error": reason}
+	if retryAt != nil {
+		set["next_attempt_at"] = retryAt.UTC()
+	} else {
+		set["status"] = model.EmailFailed
+	}
+	if _, err := r.Col.UpdateByID(ctx, id, bson.M{"$set": set}); err != nil {
+		return apperror.Internal(err)
+	}
+	return nil
+}
+
+// Retry mengantrekan ulang email berstatus failed dan mengembalikan
+// jumlahnya.
+func (r *OutboxRepository) Retry(ctx context.Context) (int64, error) {
+	result, err := r.Col.UpdateMany(ctx, bson.M{"status": model.EmailFailed}, bson.M{
+		"$set": bson.M{"status": model.EmailPending, "attempts": 0, "next_attempt_at": time.Now().UTC()},
+	})
+	if err != nil {
+		return 0, apperror.Internal(err)
+	}
+	return result.ModifiedCount, nil
+}
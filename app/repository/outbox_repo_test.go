@@ -0,0 +1,105 @@
+package repository
+
+import (
+	model "Mango/app/Model"
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestOutboxClaim(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("claims the oldest due email", func(mt *mtest.T) {
+		id := primitive.NewObjectID()
+		claimed := bson.D{
+			{Key: "_id", Value: id},
+			{Key: "to", Value: "budi@example.com"},
+			{Key: "status", Value: model.EmailPending},
+			{Key: "attempts", Value: 1},
+		}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: claimed}})
+		r := &OutboxRepository{Col: mt.Coll}
+
+		before := time.Now()
+		e, err := r.Claim(context.Background(), 5*time.Minute)
+		if err != nil {
+			mt.Fatalf("Claim: %v", err)
+		}
+		if e == nil || e.ID != id || e.To != "budi@example.com" || e.Attempts != 1 {
+			mt.Fatalf("Claim = %+v, want the claimed email", e)
+		}
+
+		cmd := mt.GetStartedEvent().Command
+		if status := cmd.Lookup("query", "status").StringValue(); status != model.EmailPending {
+			mt.Errorf("filter status = %q, want %q", status, model.EmailPending)
+		}
+		due := cmd.Lookup("query", "next_attempt_at", "$lte").Time()
+		if due.Before(before.Truncate(time.Millisecond)) || due.After(time.Now()) {
+			mt.Errorf("filter next_attempt_at $lte = %v, want now", due)
+		}
+		// lease: email tidak diambil lagi sampai lease habis
+		next := cmd.Lookup("update", "$set", "next_attempt_at").Time()
+		if lease := next.Sub(due); lease != 5*time.Minute {
+			mt.Errorf("next_attempt_at is %v after now, want the 5m lease", lease)
+		}
+		if inc := cmd.Lookup("update", "$inc", "attempts").AsInt64(); inc != 1 {
+			mt.Errorf("$inc attempts = %d, want 1", inc)
+		}
+		if sort := cmd.Lookup("sort", "next_attempt_at").AsInt64(); sort != 1 {
+			mt.Errorf("sort next_attempt_at = %d, want 1", sort)
+		}
+		if !cmd.Lookup("new").Boolean() {
+			mt.Error("Claim must return the updated document")
+		}
+	})
+
+	mt.Run("empty queue", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+		r := &OutboxRepository{Col: mt.Coll}
+
+		e, err := r.Claim(context.Background(), time.Minute)
+		if err != nil || e != nil {
+			mt.Errorf("Claim = %+v, %v, want nil, nil", e, err)
+		}
+	})
+}
+
+func TestOutboxMarkFailed(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	retryAt := time.Now().Add(time.Minute)
+
+	tests := []struct {
+		name    string
+		retryAt *time.Time
+		status  string
+		retry   bool
+	}{
+		{"retry later", &retryAt, "", true},
+		{"give up", nil, model.EmailFailed, false},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+			r := &OutboxRepository{Col: mt.Coll}
+
+			if err := r.MarkFailed(context.Background(), primitive.NewObjectID(), "421 try later", tt.retryAt); err != nil {
+				mt.Fatalf("MarkFailed: %v", err)
+			}
+			set := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u", "$set").Document()
+			if reason := set.Lookup("last_error").StringValue(); reason != "421 try later" {
+				mt.Errorf("last_error = %q", reason)
+			}
+			if status, _ := set.Lookup("status").StringValueOK(); status != tt.status {
+				mt.Errorf("status = %q, want %q", status, tt.status)
+			}
+			if _, ok := set.Lookup("next_attempt_at").TimeOK(); ok != tt.retry {
+				mt.Errorf("next_attempt_at set = %v, want %v", ok, tt.retry)
+			}
+		})
+	}
+}
@@ -1,12 +1,15 @@
 package repository
 
 import (
+	"Mango/app/apperror"
 	model "Mango/app/Model"
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type PekerjaanRepository struct {
@@ -17,72 +20,256 @@ func NewPekerjaanRepository(db *mongo.Database) *PekerjaanRepository {
 	return &PekerjaanRepository{Col: db.Collection("pekerjaan_alumni")}
 }
 
-func (r *PekerjaanRepository) GetAllPekerjaan(ctx context.Context) ([]model.Pekerjaan, error) {
+func (r *PekerjaanRepository) Collection() *mongo.Collection {
+	return r.Col
+}
+
+// Indexes: riwayat pekerjaan selalu dicari per alumni; company_id dipakai
+// saat merge perusahaan dan laporan top employer.
+func (r *PekerjaanRepository) Indexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "alumni_id", Value: 1}},
+			Options: options.Index().SetName("alumni_id"),
+		},
+		{
+			Keys:    bson.D{{Key: "company_id", Value: 1}},
+			Options: options.Index().SetName("company_id"),
+		},
+		{
+			Keys:    bson.D{{Key: "deleted_at", Value: -1}},
+			Options: options.Index().SetName("deleted_at").SetSparse(true),
+		},
+	}
+}
+
+func (r *PekerjaanRepository) GetAllPekerjaan(ctx context.Context, filter model.PekerjaanFilter) ([]model.Pekerjaan, error) {
 	var results []model.Pekerjaan
-	cursor, err := r.Col.Find(ctx, bson.M{})
+	cursor, err := r.Col.Find(ctx, pekerjaanQuery(filter))
 	if err != nil {
-		return nil, err
+		return nil, apperror.Internal(err)
 	}
 	defer cursor.Close(ctx)
 
 	for cursor.Next(ctx) {
 		var p model.Pekerjaan
 		if err := cursor.Decode(&p); err != nil {
-			return nil, err
+			return nil, apperror.Internal(err)
 		}
 		results = append(results, p)
 	}
-	return results, cursor.Err()
+	if err := cursor.Err(); err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return results, nil
 }
 
 
-// Create pekerjaan
+// Cursor mengembalikan cursor pekerjaan yang sesuai filter (export).
+func (r *PekerjaanRepository) Cursor(ctx context.Context, filter model.PekerjaanFilter) (*mongo.Cursor, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "alumni_id", Value: 1}, {Key: "tanggal_kerja", Value: 1}})
+	cursor, err := r.Col.Find(ctx, pekerjaanQuery(filter), opts)
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return cursor, nil
+}
+
+// Create pekerjaan. Alumni pemiliknya harus ada dan tidak di tempat
+// sampah.
 func (r *PekerjaanRepository) Create(ctx context.Context, p *model.Pekerjaan) error {
+	if err := r.checkOwner(ctx, p.AlumniID, ErrAlumniNotFound); err != nil {
+		return err
+	}
 	p.ID = primitive.NewObjectID()
-	_, err := r.Col.InsertOne(ctx, p)
-	return err
+	p.CreatedAt = time.Now().Unix()
+	p.UpdatedAt = p.CreatedAt
+	if _, err := r.Col.InsertOne(ctx, p); err != nil {
+		return apperror.Internal(err)
+	}
+	syncSearch(ctx, r.Col.Database(), p.AlumniID)
+	return nil
 }
 
+// FindByAlumniID mengembalikan riwayat pekerjaan alumni, urut dari yang
+// paling awal dimulai.
 func (r *PekerjaanRepository) FindByAlumniID(ctx context.Context, alumniID primitive.ObjectID) ([]model.Pekerjaan, error) {
 	var results []model.Pekerjaan
-	cursor, err := r.Col.Find(ctx, bson.M{"alumni_id": alumniID})
+	opts := options.Find().SetSort(bson.D{{Key: "tanggal_kerja", Value: 1}})
+	filter := active()
+	filter["alumni_id"] = alumniID
+	cursor, err := r.Col.Find(ctx, filter, opts)
 	if err != nil {
-		return nil, err
+		return nil, apperror.Internal(err)
 	}
 	defer cursor.Close(ctx)
 
 	for cursor.Next(ctx) {
 		var p model.Pekerjaan
 		if err := cursor.Decode(&p); err != nil {
-			return nil, err
+			return nil, apperror.Internal(err)
 		}
 		results = append(results, p)
 	}
-	return results, cursor.Err()
+	if err := cursor.Err(); err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return results, nil
 }
 
 
+// CurrentJob mengembalikan pekerjaan alumni yang masih berjalan dan paling
+// baru dimulai.
+func (r *PekerjaanRepository) CurrentJob(ctx context.Context, alumniID primitive.ObjectID) (*model.Pekerjaan, error) {
+	filter := currentJobFilter()
+	filter["alumni_id"] = alumniID
+	opts := options.FindOne().SetSort(bson.D{{Key: "tanggal_kerja", Value: -1}})
+
+	var pekerjaan model.Pekerjaan
+	if err := r.Col.FindOne(ctx, filter, opts).Decode(&pekerjaan); err != nil {
+		return nil, mapFindError(err, ErrNoCurrentJob)
+	}
+	return &pekerjaan, nil
+}
+
 // Get Pekerjaan By ID
 func (r *PekerjaanRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Pekerjaan, error) {
 	var pekerjaan model.Pekerjaan
 
-	err := r.Col.FindOne(ctx, bson.M{"_id": id}).Decode(&pekerjaan)
+	err := r.Col.FindOne(ctx, withID(active(), id)).Decode(&pekerjaan)
 	if err != nil {
-		return nil, err
+		return nil, mapFindError(err, ErrPekerjaanNotFound)
 	}
 
 	return &pekerjaan, nil
 }
 
 
-// Update pekerjaan
-func (r *PekerjaanRepository) Update(ctx context.Context, id primitive.ObjectID, update bson.M) error {
-	_, err := r.Col.UpdateByID(ctx, id, bson.M{"$set": update})
-	return err
+// Update pekerjaan. Field di unset dihapus dari dokumen.
+func (r *PekerjaanRepository) Update(ctx context.Context, id primitive.ObjectID, update bson.M, unset ...string) error {
+	update["updated_at"] = time.Now().Unix()
+	change := bson.M{"$set": update}
+	if len(unset) > 0 {
+		fields := bson.M{}
+		for _, f := range unset {
+			fields[f] = ""
+		}
+		change["$unset"] = fields
+	}
+	result, err := r.Col.UpdateOne(ctx, withID(active(), id), change)
+	if err != nil {
+		return apperror.Internal(err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrPekerjaanNotFound()
+	}
+	syncSearch(ctx, r.Col.Database(), alumniIDsOf(ctx, r.Col, bson.M{"_id": id})...)
+	return nil
+}
+
+// CountByCompany menghitung pekerjaan yang merujuk ke perusahaan,
+// termasuk yang di tempat sampah karena masih bisa di-restore.
+func (r *PekerjaanRepository) CountByCompany(ctx context.Context, companyID primitive.ObjectID) (int64, error) {
+	n, err := r.Col.CountDocuments(ctx, bson.M{"company_id": companyID})
+	if err != nil {
+		return 0, apperror.Internal(err)
+	}
+	return n, nil
+}
+
+// ReassignCompany memindahkan pekerjaan dari perusahaan from ke to dan
+// menyamakan nama_perusahaan dengan nama perusahaan tujuan.
+func (r *PekerjaanRepository) ReassignCompany(ctx context.Context, from []primitive.ObjectID, to primitive.ObjectID, name string) (int64, error) {
+	result, err := r.Col.UpdateMany(ctx,
+		bson.M{"company_id": bson.M{"$in": from}},
+		bson.M{"$set": bson.M{"company_id": to, "nama_perusahaan": name, "updated_at": time.Now().Unix()}},
+	)
+	if err != nil {
+		return 0, apperror.Internal(err)
+	}
+	if result.ModifiedCount > 0 {
+		syncSearch(ctx, r.Col.Database(), alumniIDsOf(ctx, r.Col, bson.M{"company_id": to})...)
+	}
+	return result.ModifiedCount, nil
+}
+
+// Delete memindahkan pekerjaan ke tempat sampah.
+func (r *PekerjaanRepository) Delete(ctx context.Context, id, by primitive.ObjectID) error {
+	var deleted model.Pekerjaan
+	mark := bson.M{"$set": bson.M{"deleted_at": time.Now().Unix(), "deleted_by": by}}
+	if err := r.Col.FindOneAndUpdate(ctx, withID(active(), id), mark).Decode(&deleted); err != nil {
+		return mapFindError(err, ErrPekerjaanNotFound)
+	}
+	syncSearch(ctx, r.Col.Database(), deleted.AlumniID)
+	return nil
+}
+
+// Trash mengembalikan pekerjaan di tempat sampah, yang terbaru dihapus
+// lebih dulu. Pekerjaan yang ikut terhapus bersama alumninya tidak
+// ditampilkan karena dipulihkan lewat alumni.
+func (r *PekerjaanRepository) Trash(ctx context.Context, limit int) ([]model.Pekerjaan, error) {
+	filter := trashed()
+	filter["deleted_with"] = bson.M{"$exists": false}
+	opts := options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := r.Col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+
+	result := []model.Pekerjaan{}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return result, nil
+}
+
+// Restore mengeluarkan pekerjaan dari tempat sampah. Pekerjaan milik
+// alumni yang masih di tempat sampah tidak bisa dipulihkan sendiri.
+func (r *PekerjaanRepository) Restore(ctx context.Context, id primitive.ObjectID) (*model.Pekerjaan, error) {
+	var p model.Pekerjaan
+	if err := r.Col.FindOne(ctx, withID(trashed(), id)).Decode(&p); err != nil {
+		return nil, mapFindError(err, ErrPekerjaanNotInTrash)
+	}
+	if err := r.checkOwner(ctx, p.AlumniID, ErrAlumniDeleted); err != nil {
+		return nil, err
+	}
+
+	restore := bson.M{"$unset": bson.M{"deleted_at": "", "deleted_by": "", "deleted_with": ""}}
+	if _, err := r.Col.UpdateByID(ctx, id, restore); err != nil {
+		return nil, apperror.Internal(err)
+	}
+	p.Deletion = model.Deletion{}
+	syncSearch(ctx, r.Col.Database(), p.AlumniID)
+	return &p, nil
 }
 
-// Delete pekerjaan
-func (r *PekerjaanRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
-	_, err := r.Col.DeleteOne(ctx, bson.M{"_id": id})
-	return err
+// checkOwner memastikan alumni pemilik pekerjaan ada dan tidak di tempat
+// sampah; jika tidak, missing dikembalikan.
+func (r *PekerjaanRepository) checkOwner(ctx context.Context, alumniID primitive.ObjectID, missing func() *apperror.Error) error {
+	owner := r.Col.Database().Collection("alumni")
+	if n, err := owner.CountDocuments(ctx, withID(active(), alumniID)); err != nil {
+		return apperror.Internal(err)
+	} else if n == 0 {
+		return missing()
+	}
+	return nil
+}
+
+// Purge menghapus permanen pekerjaan yang sudah di tempat sampah sebelum
+// waktu before (unix). Dengan dryRun hanya menghitung.
+func (r *PekerjaanRepository) Purge(ctx context.Context, before int64, dryRun bool) (int64, error) {
+	filter := bson.M{"deleted_at": bson.M{"$lt": before}}
+	if dryRun {
+		n, err := r.Col.CountDocuments(ctx, filter)
+		if err != nil {
+			return 0, apperror.Internal(err)
+		}
+		return n, nil
+	}
+
+	result, err := r.Col.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, apperror.Internal(err)
+	}
+	return result.DeletedCount, nil
 }
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AlumniRevisionRepository menyimpan salinan setiap versi dokumen alumni.
+// Revisi ditulis oleh AlumniRepository di transaksi yang sama dengan
+// perubahannya.
+type AlumniRevisionRepository struct {
+	Col *mongo.Collection
+}
+
+func NewAlumniRevisionRepository(db *mongo.Database) *AlumniRevisionRepository {
+	return &AlumniRevisionRepository{Col: db.Collection("alumni_revisions")}
+}
+
+func (r *AlumniRevisionRepository) Collection() *mongo.Collection {
+	return r.Col
+}
+
+// Indexes: satu dokumen per versi alumni; asOf mencari versi terakhir
+// sebelum waktu tertentu.
+func (r *AlumniRevisionRepository) Indexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "alumni_id", Value: 1}, {Key: "version", Value: -1}},
+			Options: options.Index().SetName("alumni_version").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "alumni_id", Value: 1}, {Key: "at", Value: -1}},
+			Options: options.Index().SetName("alumni_at"),
+		},
+	}
+}
+
+// save menyimpan alum (yang sudah tertulis) sebagai versi alum.Revision.
+func (r *AlumniRevisionRepository) save(ctx context.Context, alum model.Alumni, action string, by primitive.ObjectID) error {
+	rev := model.AlumniRevision{
+		ID:       primitive.NewObjectID(),
+		AlumniID: alum.ID,
+		Version:  alum.Revision,
+		At:       time.Now().UTC(),
+		Action:   action,
+		Snapshot: alum,
+	}
+	if !by.IsZero() {
+		rev.By = &by
+	}
+	rev.Snapshot.Search = nil
+	if _, err := r.Col.InsertOne(ctx, rev); err != nil {
+		return apperror.Internal(err)
+	}
+	return nil
+}
+
+// List mengembalikan semua versi alumni, yang terbaru lebih dulu.
+func (r *AlumniRevisionRepository) List(ctx context.Context, alumniID primitive.ObjectID) ([]model.AlumniRevision, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: -1}})
+	cursor, err := r.Col.Find(ctx, bson.M{"alumni_id": alumniID}, opts)
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+
+	result := []model.AlumniRevision{}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return result, nil
+}
+
+// Find mengembalikan satu versi alumni.
+func (r *AlumniRevisionRepository) Find(ctx context.Context, alumniID primitive.ObjectID, version int) (*model.AlumniRevision, error) {
+	var rev model.AlumniRevision
+	err := r.Col.FindOne(ctx, bson.M{"alumni_id": alumniID, "version": version}).Decode(&rev)
+	if err != nil {
+		return nil, mapFindError(err, ErrRevisionNotFound)
+	}
+	return &rev, nil
+}
+
+// AsOf mengembalikan profil alumni seperti pada waktu at: versi terakhir
+// yang ditulis sebelum atau tepat pada at. Alumni yang saat itu belum ada
+// atau sudah di tempat sampah dilaporkan tidak ditemukan.
+func (r *AlumniRevisionRepository) AsOf(ctx context.Context, alumniID primitive.ObjectID, at time.Time) (*model.Alumni, error) {
+	var rev model.AlumniRevision
+	opts := options.FindOne().SetSort(bson.D{{Key: "at", Value: -1}, {Key: "version", Value: -1}})
+	err := r.Col.FindOne(ctx, bson.M{"alumni_id": alumniID, "at": bson.M{"$lte": at}}, opts).Decode(&rev)
+	if err != nil {
+		return nil, mapFindError(err, ErrNoRevisionAsOf)
+	}
+	if rev.Snapshot.IsDeleted() {
+		return nil, ErrNoRevisionAsOf()
+	}
+	return &rev.Snapshot, nil
+}
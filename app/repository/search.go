@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"Mango/app/crypt"
+	"Mango/app/search"
+	"context"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// searchCandidates membatasi jumlah kandidat yang dinilai ulang per fase.
+const searchCandidates = 200
+
+// textIndex adalah text index alumni. Bahasa "none" mematikan stemming
+// dan stopword bahasa Inggris yang tidak cocok untuk nama Indonesia;
+// text index versi 3 sudah tidak membedakan huruf besar dan diakritik.
+// Email terenkripsi sehingga tidak masuk text index.
+func textIndex() mongo.IndexModel {
+	keys := bson.D{}
+	weights := bson.D{}
+	for _, f := range []string{"nama", "nim", "jurusan", "employers", "positions"} {
+		path := f
+		if f == "employers" || f == "positions" {
+			path = "search." + f
+		}
+		keys = append(keys, bson.E{Key: path, Value: "text"})
+		weights = append(weights, bson.E{Key: path, Value: search.Weights[f]})
+	}
+	return mongo.IndexModel{
+		Keys:    keys,
+		Options: options.Index().SetName("alumni_text").SetWeights(weights).SetDefaultLanguage("none"),
+	}
+}
+
+// Search mencari alumni dengan text index, ditambah kandidat nama yang
+// mirip (salah ketik) lewat search.name_keys dan kandidat yang emailnya
+// sama persis dengan kata di query (lewat blind index). Semua kandidat
+// dinilai ulang oleh search.Rank lalu diurutkan dari yang paling relevan.
+func (r *AlumniRepository) Search(ctx context.Context, q string, limit int) ([]model.SearchHit, error) {
+	terms := search.Terms(q)
+	if len(terms) == 0 {
+		return nil, apperror.Validation("validation_failed", "Request validation failed", apperror.FieldError{
+			Field: "q", Code: "required", Message: "must contain at least one word",
+		})
+	}
+
+	candidates := map[primitive.ObjectID]model.Alumni{}
+	collect := func(filter bson.M, opts *options.FindOptions) error {
+		cursor, err := r.Col.Find(ctx, filter, opts.SetLimit(searchCandidates))
+		if err != nil {
+			return apperror.Internal(err)
+		}
+		defer cursor.Close(ctx)
+		for cursor.Next(ctx) {
+			var a model.Alumni
+			if err := cursor.Decode(&a); err != nil {
+				return apperror.Internal(err)
+			}
+			candidates[a.ID] = a
+		}
+		if err := cursor.Err(); err != nil {
+			return apperror.Internal(err)
+		}
+		return nil
+	}
+
+	textOpts := options.Find().
+		SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetSort(bson.M{"score": bson.M{"$meta": "textScore"}})
+	textFilter := active()
+	textFilter["$text"] = bson.M{"$search": strings.Join(terms, " ")}
+	if err := collect(textFilter, textOpts); err != nil {
+		return nil, err
+	}
+
+	// Kandidat salah ketik: kata nama dengan dua huruf awal yang sama
+	var prefixes bson.A
+	for _, t := range terms {
+		if rs := []rune(t); len(rs) >= search.MinTypoLength {
+			prefixes = append(prefixes, primitive.Regex{Pattern: "^" + regexp.QuoteMeta(string(rs[:2]))})
+		}
+	}
+	if len(prefixes) > 0 {
+		typoFilter := active()
+		typoFilter["search.name_keys"] = bson.M{"$in": prefixes}
+		if err := collect(typoFilter, options.Find()); err != nil {
+			return nil, err
+		}
+	}
+
+	var emails bson.A
+	for _, word := range strings.Fields(q) {
+		if strings.Contains(word, "@") {
+			emails = append(emails, crypt.EmailIndex(word))
+		}
+	}
+	if len(emails) > 0 {
+		emailFilter := active()
+		emailFilter["email_bidx"] = bson.M{"$in": emails}
+		if err := collect(emailFilter, options.Find()); err != nil {
+			return nil, err
+		}
+	}
+
+	hits := make([]model.SearchHit, 0, len(candidates))
+	for _, a := range candidates {
+		doc := search.Document{
+			"nama":    {a.Nama},
+			"nim":     {a.NIM},
+			"email":   {string(a.Email)},
+			"jurusan": {a.Jurusan},
+		}
+		var employers []string
+		if a.Search != nil {
+			employers = a.Search.Employers
+			doc["employers"] = a.Search.Employers
+			doc["positions"] = a.Search.Positions
+		}
+		res := search.Rank(terms, doc)
+		if res.Score == 0 {
+			continue
+		}
+		hits = append(hits, model.SearchHit{Alumni: a, Score: res.Score, Highlights: res.Highlights, Employers: employers})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Alumni.Nama < hits[j].Alumni.Nama
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+// syncSearch memperbarui daftar perusahaan dan jabatan di field search
+// alumni dari riwayat pekerjaannya. Field ini turunan, jadi kegagalan
+// hanya dicatat dan tidak menggagalkan perubahan pekerjaan; jalankan
+// migrasi alumni_search_fields untuk membangun ulang.
+func syncSearch(ctx context.Context, db *mongo.Database, alumniIDs ...primitive.ObjectID) {
+	for _, id := range alumniIDs {
+		if err := SyncAlumniSearch(ctx, db, id); err != nil {
+			log.Printf("⚠️  search sync for alumni %s: %v", id.Hex(), err)
+		}
+	}
+}
+
+// SyncAlumniSearch menghitung ulang field search satu alumni.
+func SyncAlumniSearch(ctx context.Context, db *mongo.Database, alumniID primitive.ObjectID) error {
+	opts := options.Find().SetProjection(bson.M{"nama_perusahaan": 1, "posisi_jabatan": 1})
+	filter := active()
+	filter["alumni_id"] = alumniID
+	cursor, err := db.Collection("pekerjaan_alumni").Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var employers, positions []string
+	for cursor.Next(ctx) {
+		var p model.Pekerjaan
+		if err := cursor.Decode(&p); err != nil {
+			return err
+		}
+		employers = appendUnique(employers, p.Nama_perusahaan)
+		positions = appendUnique(positions, p.Posisi_jabatan)
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	_, err = db.Collection("alumni").UpdateByID(ctx, alumniID, bson.M{"$set": bson.M{
+		"search.employers": employers,
+		"search.positions": positions,
+	}})
+	return err
+}
+
+// alumniIDsOf mengembalikan alumni_id unik dari pekerjaan yang cocok filter.
+func alumniIDsOf(ctx context.Context, col *mongo.Collection, filter bson.M) []primitive.ObjectID {
+	values, err := col.Distinct(ctx, "alumni_id", filter)
+	if err != nil {
+		log.Printf("⚠️  search sync: %v", err)
+		return nil
+	}
+	var ids []primitive.ObjectID
+	for _, v := range values {
+		if id, ok := v.(primitive.ObjectID); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func appendUnique(list []string, s string) []string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return list
+	}
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"Mango/app/salary"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// StatsRepository menjalankan aggregation pipeline untuk laporan tracer
+// study. Semua laporan berangkat dari koleksi alumni sehingga filter
+// alumni (jurusan, angkatan, tahun lulus) berlaku sama.
+type StatsRepository struct {
+	Alumni *mongo.Collection
+}
+
+func NewStatsRepository(db *mongo.Database) *StatsRepository {
+	return &StatsRepository{Alumni: db.Collection("alumni")}
+}
+
+// lookupJobs menambahkan field `as` berisi pekerjaan alumni (yang tidak
+// ada di tempat sampah) yang cocok dengan match tambahan.
+func lookupJobs(as string, match bson.M, extra ...bson.D) bson.D {
+	cond := active()
+	cond["$expr"] = bson.M{"$eq": bson.A{"$alumni_id", "$$alumni_id"}}
+	for k, v := range match {
+		cond[k] = v
+	}
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: cond}}}
+	pipeline = append(pipeline, extra...)
+
+	return bson.D{{Key: "$lookup", Value: bson.M{
+		"from":     "pekerjaan_alumni",
+		"let":      bson.M{"alumni_id": "$_id"},
+		"pipeline": pipeline,
+		"as":       as,
+	}}}
+}
+
+// EmploymentRate menghitung jumlah alumni dan yang sedang bekerja per
+// kelompok `by` (angkatan, tahun_lulus atau jurusan).
+func (r *StatsRepository) EmploymentRate(ctx context.Context, filter model.AlumniFilter, by string) ([]model.EmploymentRate, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: alumniQuery(filter)}},
+		lookupJobs("current", currentJobFilter(), bson.D{{Key: "$limit", Value: 1}}),
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$" + by,
+			"total": bson.M{"$sum": 1},
+			"employed": bson.M{"$sum": bson.M{
+				"$cond": bson.A{bson.M{"$gt": bson.A{bson.M{"$size": "$current"}, 0}}, 1, 0},
+			}},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	var rows []struct {
+		Group    any `bson:"_id"`
+		Total    int `bson:"total"`
+		Employed int `bson:"employed"`
+	}
+	if err := r.aggregate(ctx, pipeline, &rows); err != nil {
+		return nil, err
+	}
+
+	result := make([]model.EmploymentRate, len(rows))
+	for i, row := range rows {
+		result[i] = model.EmploymentRate{Group: row.Group, Total: row.Total, Employed: row.Employed}
+		if row.Total > 0 {
+			result[i].Rate = float64(row.Employed) / float64(row.Total)
+		}
+	}
+	return result, nil
+}
+
+// CurrentJobDistribution menghitung sebaran pekerjaan yang sedang
+// berjalan berdasarkan satu field pekerjaan (bidang_industri,
+// lokasi_kerja, ...). Nilai kosong dilaporkan sebagai "unknown".
+func (r *StatsRepository) CurrentJobDistribution(ctx context.Context, filter model.AlumniFilter, field string) ([]model.DistributionItem, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: alumniQuery(filter)}},
+		lookupJobs("current", currentJobFilter()),
+		{{Key: "$unwind", Value: "$current"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"$ifNull": bson.A{"$current." + field, ""}},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+
+	var rows []struct {
+		Label any `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	if err := r.aggregate(ctx, pipeline, &rows); err != nil {
+		return nil, err
+	}
+
+	total := 0
+	for _, row := range rows {
+		total += row.Count
+	}
+	result := make([]model.DistributionItem, len(rows))
+	for i, row := range rows {
+		label, _ := row.Label.(string)
+		if label == "" {
+			label = "unknown"
+		}
+		result[i] = model.DistributionItem{Label: label, Count: row.Count}
+		if total > 0 {
+			result[i].Percentage = float64(row.Count) * 100 / float64(total)
+		}
+	}
+	return result, nil
+}
+
+// SalaryDistribution mengelompokkan gaji pekerjaan saat ini ke bracket
+// cfg. Gaji dinormalkan ke rupiah per bulan memakai kurs cfg dan batas
+// bawah rentang yang dipakai, karena rentang selalu berada dalam satu
+// bracket. Semua bracket dilaporkan walaupun kosong. Gaji terenkripsi,
+// jadi pengelompokan dilakukan di aplikasi setelah didekripsi.
+func (r *StatsRepository) SalaryDistribution(ctx context.Context, filter model.AlumniFilter, cfg salary.Config) ([]model.SalaryBucket, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: alumniQuery(filter)}},
+		lookupJobs("current", currentJobFilter(), bson.D{{Key: "$project", Value: bson.M{"gaji": 1}}}),
+		{{Key: "$unwind", Value: "$current"}},
+		{{Key: "$project", Value: bson.M{"_id": 0, "gaji": "$current.gaji"}}},
+	}
+
+	var rows []struct {
+		Gaji *model.Salary `bson:"gaji"`
+	}
+	if err := r.aggregate(ctx, pipeline, &rows); err != nil {
+		return nil, err
+	}
+
+	counts := map[int64]int{}
+	unknown, total := 0, len(rows)
+	for _, row := range rows {
+		if row.Gaji == nil {
+			unknown++
+			continue
+		}
+		monthly, ok := cfg.Monthly(row.Gaji.Min, row.Gaji.Currency, row.Gaji.Period)
+		if !ok {
+			unknown++
+			continue
+		}
+		counts[cfg.BracketOf(monthly).Min]++
+	}
+
+	result := make([]model.SalaryBucket, 0, len(cfg.Brackets)+1)
+	for _, b := range cfg.Brackets {
+		result = append(result, model.SalaryBucket{Label: b.Label, Min: b.Min, Max: b.Max, Count: counts[b.Min]})
+	}
+	if unknown > 0 {
+		result = append(result, model.SalaryBucket{Label: "unknown", Count: unknown})
+	}
+	for i := range result {
+		if total > 0 {
+			result[i].Percentage = float64(result[i].Count) * 100 / float64(total)
+		}
+	}
+	return result, nil
+}
+
+// TopEmployers mengurutkan perusahaan berdasarkan jumlah alumni (unik)
+// yang pernah bekerja di sana, atau yang masih bekerja jika currentOnly.
+// Hanya pekerjaan yang tertaut ke direktori perusahaan yang dihitung.
+func (r *StatsRepository) TopEmployers(ctx context.Context, filter model.AlumniFilter, limit int, currentOnly bool) ([]model.TopEmployer, error) {
+	match := bson.M{}
+	if currentOnly {
+		match = currentJobFilter()
+	}
+	match["company_id"] = bson.M{"$exists": true}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: alumniQuery(filter)}},
+		lookupJobs("jobs", match),
+		{{Key: "$unwind", Value: "$jobs"}},
+		{{Key: "$group", Value: bson.M{"_id": "$jobs.company_id", "alumni": bson.M{"$addToSet": "$_id"}}}},
+		{{Key: "$set", Value: bson.M{"alumni": bson.M{"$size": "$alumni"}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "alumni", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+		{{Key: "$lookup", Value: bson.M{"from": "companies", "localField": "_id", "foreignField": "_id", "as": "company"}}},
+		{{Key: "$set", Value: bson.M{"name": bson.M{"$first": "$company.name"}}}},
+	}
+
+	result := []model.TopEmployer{}
+	if err := r.aggregate(ctx, pipeline, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WaitingTime menghitung rata-rata masa tunggu (bulan) antara kelulusan
+// dan pekerjaan pertama per kelompok `by`. Bulan lulus tidak disimpan,
+// sehingga kelulusan dianggap Januari tahun_lulus. Pekerjaan yang dimulai
+// sebelum lulus dihitung sebagai masa tunggu nol.
+func (r *StatsRepository) WaitingTime(ctx context.Context, filter model.AlumniFilter, by string) ([]model.WaitingTime, error) {
+	match := alumniQuery(filter)
+	if _, ok := match["tahun_lulus"]; !ok {
+		match["tahun_lulus"] = bson.M{"$gt": 0}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		lookupJobs("first", bson.M{"tanggal_kerja": bson.M{"$type": "date"}},
+			bson.D{{Key: "$sort", Value: bson.M{"tanggal_kerja": 1}}},
+			bson.D{{Key: "$limit", Value: 1}},
+		),
+		{{Key: "$unwind", Value: "$first"}},
+		{{Key: "$set", Value: bson.M{"wait_months": bson.M{"$max": bson.A{0,
+			bson.M{"$add": bson.A{
+				bson.M{"$multiply": bson.A{bson.M{"$subtract": bson.A{bson.M{"$year": "$first.tanggal_kerja"}, "$tahun_lulus"}}, 12}},
+				bson.M{"$subtract": bson.A{bson.M{"$month": "$first.tanggal_kerja"}, 1}},
+			}},
+		}}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     "$" + by,
+			"alumni":  bson.M{"$sum": 1},
+			"average": bson.M{"$avg": "$wait_months"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	var rows []struct {
+		Group   any     `bson:"_id"`
+		Alumni  int     `bson:"alumni"`
+		Average float64 `bson:"average"`
+	}
+	if err := r.aggregate(ctx, pipeline, &rows); err != nil {
+		return nil, err
+	}
+
+	result := make([]model.WaitingTime, len(rows))
+	for i, row := range rows {
+		result[i] = model.WaitingTime{Group: row.Group, Alumni: row.Alumni, AverageMonths: row.Average}
+	}
+	return result, nil
+}
+
+func (r *StatsRepository) aggregate(ctx context.Context, pipeline mongo.Pipeline, out any) error {
+	cursor, err := r.Alumni.Aggregate(ctx, pipeline)
+	if err != nil {
+		return apperror.Internal(err)
+	}
+	if err := cursor.All(ctx, out); err != nil {
+		return apperror.Internal(err)
+	}
+	return nil
+}
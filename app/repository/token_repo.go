@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TokenRepository menyimpan token sekali pakai untuk verifikasi email dan
+// reset password.
+type TokenRepository struct {
+	Col *mongo.Collection
+}
+
+func NewTokenRepository(db *mongo.Database) *TokenRepository {
+	return &TokenRepository{Col: db.Collection("user_tokens")}
+}
+
+func (r *TokenRepository) Collection() *mongo.Collection {
+	return r.Col
+}
+
+// Indexes: token dicari lewat hash-nya; token kedaluwarsa dihapus TTL.
+func (r *TokenRepository) Indexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "hash", Value: 1}},
+			Options: options.Index().SetName("hash_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "purpose", Value: 1}},
+			Options: options.Index().SetName("user_purpose"),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+		},
+	}
+}
+
+// Issue membuat token baru untuk user dan mengembalikan token mentahnya.
+// Token lama dengan tujuan yang sama yang belum dipakai dibatalkan,
+// sehingga hanya link di email terakhir yang berlaku.
+func (r *TokenRepository) Issue(ctx context.Context, userID primitive.ObjectID, purpose, emailIndex string, ttl time.Duration) (string, time.Time, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, apperror.Internal(err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	now := time.Now().UTC()
+	t := model.UserToken{
+		ID:         primitive.NewObjectID(),
+		UserID:     userID,
+		Purpose:    purpose,
+		Hash:       hashToken(token),
+		EmailIndex: emailIndex,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(ttl),
+	}
+	if err := r.RevokeAll(ctx, userID, purpose); err != nil {
+		return "", time.Time{}, err
+	}
+	if _, err := r.Col.InsertOne(ctx, t); err != nil {
+		return "", time.Time{}, apperror.Internal(err)
+	}
+	return token, t.ExpiresAt, nil
+}
+
+// Consume menandai token terpakai dan mengembalikannya. Token yang tidak
+// ada, salah tujuan, kedaluwarsa atau sudah dipakai ditolak dengan
+// ErrTokenInvalid.
+func (r *TokenRepository) Consume(ctx context.Context, token, purpose string) (*model.UserToken, error) {
+	now := time.Now().UTC()
+	filter := bson.M{
+		"hash":       hashToken(token),
+		"purpose":    purpose,
+		"used_at":    bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": now},
+	}
+
+	var t model.UserToken
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := r.Col.FindOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{"used_at": now}}, opts).Decode(&t)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrTokenInvalid()
+	}
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return &t, nil
+}
+
+// RevokeAll menghapus token user dengan tujuan purpose yang belum dipakai.
+func (r *TokenRepository) RevokeAll(ctx context.Context, userID primitive.ObjectID, purpose string) error {
+	filter := bson.M{"user_id": userID, "purpose": purpose, "used_at": bson.M{"$exists": false}}
+	if _, err := r.Col.DeleteMany(ctx, filter); err != nil {
+		return apperror.Internal(err)
+	}
+	return nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// txSupport menyimpan hasil deteksi dukungan transaksi per client.
+var txSupport sync.Map // *mongo.Client -> bool
+
+// RunInTransaction menjalankan fn dalam transaksi multi-dokumen jika
+// deployment mendukung (replica set atau sharded cluster). Pada server
+// standalone fn dijalankan langsung, tanpa jaminan atomik. Semua operasi
+// di fn harus memakai ctx yang diberikan agar ikut dalam transaksi.
+func RunInTransaction(ctx context.Context, db *mongo.Database, fn func(ctx context.Context) error) error {
+	if !supportsTransactions(ctx, db) {
+		return fn(ctx)
+	}
+
+	session, err := db.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (any, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
+
+// supportsTransactions memeriksa sekali per client apakah server adalah
+// anggota replica set atau mongos.
+func supportsTransactions(ctx context.Context, db *mongo.Database) bool {
+	client := db.Client()
+	if v, ok := txSupport.Load(client); ok {
+		return v.(bool)
+	}
+
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := db.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		// Jangan simpan hasil; coba lagi di panggilan berikutnya
+		log.Printf("⚠️  cannot detect transaction support: %v", err)
+		return false
+	}
+	supported := hello.SetName != "" || hello.Msg == "isdbgrid"
+	if !supported {
+		log.Printf("⚠️  MongoDB is standalone; multi-document writes run without transactions")
+	}
+	txSupport.Store(client, supported)
+	return supported
+}
@@ -1,12 +1,18 @@
 package repository
 
 import (
+	"Mango/app/apperror"
 	model "Mango/app/Model"
+	"Mango/app/crypt"
 	"context"
+	"errors"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type UserRepository struct {
@@ -19,22 +25,348 @@ func NewUserRepository(db *mongo.Database) *UserRepository {
 	}
 }
 
-// ✅ Cari user berdasarkan ID (digunakan di AuthMiddleware)
+func (r *UserRepository) Collection() *mongo.Collection {
+	return r.Col
+}
+
+// Indexes: username, email (lewat blind index) dan akun SSO harus unik,
+// dan satu alumni hanya boleh ditautkan ke satu user.
+func (r *UserRepository) Indexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "username", Value: 1}},
+			Options: options.Index().SetName("username_unique").SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "email_bidx", Value: 1}},
+			Options: options.Index().SetName("email_bidx_unique").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"email_bidx": bson.M{"$gt": ""}}),
+		},
+		{
+			Keys: bson.D{{Key: "sso.issuer", Value: 1}, {Key: "sso.subject", Value: 1}},
+			Options: options.Index().SetName("sso_unique").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"sso.subject": bson.M{"$gt": ""}}),
+		},
+		{
+			Keys: bson.D{{Key: "alumni_id", Value: 1}},
+			Options: options.Index().SetName("alumni_link_unique").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"alumni_link": bson.M{"$exists": true}}),
+		},
+	}
+}
+
+// ✅ Cari user berdasarkan ID (digunakan di AuthMiddleware). User yang
+// dinonaktifkan bersama alumninya dianggap tidak ada.
 func (r *UserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.User, error) {
 	var user model.User
-	err := r.Col.FindOne(ctx, bson.M{"_id": id}).Decode(&user)
-	return &user, err
+	err := r.Col.FindOne(ctx, withID(active(), id)).Decode(&user)
+	if err != nil {
+		return nil, mapFindError(err, ErrUserNotFound)
+	}
+	return &user, nil
 }
 
 // ✅ Cari user berdasarkan username (untuk login)
 func (r *UserRepository) FindByUsername(ctx context.Context, Username string) (*model.User, error) {
 	var user model.User
-	err := r.Col.FindOne(ctx, bson.M{"username": Username}).Decode(&user)
-	return &user, err
+	filter := active()
+	filter["username"] = Username
+	err := r.Col.FindOne(ctx, filter).Decode(&user)
+	if err != nil {
+		return nil, mapFindError(err, ErrUserNotFound)
+	}
+	return &user, nil
+}
+
+// FindByEmail mencari user aktif lewat blind index email.
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	idx := crypt.EmailIndex(email)
+	if idx == "" {
+		return nil, ErrUserNotFound()
+	}
+	var user model.User
+	filter := active()
+	filter["email_bidx"] = idx
+	if err := r.Col.FindOne(ctx, filter).Decode(&user); err != nil {
+		return nil, mapFindError(err, ErrUserNotFound)
+	}
+	return &user, nil
+}
+
+// FindBySSO mencari user aktif yang terhubung dengan akun subject di
+// identity provider issuer.
+func (r *UserRepository) FindBySSO(ctx context.Context, issuer, subject string) (*model.User, error) {
+	var user model.User
+	filter := active()
+	filter["sso.issuer"] = issuer
+	filter["sso.subject"] = subject
+	if err := r.Col.FindOne(ctx, filter).Decode(&user); err != nil {
+		return nil, mapFindError(err, ErrUserNotFound)
+	}
+	return &user, nil
+}
+
+// LinkSSO menghubungkan user yang belum punya akun SSO dengan identity.
+func (r *UserRepository) LinkSSO(ctx context.Context, id primitive.ObjectID, identity model.SSOIdentity) error {
+	filter := bson.M{"_id": id, "sso": bson.M{"$exists": false}}
+	result, err := r.Col.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"sso": identity}})
+	if err != nil {
+		return mapWriteError(err, conflictRule{"sso_unique", ErrSSOAlreadyLinked})
+	}
+	if result.MatchedCount == 0 {
+		return ErrSSOAlreadyLinked()
+	}
+	return nil
+}
+
+// RecordSSOLogin mencatat waktu login SSO terakhir.
+func (r *UserRepository) RecordSSOLogin(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.Col.UpdateByID(ctx, id, bson.M{"$set": bson.M{"sso.last_login_at": time.Now().UTC()}})
+	if err != nil {
+		return apperror.Internal(err)
+	}
+	return nil
+}
+
+// SetRole mengganti role user (dipakai sinkronisasi grup SSO).
+func (r *UserRepository) SetRole(ctx context.Context, id primitive.ObjectID, role string) error {
+	result, err := r.Col.UpdateByID(ctx, id, bson.M{"$set": bson.M{"role": role}})
+	if err != nil {
+		return apperror.Internal(err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrUserNotFound()
+	}
+	return nil
+}
+
+// LinkAlumni menghubungkan user yang belum punya tautan alumni dengan
+// alumni. Mengembalikan false jika user sudah tertaut atau alumni sudah
+// tertaut ke user lain.
+func (r *UserRepository) LinkAlumni(ctx context.Context, id, alumniID primitive.ObjectID, link model.AlumniLink) (bool, error) {
+	filter := bson.M{"_id": id, "alumni_link": bson.M{"$exists": false}}
+	result, err := r.Col.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"alumni_id": alumniID, "alumni_link": link}})
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, apperror.Internal(err)
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// SetAlumniLink mengganti tautan alumni user (tindakan admin); alumniID
+// kosong melepas tautan.
+func (r *UserRepository) SetAlumniLink(ctx context.Context, id, alumniID primitive.ObjectID, link model.AlumniLink) error {
+	update := bson.M{"$set": bson.M{"alumni_id": alumniID, "alumni_link": link}}
+	if alumniID.IsZero() {
+		update = bson.M{"$unset": bson.M{"alumni_id": "", "alumni_link": ""}}
+	}
+	result, err := r.Col.UpdateOne(ctx, withID(active(), id), update)
+	if err != nil {
+		return mapWriteError(err, conflictRule{"alumni_link_unique", ErrAlumniAlreadyLinked})
+	}
+	if result.MatchedCount == 0 {
+		return ErrUserNotFound()
+	}
+	return nil
+}
+
+// AlumniLinked melaporkan apakah sudah ada user yang tertaut dengan
+// alumni tersebut lewat SSO atau admin.
+func (r *UserRepository) AlumniLinked(ctx context.Context, alumniID primitive.ObjectID) (bool, error) {
+	filter := bson.M{"alumni_id": alumniID, "alumni_link": bson.M{"$exists": true}}
+	n, err := r.Col.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, apperror.Internal(err)
+	}
+	return n > 0, nil
+}
+
+// MarkEmailVerified menandai email user terverifikasi, asalkan emailnya
+// masih yang sama dengan saat token dibuat (blind index emailIndex).
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, id primitive.ObjectID, emailIndex string) error {
+	filter := bson.M{"_id": id, "email_bidx": emailIndex}
+	result, err := r.Col.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"email_verified_at": time.Now().UTC()}})
+	if err != nil {
+		return apperror.Internal(err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrTokenInvalid()
+	}
+	return nil
 }
 
-// ✅ Tambah user baru (untuk register)
+// UpdatePassword mengganti password user (dipakai reset-password).
+// password disimpan sebagai hash bcrypt.
+func (r *UserRepository) UpdatePassword(ctx context.Context, id primitive.ObjectID, password string) error {
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	result, err := r.Col.UpdateByID(ctx, id, bson.M{"$set": bson.M{"password": hash}})
+	if err != nil {
+		return apperror.Internal(err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrUserNotFound()
+	}
+	return nil
+}
+
+// RecordLoginFailure menambah hitungan login gagal. Jika mencapai
+// policy.Threshold, akun dikunci dan hitungan dimulai lagi dari nol.
+// Mengembalikan akhir penguncian baru, atau nil jika akun tidak dikunci.
+func (r *UserRepository) RecordLoginFailure(ctx context.Context, id primitive.ObjectID, policy model.LockoutPolicy) (*time.Time, error) {
+	var u model.User
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After).
+		SetProjection(bson.M{"failed_logins": 1, "lockouts": 1})
+	err := r.Col.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"failed_logins": 1}}, opts).Decode(&u)
+	if err != nil {
+		return nil, mapFindError(err, ErrUserNotFound)
+	}
+	if policy.Threshold <= 0 || u.FailedLogins < policy.Threshold {
+		return nil, nil
+	}
+
+	// Jika beberapa percobaan gagal bersamaan, hanya yang hitungannya
+	// masih sama yang mengunci
+	until := time.Now().Add(policy.Duration(u.Lockouts + 1))
+	result, err := r.Col.UpdateOne(ctx, bson.M{"_id": id, "failed_logins": u.FailedLogins}, bson.M{
+		"$set": bson.M{"failed_logins": 0, "locked_until": until},
+		"$inc": bson.M{"lockouts": 1},
+	})
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	if result.ModifiedCount == 0 {
+		return nil, nil
+	}
+	return &until, nil
+}
+
+// ClearLockout menghapus hitungan login gagal dan penguncian akun
+// (setelah login berhasil atau dibuka admin).
+func (r *UserRepository) ClearLockout(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.Col.UpdateByID(ctx, id, bson.M{"$unset": bson.M{"failed_logins": "", "lockouts": "", "locked_until": ""}})
+	if err != nil {
+		return apperror.Internal(err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrUserNotFound()
+	}
+	return nil
+}
+
+// SetPendingMFA menyimpan secret TOTP yang menunggu konfirmasi. Gagal
+// dengan ErrMFAAlreadyEnabled jika TOTP sudah aktif.
+func (r *UserRepository) SetPendingMFA(ctx context.Context, id primitive.ObjectID, secret model.Secret) error {
+	filter := bson.M{"_id": id, "mfa.enabled_at": bson.M{"$exists": false}}
+	result, err := r.Col.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"mfa.pending_secret": secret}})
+	if err != nil {
+		return apperror.Internal(err)
+	}
+	if result.MatchedCount == 0 {
+		if _, err := r.FindByID(ctx, id); err != nil {
+			return err
+		}
+		return ErrMFAAlreadyEnabled()
+	}
+	return nil
+}
+
+// EnableMFA mengaktifkan TOTP dengan secret yang sudah dikonfirmasi pada
+// periode step, beserta hash kode pemulihannya.
+func (r *UserRepository) EnableMFA(ctx context.Context, id primitive.ObjectID, secret model.Secret, step int64, recoveryHashes []string) error {
+	now := time.Now().UTC()
+	filter := bson.M{"_id": id, "mfa.enabled_at": bson.M{"$exists": false}}
+	result, err := r.Col.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"mfa": model.MFA{
+		Secret:        secret,
+		EnabledAt:     &now,
+		LastStep:      step,
+		RecoveryCodes: recoveryHashes,
+	}}})
+	if err != nil {
+		return apperror.Internal(err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrMFAAlreadyEnabled()
+	}
+	return nil
+}
+
+// UseTOTPStep mencatat periode TOTP yang baru dipakai. false berarti kode
+// periode itu (atau yang lebih baru) sudah pernah dipakai.
+func (r *UserRepository) UseTOTPStep(ctx context.Context, id primitive.ObjectID, step int64) (bool, error) {
+	filter := bson.M{"_id": id, "mfa.enabled_at": bson.M{"$exists": true}, "mfa.last_step": bson.M{"$not": bson.M{"$gte": step}}}
+	result, err := r.Col.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"mfa.last_step": step}})
+	if err != nil {
+		return false, apperror.Internal(err)
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// UseRecoveryCode menghapus kode pemulihan dengan hash tersebut. false
+// berarti kode tidak ada atau sudah dipakai.
+func (r *UserRepository) UseRecoveryCode(ctx context.Context, id primitive.ObjectID, hash string) (bool, error) {
+	filter := bson.M{"_id": id, "mfa.enabled_at": bson.M{"$exists": true}, "mfa.recovery_codes": hash}
+	result, err := r.Col.UpdateOne(ctx, filter, bson.M{"$pull": bson.M{"mfa.recovery_codes": hash}})
+	if err != nil {
+		return false, apperror.Internal(err)
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// SetRecoveryCodes mengganti semua kode pemulihan.
+func (r *UserRepository) SetRecoveryCodes(ctx context.Context, id primitive.ObjectID, hashes []string) error {
+	filter := bson.M{"_id": id, "mfa.enabled_at": bson.M{"$exists": true}}
+	if _, err := r.Col.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"mfa.recovery_codes": hashes}}); err != nil {
+		return apperror.Internal(err)
+	}
+	return nil
+}
+
+// DisableMFA menghapus pengaturan TOTP user.
+func (r *UserRepository) DisableMFA(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.Col.UpdateByID(ctx, id, bson.M{"$unset": bson.M{"mfa": ""}})
+	if err != nil {
+		return apperror.Internal(err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrUserNotFound()
+	}
+	return nil
+}
+
+// ✅ Tambah user baru (untuk register). Password di-hash sebelum disimpan.
 func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
-	_, err := r.Col.InsertOne(ctx, user)
-	return err
+	if user.Password != "" {
+		hash, err := hashPassword(user.Password)
+		if err != nil {
+			return err
+		}
+		user.Password = hash
+	}
+	user.EmailIndex = crypt.EmailIndex(string(user.Email))
+	if _, err := r.Col.InsertOne(ctx, user); err != nil {
+		return mapWriteError(err,
+			conflictRule{"username_unique", ErrUsernameTaken},
+			conflictRule{"email_bidx_unique", ErrEmailTaken},
+			conflictRule{"sso_unique", ErrSSOAlreadyLinked},
+		)
+	}
+	return nil
+}
+
+// hashPassword meng-hash password baru; password lebih dari
+// model.MaxPasswordLength byte ditolak.
+func hashPassword(password string) (string, error) {
+	hash, err := model.HashPassword(password)
+	if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		return "", ErrPasswordTooLong()
+	}
+	if err != nil {
+		return "", apperror.Internal(err)
+	}
+	return hash, nil
 }
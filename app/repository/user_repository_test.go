@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"strings"
+	"testing"
+)
+
+func TestHashPassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		kind     apperror.Kind // diabaikan jika ok
+		ok       bool
+	}{
+		{"short", "rahasia", 0, true},
+		{"at limit", strings.Repeat("a", model.MaxPasswordLength), 0, true},
+		{"multibyte at limit", strings.Repeat("é", model.MaxPasswordLength/2), 0, true},
+		{"over limit", strings.Repeat("a", model.MaxPasswordLength+1), apperror.KindValidation, false},
+		{"multibyte over limit", strings.Repeat("é", model.MaxPasswordLength/2+1), apperror.KindValidation, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := hashPassword(tt.password)
+			if !tt.ok {
+				if !apperror.Is(err, tt.kind) {
+					t.Errorf("err = %v, want kind %v", err, tt.kind)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !(&model.User{Password: hash}).CheckPassword(tt.password) {
+				t.Error("hash does not match the password")
+			}
+		})
+	}
+}
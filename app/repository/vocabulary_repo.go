@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// VocabularyRepository menyimpan semua kosakata referensi dalam satu
+// koleksi yang dibedakan field type.
+type VocabularyRepository struct {
+	Col *mongo.Collection
+}
+
+func NewVocabularyRepository(db *mongo.Database) *VocabularyRepository {
+	return &VocabularyRepository{Col: db.Collection("vocabularies")}
+}
+
+func (r *VocabularyRepository) Collection() *mongo.Collection {
+	return r.Col
+}
+
+// Indexes: kode unik per jenis; daftar dropdown diurutkan per nama.
+func (r *VocabularyRepository) Indexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "type", Value: 1}, {Key: "code", Value: 1}},
+			Options: options.Index().SetName("type_code_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "type", Value: 1}, {Key: "parent", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetName("type_parent_name"),
+		},
+	}
+}
+
+// List mengembalikan term satu jenis urut nama. parent membatasi kota
+// pada satu provinsi; q mencari di nama, kode dan alias.
+func (r *VocabularyRepository) List(ctx context.Context, typ, parent, q string) ([]model.Term, error) {
+	filter := bson.M{"type": typ}
+	if parent != "" {
+		filter["parent"] = parent
+	}
+	if q != "" {
+		re := containsCI(q)
+		filter["$or"] = bson.A{bson.M{"name": re}, bson.M{"code": re}, bson.M{"aliases": re}}
+	}
+	cursor, err := r.Col.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	terms := []model.Term{}
+	if err := cursor.All(ctx, &terms); err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return terms, nil
+}
+
+func (r *VocabularyRepository) FindByID(ctx context.Context, typ string, id primitive.ObjectID) (*model.Term, error) {
+	var term model.Term
+	if err := r.Col.FindOne(ctx, bson.M{"_id": id, "type": typ}).Decode(&term); err != nil {
+		return nil, mapFindError(err, ErrTermNotFound)
+	}
+	return &term, nil
+}
+
+func (r *VocabularyRepository) FindByCode(ctx context.Context, typ, code string) (*model.Term, error) {
+	var term model.Term
+	if err := r.Col.FindOne(ctx, bson.M{"type": typ, "code": code}).Decode(&term); err != nil {
+		return nil, mapFindError(err, ErrTermNotFound)
+	}
+	return &term, nil
+}
+
+func (r *VocabularyRepository) Create(ctx context.Context, t *model.Term) error {
+	t.ID = primitive.NewObjectID()
+	t.SetKeys()
+	t.CreatedAt = time.Now().Unix()
+	t.UpdatedAt = t.CreatedAt
+	if _, err := r.Col.InsertOne(ctx, t); err != nil {
+		return mapWriteError(err, conflictRule{"type_code_unique", ErrTermCodeTaken})
+	}
+	return nil
+}
+
+// Upsert membuat atau memperbarui term berdasarkan type dan code (seed).
+func (r *VocabularyRepository) Upsert(ctx context.Context, t *model.Term) error {
+	t.SetKeys()
+	now := time.Now().Unix()
+	update := bson.M{
+		"$set": bson.M{
+			"name":       t.Name,
+			"parent":     t.Parent,
+			"faculty":    t.Faculty,
+			"aliases":    t.Aliases,
+			"name_key":   t.NameKey,
+			"alias_keys": t.AliasKeys,
+			"updated_at": now,
+		},
+		"$setOnInsert": bson.M{"created_at": now},
+	}
+	_, err := r.Col.UpdateOne(ctx, bson.M{"type": t.Type, "code": t.Code}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return apperror.Internal(err)
+	}
+	return nil
+}
+
+func (r *VocabularyRepository) Update(ctx context.Context, id primitive.ObjectID, t *model.Term) error {
+	t.SetKeys()
+	update := bson.M{"$set": bson.M{
+		"code":       t.Code,
+		"name":       t.Name,
+		"parent":     t.Parent,
+		"faculty":    t.Faculty,
+		"aliases":    t.Aliases,
+		"name_key":   t.NameKey,
+		"alias_keys": t.AliasKeys,
+		"updated_at": time.Now().Unix(),
+	}}
+	result, err := r.Col.UpdateOne(ctx, bson.M{"_id": id, "type": t.Type}, update)
+	if err != nil {
+		return mapWriteError(err, conflictRule{"type_code_unique", ErrTermCodeTaken})
+	}
+	if result.MatchedCount == 0 {
+		return ErrTermNotFound()
+	}
+	return nil
+}
+
+func (r *VocabularyRepository) Delete(ctx context.Context, typ string, id primitive.ObjectID) error {
+	result, err := r.Col.DeleteOne(ctx, bson.M{"_id": id, "type": typ})
+	if err != nil {
+		return apperror.Internal(err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrTermNotFound()
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package salary
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultBounds adalah batas bracket gaji tracer study nasional dalam
+// rupiah per bulan: < 1 juta, 1-3 juta, 3-5 juta, 5-10 juta, > 10 juta.
+var DefaultBounds = []int64{1_000_000, 3_000_000, 5_000_000, 10_000_000}
+
+// Bracket adalah satu rentang gaji bulanan dalam rupiah. Max 0 berarti
+// tanpa batas atas.
+type Bracket struct {
+	Min   int64  `json:"min"`
+	Max   int64  `json:"max,omitempty"`
+	Label string `json:"label"`
+}
+
+// Config berisi bracket laporan dan kurs mata uang lain ke rupiah agar
+// gaji bisa dibandingkan antar data.
+type Config struct {
+	Brackets []Bracket
+	Rates    map[string]float64
+}
+
+// New membuat Config dari batas bracket (menaik, rupiah per bulan) dan
+// kurs ke rupiah. IDR selalu berkurs 1.
+func New(bounds []int64, rates map[string]float64) (Config, error) {
+	if len(bounds) == 0 {
+		return Config{}, fmt.Errorf("salary brackets are empty")
+	}
+	cfg := Config{Rates: map[string]float64{model.CurrencyIDR: 1}}
+	for code, rate := range rates {
+		if rate <= 0 {
+			return Config{}, fmt.Errorf("rate for %s must be positive", code)
+		}
+		cfg.Rates[strings.ToUpper(code)] = rate
+	}
+
+	var prev int64
+	for i, b := range bounds {
+		if b <= prev {
+			return Config{}, fmt.Errorf("salary brackets must be positive and ascending, got %d after %d", b, prev)
+		}
+		label := fmt.Sprintf("%s - %s", rupiah(prev), rupiah(b))
+		if i == 0 {
+			label = "< " + rupiah(b)
+		}
+		cfg.Brackets = append(cfg.Brackets, Bracket{Min: prev, Max: b, Label: label})
+		prev = b
+	}
+	cfg.Brackets = append(cfg.Brackets, Bracket{Min: prev, Label: "> " + rupiah(prev)})
+	return cfg, nil
+}
+
+// FromEnv membaca SALARY_BRACKETS (batas dipisah koma, rupiah per bulan)
+// dan SALARY_RATES (misalnya "USD=16000,SGD=12000"). Tanpa variabel
+// tersebut dipakai DefaultBounds dan hanya IDR.
+func FromEnv() (Config, error) {
+	bounds := DefaultBounds
+	if v := strings.TrimSpace(os.Getenv("SALARY_BRACKETS")); v != "" {
+		bounds = nil
+		for _, part := range strings.Split(v, ",") {
+			n, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+			if err != nil {
+				return Config{}, fmt.Errorf("SALARY_BRACKETS: %q is not a number", part)
+			}
+			bounds = append(bounds, n)
+		}
+	}
+
+	rates := map[string]float64{}
+	if v := strings.TrimSpace(os.Getenv("SALARY_RATES")); v != "" {
+		for _, part := range strings.Split(v, ",") {
+			code, value, ok := strings.Cut(part, "=")
+			rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if !ok || err != nil {
+				return Config{}, fmt.Errorf("SALARY_RATES: %q must look like USD=16000", part)
+			}
+			rates[strings.TrimSpace(code)] = rate
+		}
+	}
+	return New(bounds, rates)
+}
+
+// Currencies mengembalikan kode mata uang yang diterima, terurut.
+func (c Config) Currencies() []string {
+	codes := make([]string, 0, len(c.Rates))
+	for code := range c.Rates {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Monthly menormalkan nilai gaji ke rupiah per bulan.
+func (c Config) Monthly(amount int64, currency, period string) (int64, bool) {
+	rate, ok := c.Rates[currency]
+	if !ok {
+		return 0, false
+	}
+	v := float64(amount) * rate
+	if period == model.PeriodYear {
+		v /= 12
+	}
+	return int64(math.Round(v)), true
+}
+
+// BracketOf mengembalikan bracket untuk gaji bulanan dalam rupiah.
+func (c Config) BracketOf(monthly int64) Bracket {
+	for _, b := range c.Brackets {
+		if b.Max == 0 || monthly < b.Max {
+			return b
+		}
+	}
+	return c.Brackets[len(c.Brackets)-1]
+}
+
+// Validate memeriksa mata uang dan memastikan rentang gaji berada di
+// dalam satu bracket sehingga bisa dilaporkan tanpa ambigu. Rentang tanpa
+// batas atas hanya boleh di bracket teratas.
+func (c Config) Validate(s model.Salary) []apperror.FieldError {
+	min, ok := c.Monthly(s.Min, s.Currency, s.Period)
+	if !ok {
+		return []apperror.FieldError{{
+			Field:   "gaji.currency",
+			Code:    "oneof",
+			Message: fmt.Sprintf("must be one of [%s]", strings.Join(c.Currencies(), " ")),
+		}}
+	}
+
+	b := c.BracketOf(min)
+	if s.Max == 0 {
+		if b.Max != 0 {
+			return []apperror.FieldError{{
+				Field:   "gaji.max",
+				Code:    "required",
+				Message: fmt.Sprintf("is required unless the range starts in the top bracket (%s)", c.Brackets[len(c.Brackets)-1].Label),
+			}}
+		}
+		return nil
+	}
+
+	if max, _ := c.Monthly(s.Max, s.Currency, s.Period); b.Max != 0 && max > b.Max {
+		return []apperror.FieldError{{
+			Field:   "gaji.max",
+			Code:    "bracket",
+			Message: fmt.Sprintf("range must fit in one salary bracket (%s per month)", b.Label),
+		}}
+	}
+	return nil
+}
+
+// rupiah memformat nominal untuk label bracket, misalnya "Rp1 juta" atau
+// "Rp1.500.000".
+func rupiah(n int64) string {
+	if n == 0 {
+		return "Rp0"
+	}
+	if n%1_000_000 == 0 {
+		return fmt.Sprintf("Rp%d juta", n/1_000_000)
+	}
+	s := strconv.FormatInt(n, 10)
+	var b strings.Builder
+	for i, r := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(r)
+	}
+	return "Rp" + b.String()
+}
@@ -0,0 +1,146 @@
+package salary
+
+import (
+	model "Mango/app/Model"
+	"testing"
+)
+
+func defaultConfig(t *testing.T) Config {
+	t.Helper()
+	cfg, err := New(DefaultBounds, map[string]float64{"usd": 16000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestNewBrackets(t *testing.T) {
+	cfg := defaultConfig(t)
+	want := []Bracket{
+		{Min: 0, Max: 1_000_000, Label: "< Rp1 juta"},
+		{Min: 1_000_000, Max: 3_000_000, Label: "Rp1 juta - Rp3 juta"},
+		{Min: 3_000_000, Max: 5_000_000, Label: "Rp3 juta - Rp5 juta"},
+		{Min: 5_000_000, Max: 10_000_000, Label: "Rp5 juta - Rp10 juta"},
+		{Min: 10_000_000, Label: "> Rp10 juta"},
+	}
+	if len(cfg.Brackets) != len(want) {
+		t.Fatalf("got %d brackets, want %d", len(cfg.Brackets), len(want))
+	}
+	for i, b := range cfg.Brackets {
+		if b != want[i] {
+			t.Errorf("bracket %d = %+v, want %+v", i, b, want[i])
+		}
+	}
+	if got := cfg.Currencies(); len(got) != 2 || got[0] != "IDR" || got[1] != "USD" {
+		t.Errorf("Currencies = %v, want [IDR USD]", got)
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		bounds []int64
+		rates  map[string]float64
+	}{
+		{"empty", nil, nil},
+		{"not ascending", []int64{3_000_000, 1_000_000}, nil},
+		{"duplicate", []int64{1_000_000, 1_000_000}, nil},
+		{"zero bound", []int64{0, 1_000_000}, nil},
+		{"negative rate", DefaultBounds, map[string]float64{"USD": -1}},
+	}
+	for _, tt := range tests {
+		if _, err := New(tt.bounds, tt.rates); err == nil {
+			t.Errorf("%s: New succeeded, want error", tt.name)
+		}
+	}
+}
+
+func TestBracketOf(t *testing.T) {
+	cfg := defaultConfig(t)
+	tests := []struct {
+		monthly int64
+		want    string
+	}{
+		{0, "< Rp1 juta"},
+		{999_999, "< Rp1 juta"},
+		{1_000_000, "Rp1 juta - Rp3 juta"},
+		{4_999_999, "Rp3 juta - Rp5 juta"},
+		{10_000_000, "> Rp10 juta"},
+		{250_000_000, "> Rp10 juta"},
+	}
+	for _, tt := range tests {
+		if got := cfg.BracketOf(tt.monthly).Label; got != tt.want {
+			t.Errorf("BracketOf(%d) = %q, want %q", tt.monthly, got, tt.want)
+		}
+	}
+}
+
+func TestMonthly(t *testing.T) {
+	cfg := defaultConfig(t)
+	tests := []struct {
+		amount   int64
+		currency string
+		period   string
+		want     int64
+		ok       bool
+	}{
+		{6_000_000, "IDR", model.PeriodMonth, 6_000_000, true},
+		{120_000_000, "IDR", model.PeriodYear, 10_000_000, true},
+		{1_000, "USD", model.PeriodMonth, 16_000_000, true},
+		{100_000, "IDR", model.PeriodYear, 8_333, true},
+		{1_000, "SGD", model.PeriodMonth, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := cfg.Monthly(tt.amount, tt.currency, tt.period)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Monthly(%d %s/%s) = %d, %v; want %d, %v", tt.amount, tt.currency, tt.period, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := defaultConfig(t)
+	tests := []struct {
+		name  string
+		s     model.Salary
+		field string // field error yang diharapkan, kosong jika valid
+	}{
+		{"within bracket", model.Salary{Min: 5_000_000, Max: 7_000_000, Currency: "IDR", Period: model.PeriodMonth}, ""},
+		{"up to bracket bound", model.Salary{Min: 5_000_000, Max: 10_000_000, Currency: "IDR", Period: model.PeriodMonth}, ""},
+		{"single value", model.Salary{Min: 4_000_000, Max: 4_000_000, Currency: "IDR", Period: model.PeriodMonth}, ""},
+		{"open range in top bracket", model.Salary{Min: 12_000_000, Currency: "IDR", Period: model.PeriodMonth}, ""},
+		{"yearly within bracket", model.Salary{Min: 72_000_000, Max: 96_000_000, Currency: "IDR", Period: model.PeriodYear}, ""},
+		{"foreign currency", model.Salary{Min: 400, Max: 600, Currency: "USD", Period: model.PeriodMonth}, ""},
+		{"foreign currency spans brackets", model.Salary{Min: 200, Max: 400, Currency: "USD", Period: model.PeriodMonth}, "gaji.max"},
+		{"spans brackets", model.Salary{Min: 4_000_000, Max: 6_000_000, Currency: "IDR", Period: model.PeriodMonth}, "gaji.max"},
+		{"open range below top", model.Salary{Min: 4_000_000, Currency: "IDR", Period: model.PeriodMonth}, "gaji.max"},
+		{"unknown currency", model.Salary{Min: 1_000, Max: 2_000, Currency: "SGD", Period: model.PeriodMonth}, "gaji.currency"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := cfg.Validate(tt.s)
+			switch {
+			case tt.field == "" && len(fields) > 0:
+				t.Errorf("Validate = %+v, want valid", fields)
+			case tt.field != "" && (len(fields) != 1 || fields[0].Field != tt.field):
+				t.Errorf("Validate = %+v, want error on %s", fields, tt.field)
+			}
+		})
+	}
+}
+
+func TestRupiah(t *testing.T) {
+	tests := map[int64]string{
+		0:          "Rp0",
+		1_000_000:  "Rp1 juta",
+		10_000_000: "Rp10 juta",
+		1_500_000:  "Rp1.500.000",
+		750_000:    "Rp750.000",
+		999:        "Rp999",
+	}
+	for n, want := range tests {
+		if got := rupiah(n); got != want {
+			t.Errorf("rupiah(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
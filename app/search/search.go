@@ -0,0 +1,193 @@
+// Package search berisi normalisasi query, penilaian relevansi dan
+// highlight untuk pencarian alumni. Pengambilan kandidat dilakukan oleh
+// repository (text index MongoDB); paket ini menilai ulang hasilnya agar
+// urutan dan highlight konsisten, termasuk untuk nama yang salah ketik.
+package search
+
+import (
+	"Mango/app/fuzzy"
+	"html"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Bobot field; nama dan NIM paling menentukan, jurusan paling lemah.
+// Nilai yang sama dipakai sebagai bobot text index.
+var Weights = map[string]int{
+	"nama":      10,
+	"nim":       10,
+	"email":     5,
+	"employers": 3,
+	"positions": 3,
+	"jurusan":   2,
+}
+
+// MinTypoLength adalah panjang minimal kata query yang boleh cocok dengan
+// nama walaupun salah ketik; kata yang lebih pendek terlalu ambigu.
+const MinTypoLength = 4
+
+// minTypoSimilarity kira-kira satu salah ketik per empat huruf.
+const minTypoSimilarity = 0.75
+
+// stopwords adalah kata pengisi yang sering muncul di kalimat pencarian
+// ("teman saya Budi yang kerja di bank") dan tidak membantu pencocokan.
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "at": true, "for": true, "friend": true, "in": true,
+	"my": true, "of": true, "the": true, "who": true, "with": true, "works": true, "working": true,
+	"dan": true, "dari": true, "di": true, "ini": true, "itu": true, "ke": true, "kerja": true,
+	"bekerja": true, "saya": true, "sebagai": true, "teman": true, "yang": true,
+}
+
+var stripMarks = runes.Remove(runes.In(unicode.Mn))
+
+// Normalize menyamakan teks untuk dibandingkan: tanpa diakritik, huruf
+// kecil dan tanda baca menjadi spasi ("Adé-Putri" menjadi "ade putri").
+func Normalize(s string) string {
+	plain, _, err := transform.String(transform.Chain(norm.NFD, stripMarks, norm.NFC), s)
+	if err != nil {
+		plain = s
+	}
+	return fuzzy.Fold(plain)
+}
+
+// Terms memecah query menjadi kata unik yang sudah dinormalisasi, tanpa
+// kata pengisi. Jika semua kata adalah kata pengisi, kata-kata itu tetap
+// dipakai.
+func Terms(q string) []string {
+	all := strings.Fields(Normalize(q))
+	var terms []string
+	seen := map[string]bool{}
+	for _, w := range all {
+		if stopwords[w] || seen[w] {
+			continue
+		}
+		seen[w] = true
+		terms = append(terms, w)
+	}
+	if len(terms) == 0 {
+		for _, w := range all {
+			if !seen[w] {
+				seen[w] = true
+				terms = append(terms, w)
+			}
+		}
+	}
+	return terms
+}
+
+// Keys adalah kata-kata ternormalisasi dari teks, disimpan untuk mencari
+// kandidat nama yang salah ketik.
+func Keys(s string) []string {
+	var keys []string
+	seen := map[string]bool{}
+	for _, w := range strings.Fields(Normalize(s)) {
+		if !seen[w] {
+			seen[w] = true
+			keys = append(keys, w)
+		}
+	}
+	return keys
+}
+
+// Document adalah nilai field satu alumni yang dinilai dan di-highlight.
+// Key map adalah nama field pada Weights.
+type Document map[string][]string
+
+// Result adalah skor relevansi dan fragmen yang di-highlight per field.
+type Result struct {
+	Score      float64
+	Highlights map[string][]string
+}
+
+// Rank menilai dokumen terhadap kata query. Setiap kata mengambil
+// kecocokan terbaik di semua field (bobot field × kualitas kecocokan):
+// sama persis 1, awalan 0.9, salah ketik pada nama sebesar kemiripannya
+// dikurangi 20%. Skor 0 berarti dokumen tidak relevan.
+func Rank(terms []string, doc Document) Result {
+	res := Result{Highlights: map[string][]string{}}
+	matched := map[string]map[string]bool{} // field -> kata dokumen yang cocok
+
+	for _, t := range terms {
+		best := 0.0
+		for field, values := range doc {
+			for _, v := range values {
+				for _, w := range strings.Fields(Normalize(v)) {
+					q := quality(t, w, field == "nama")
+					if q == 0 {
+						continue
+					}
+					if matched[field] == nil {
+						matched[field] = map[string]bool{}
+					}
+					matched[field][w] = true
+					best = max(best, q*float64(Weights[field]))
+				}
+			}
+		}
+		res.Score += best
+	}
+
+	for field, words := range matched {
+		for _, v := range doc[field] {
+			if frag, ok := highlight(v, words); ok {
+				res.Highlights[field] = append(res.Highlights[field], frag)
+			}
+		}
+	}
+	res.Score = float64(int(res.Score*1000+0.5)) / 1000
+	return res
+}
+
+func quality(term, word string, typos bool) float64 {
+	switch {
+	case word == term:
+		return 1
+	case len([]rune(term)) >= 2 && strings.HasPrefix(word, term):
+		return 0.9
+	case typos && len([]rune(term)) >= MinTypoLength:
+		if s := fuzzy.Similarity(term, word); s >= minTypoSimilarity {
+			return s * 0.8
+		}
+	}
+	return 0
+}
+
+// highlight membungkus kata pada text yang ada di words dengan <em>.
+// Teks lain di-escape agar aman ditampilkan sebagai HTML.
+func highlight(text string, words map[string]bool) (string, bool) {
+	var b strings.Builder
+	found := false
+	rs := []rune(text)
+	for i := 0; i < len(rs); {
+		if !isWordRune(rs[i]) {
+			j := i
+			for j < len(rs) && !isWordRune(rs[j]) {
+				j++
+			}
+			b.WriteString(html.EscapeString(string(rs[i:j])))
+			i = j
+			continue
+		}
+		j := i
+		for j < len(rs) && isWordRune(rs[j]) {
+			j++
+		}
+		word := string(rs[i:j])
+		if words[Normalize(word)] {
+			found = true
+			b.WriteString("<em>" + html.EscapeString(word) + "</em>")
+		} else {
+			b.WriteString(html.EscapeString(word))
+		}
+		i = j
+	}
+	return b.String(), found
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
@@ -0,0 +1,113 @@
+package search
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Adé-Putri":       "ade putri",
+		"  SITI   Rahma ": "siti rahma",
+		"O'Neil, Jr.":     "o neil jr",
+		"Çağlar Ünal":     "caglar unal",
+		"SEED000001":      "seed000001",
+	}
+	for in, want := range tests {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		q    string
+		want []string
+	}{
+		{"teman saya Budi yang kerja di bank", []string{"budi", "bank"}},
+		{"Budi budi BUDI", []string{"budi"}},
+		{"my friend who works at Telkom", []string{"telkom"}},
+		{"yang di", []string{"yang", "di"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := Terms(tt.q); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Terms(%q) = %v, want %v", tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestRankScore(t *testing.T) {
+	budi := Document{
+		"nama":      {"Budi Santoso"},
+		"nim":       {"SEED000001"},
+		"employers": {"Bank Mandiri"},
+		"jurusan":   {"Teknik Informatika"},
+	}
+	tests := []struct {
+		name  string
+		terms []string
+		want  float64
+	}{
+		{"exact name", []string{"budi"}, 10},
+		{"name prefix", []string{"bud"}, 9},
+		{"name typo", []string{"budy"}, 6}, // kemiripan 0.75 × 0.8 × 10
+		{"nim", []string{"seed000001"}, 10},
+		{"employer and name", []string{"budi", "mandiri"}, 13},
+		{"weak field", []string{"informatika"}, 2},
+		{"no typo outside name", []string{"informatica"}, 0},
+		{"short term no typo", []string{"bdi"}, 0},
+		{"unrelated", []string{"siti"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Rank(tt.terms, budi).Score; got != tt.want {
+				t.Errorf("Score = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankOrder(t *testing.T) {
+	docs := map[string]Document{
+		"exact":    {"nama": {"Siti Rahma"}},
+		"prefix":   {"nama": {"Sitiana Dewi"}},
+		"typo":     {"nama": {"Sita Rahmawati"}},
+		"employer": {"nama": {"Andi"}, "employers": {"Siti Group"}},
+		"none":     {"nama": {"Budi"}, "jurusan": {"Sistem Informasi"}},
+	}
+	terms := Terms("siti")
+
+	var ranked []string
+	scores := map[string]float64{}
+	for id, doc := range docs {
+		if s := Rank(terms, doc).Score; s > 0 {
+			ranked = append(ranked, id)
+			scores[id] = s
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool { return scores[ranked[i]] > scores[ranked[j]] })
+
+	want := []string{"exact", "prefix", "typo", "employer"}
+	if !reflect.DeepEqual(ranked, want) {
+		t.Errorf("order = %v (scores %v), want %v", ranked, scores, want)
+	}
+}
+
+func TestRankHighlights(t *testing.T) {
+	doc := Document{
+		"nama":      {"Adé <Putri>"},
+		"employers": {"PT Putri & Co", "Bank Mandiri"},
+	}
+	res := Rank(Terms("ade putri"), doc)
+
+	want := map[string][]string{
+		"nama":      {"<em>Adé</em> &lt;<em>Putri</em>&gt;"},
+		"employers": {"PT <em>Putri</em> &amp; Co"},
+	}
+	if !reflect.DeepEqual(res.Highlights, want) {
+		t.Errorf("Highlights = %q, want %q", res.Highlights, want)
+	}
+}
@@ -0,0 +1,42 @@
+package totp
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// RecoveryCodeCount adalah jumlah kode pemulihan yang dibuat sekaligus.
+const RecoveryCodeCount = 10
+
+var recoveryEncoding = strings.ToLower("ABCDEFGHIJKLMNOPQRSTUVWXYZ234567")
+
+// NewRecoveryCodes membuat n kode pemulihan acak berbentuk "xxxxx-xxxxx"
+// (50 bit). Kode ditampilkan sekali ke user; simpan hanya HashRecoveryCode.
+func NewRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, n)
+	raw := make([]byte, 10)
+	for i := range codes {
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		var b strings.Builder
+		for j, v := range raw {
+			if j == 5 {
+				b.WriteByte('-')
+			}
+			b.WriteByte(recoveryEncoding[v&31])
+		}
+		codes[i] = b.String()
+	}
+	return codes, nil
+}
+
+// HashRecoveryCode menormalkan kode (huruf kecil, tanpa tanda hubung dan
+// spasi) lalu mengembalikan hash SHA-256-nya.
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
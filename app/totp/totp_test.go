@@ -0,0 +1,118 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret adalah secret SHA-1 dari RFC 6238 lampiran B ("12345678901234567890").
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	// Kode 8 digit dari RFC 6238 lampiran B, dipotong ke 6 digit terakhir
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Code at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	step := Step(now)
+	code := func(s int64) string {
+		c, err := Code(rfcSecret, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		secret   string
+		input    string
+		wantStep int64
+		ok       bool
+	}{
+		{"current period", rfcSecret, code(step), step, true},
+		{"previous period", rfcSecret, code(step - 1), step - 1, true},
+		{"next period", rfcSecret, code(step + 1), step + 1, true},
+		{"too old", rfcSecret, code(step - 2), 0, false},
+		{"too new", rfcSecret, code(step + 2), 0, false},
+		{"spaces", rfcSecret, " " + code(step)[:3] + " " + code(step)[3:] + " ", step, true},
+		{"lowercase padded secret", strings.ToLower(rfcSecret) + "====", code(step), step, true},
+		{"wrong code", rfcSecret, "000000", 0, false},
+		{"too short", rfcSecret, code(step)[:5], 0, false},
+		{"too long", rfcSecret, code(step) + "0", 0, false},
+		{"empty", rfcSecret, "", 0, false},
+		{"invalid secret", "not base32!", code(step), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Validate(tt.secret, tt.input, now)
+			if ok != tt.ok || got != tt.wantStep {
+				t.Errorf("Validate = %d, %v; want %d, %v", got, ok, tt.wantStep, tt.ok)
+			}
+		})
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	a, err := NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := NewSecret()
+	if a == b {
+		t.Error("two secrets are equal")
+	}
+	if key, err := decode(a); err != nil || len(key) != 20 {
+		t.Errorf("secret decodes to %d bytes (err %v), want 20", len(key), err)
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := NewRecoveryCodes(RecoveryCodeCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != RecoveryCodeCount {
+		t.Fatalf("got %d codes, want %d", len(codes), RecoveryCodeCount)
+	}
+	seen := map[string]bool{}
+	for _, c := range codes {
+		if len(c) != 11 || c[5] != '-' || strings.Trim(c, recoveryEncoding+"-") != "" {
+			t.Errorf("code %q is not xxxxx-xxxxx", c)
+		}
+		if seen[c] {
+			t.Errorf("duplicate code %q", c)
+		}
+		seen[c] = true
+	}
+
+	want := HashRecoveryCode("abcde-fghij")
+	for _, in := range []string{"ABCDE-FGHIJ", "abcdefghij", " abcde fghij "} {
+		if got := HashRecoveryCode(in); got != want {
+			t.Errorf("HashRecoveryCode(%q) differs from the normalised code", in)
+		}
+	}
+	if HashRecoveryCode("abcde-fghik") == want {
+		t.Error("different codes hash the same")
+	}
+}
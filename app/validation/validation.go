@@ -0,0 +1,80 @@
+package validation
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+const MinYear = 1950
+
+var (
+	nimPattern   = regexp.MustCompile(`^[A-Za-z0-9]{6,20}$`)
+	phonePattern = regexp.MustCompile(`^(\+62|62|0)[2-9][0-9]{7,11}$`)
+	phoneStrip   = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "")
+)
+
+// Register memasang aturan validasi tambahan ke validator milik gin
+// sehingga tag `binding:"..."` pada DTO request bisa memakainya.
+// Nama field pada error mengikuti tag json.
+func Register() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return nil
+	}
+
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return f.Name
+		}
+		return name
+	})
+
+	rules := map[string]validator.Func{
+		"nim":      func(fl validator.FieldLevel) bool { return IsNIM(fl.Field().String()) },
+		"phone_id": func(fl validator.FieldLevel) bool { return IsPhoneID(fl.Field().String()) },
+		"year":     func(fl validator.FieldLevel) bool { return IsYear(int(fl.Field().Int())) },
+		"month":    func(fl validator.FieldLevel) bool { return IsMonth(fl.Field().String()) },
+	}
+	for tag, fn := range rules {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsNIM memeriksa format Nomor Induk Mahasiswa: 6-20 huruf atau angka.
+func IsNIM(s string) bool {
+	return nimPattern.MatchString(s)
+}
+
+// IsPhoneID memeriksa nomor telepon Indonesia (awalan 0, 62 atau +62).
+// Spasi, tanda hubung, titik dan kurung diabaikan.
+func IsPhoneID(s string) bool {
+	return phonePattern.MatchString(NormalizePhone(s))
+}
+
+// NormalizePhone membuang karakter pemisah dari nomor telepon.
+func NormalizePhone(s string) string {
+	return phoneStrip.Replace(strings.TrimSpace(s))
+}
+
+// IsMonth memeriksa format "YYYY-MM" dengan tahun yang valid.
+func IsMonth(s string) bool {
+	t, err := time.Parse("2006-01", s)
+	return err == nil && IsYear(t.Year())
+}
+
+// IsYear memeriksa tahun antara MinYear dan tahun depan.
+func IsYear(y int) bool {
+	return y >= MinYear && y <= time.Now().Year()+1
+}
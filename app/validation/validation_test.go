@@ -0,0 +1,140 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+func TestIsNIM(t *testing.T) {
+	tests := []struct {
+		nim  string
+		want bool
+	}{
+		{"SEED000001", true},
+		{"123456", true},
+		{"a1b2c3d4e5f6g7h8i9j0", true},
+		{"12345", false},
+		{"a1b2c3d4e5f6g7h8i9j0k", false},
+		{"1234-5678", false},
+		{"1234 5678", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsNIM(tt.nim); got != tt.want {
+			t.Errorf("IsNIM(%q) = %v, want %v", tt.nim, got, tt.want)
+		}
+	}
+}
+
+func TestIsPhoneID(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  bool
+	}{
+		{"081234567890", true},
+		{"+6281234567890", true},
+		{"6281234567890", true},
+		{"0812-3456-7890", true},
+		{"(031) 5994251", true},
+		{"0812.3456.789", true},
+		{"0112345678", false}, // digit setelah awalan harus 2-9
+		{"08123", false},
+		{"0812345678901234", false},
+		{"+1 202 555 0100", false},
+		{"nomor", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsPhoneID(tt.phone); got != tt.want {
+			t.Errorf("IsPhoneID(%q) = %v, want %v", tt.phone, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	tests := map[string]string{
+		" 0812-3456-7890 ": "081234567890",
+		"(031) 599.4251":   "0315994251",
+		"+62 812 3456":     "+628123456",
+	}
+	for in, want := range tests {
+		if got := NormalizePhone(in); got != want {
+			t.Errorf("NormalizePhone(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestIsYear(t *testing.T) {
+	next := time.Now().Year() + 1
+	tests := []struct {
+		year int
+		want bool
+	}{
+		{MinYear, true},
+		{2020, true},
+		{next, true},
+		{MinYear - 1, false},
+		{next + 1, false},
+		{0, false},
+	}
+	for _, tt := range tests {
+		if got := IsYear(tt.year); got != tt.want {
+			t.Errorf("IsYear(%d) = %v, want %v", tt.year, got, tt.want)
+		}
+	}
+}
+
+func TestIsMonth(t *testing.T) {
+	tests := []struct {
+		month string
+		want  bool
+	}{
+		{"2020-08", true},
+		{time.Now().AddDate(1, 0, 0).Format("2006-01"), true},
+		{"1949-12", false},
+		{"2020-13", false},
+		{"2020-8", false},
+		{"2020-08-01", false},
+		{"08-2020", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsMonth(tt.month); got != tt.want {
+			t.Errorf("IsMonth(%q) = %v, want %v", tt.month, got, tt.want)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	if err := Register(); err != nil {
+		t.Fatal(err)
+	}
+
+	type request struct {
+		NIM      string `json:"nim" binding:"required,nim"`
+		Phone    string `json:"no_telp" binding:"omitempty,phone_id"`
+		Angkatan int    `json:"angkatan" binding:"year"`
+		Mulai    string `json:"tanggal_kerja" binding:"omitempty,month"`
+	}
+	tests := []struct {
+		name  string
+		req   request
+		valid bool
+	}{
+		{"valid", request{NIM: "SEED000001", Phone: "081234567890", Angkatan: 2016}, true},
+		{"bad nim", request{NIM: "S-1", Angkatan: 2016}, false},
+		{"bad phone", request{NIM: "SEED000001", Phone: "12345", Angkatan: 2016}, false},
+		{"bad year", request{NIM: "SEED000001", Angkatan: 1900}, false},
+		{"bad month", request{NIM: "SEED000001", Angkatan: 2016, Mulai: "2020/08"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := binding.Validator.ValidateStruct(&tt.req)
+			if (err == nil) != tt.valid {
+				t.Errorf("ValidateStruct = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package vocab
+
+import (
+	model "Mango/app/Model"
+	"Mango/app/repository"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// minMapScore adalah skor minimum agar teks bebas dipetakan otomatis ke
+// term yang tidak persis sama.
+const minMapScore = 0.85
+
+// MapReport merangkum hasil pemetaan satu field.
+type MapReport struct {
+	Field     string   `json:"field"`
+	Mapped    int64    `json:"mapped"`
+	Unmatched []string `json:"unmatched"`
+}
+
+// target adalah field teks bebas yang dipetakan ke kosakata. Kode term
+// disimpan di codeField; extra mengisi field turunan lain.
+type target struct {
+	collection string
+	field      string
+	codeField  string
+	types      []string
+	extra      func(model.Term) bson.M
+}
+
+var targets = []target{
+	{"alumni", "jurusan", "kode_jurusan", []string{model.VocabJurusan}, func(t model.Term) bson.M { return bson.M{"fakultas": t.Faculty} }},
+	{"pekerjaan_alumni", "bidang_industri", "kode_industri", []string{model.VocabIndustri}, nil},
+	{"pekerjaan_alumni", "lokasi_kerja", "kode_lokasi", []string{model.VocabKota, model.VocabProvinsi}, nil},
+}
+
+// MapExisting memetakan teks bebas yang belum punya kode ke term yang
+// cocok (kode, nama, alias, atau sangat mirip) dan menggantinya dengan
+// nama baku. Nilai yang tidak cocok dibiarkan dan dilaporkan agar admin
+// bisa menambah term atau alias lalu menjalankan ulang.
+func MapExisting(ctx context.Context, db *mongo.Database) ([]MapReport, error) {
+	repo := repository.NewVocabularyRepository(db)
+	var reports []MapReport
+
+	for _, tg := range targets {
+		report := MapReport{Field: tg.collection + "." + tg.field, Unmatched: []string{}}
+		var terms []model.Term
+		for _, typ := range tg.types {
+			list, err := repo.List(ctx, typ, "", "")
+			if err != nil {
+				return nil, err
+			}
+			terms = append(terms, list...)
+		}
+		if len(terms) == 0 {
+			reports = append(reports, report)
+			continue
+		}
+
+		col := db.Collection(tg.collection)
+		unmapped := bson.M{tg.codeField: bson.M{"$exists": false}, tg.field: bson.M{"$gt": ""}}
+		values, err := col.Distinct(ctx, tg.field, unmapped)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range values {
+			s, ok := v.(string)
+			if !ok {
+				continue
+			}
+			term, ok := Find(terms, s)
+			if !ok {
+				term, ok = Closest(terms, s, minMapScore)
+			}
+			if !ok {
+				report.Unmatched = append(report.Unmatched, s)
+				continue
+			}
+
+			set := bson.M{tg.field: term.Name, tg.codeField: term.Code}
+			if tg.extra != nil {
+				for k, val := range tg.extra(term) {
+					set[k] = val
+				}
+			}
+			filter := bson.M{tg.field: s, tg.codeField: bson.M{"$exists": false}}
+			result, err := col.UpdateMany(ctx, filter, bson.M{"$set": set})
+			if err != nil {
+				return nil, err
+			}
+			report.Mapped += result.ModifiedCount
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
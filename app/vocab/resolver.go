@@ -0,0 +1,185 @@
+// Package vocab mencocokkan input bebas dengan kosakata referensi
+// (jurusan, bidang industri, wilayah).
+package vocab
+
+import (
+	"Mango/app/apperror"
+	"Mango/app/cache"
+	"Mango/app/fuzzy"
+	model "Mango/app/Model"
+	"Mango/app/repository"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// minSuggestScore adalah skor minimum term yang disarankan ketika input
+// tidak cocok.
+const minSuggestScore = 0.5
+
+// Resolver menyimpan term per jenis di cache sehingga validasi tidak
+// membaca koleksi setiap request. Panggil Invalidate setelah kosakata
+// diubah.
+type Resolver struct {
+	repo  *repository.VocabularyRepository
+	cache *cache.TTL
+}
+
+func NewResolver(repo *repository.VocabularyRepository, ttl time.Duration) *Resolver {
+	return &Resolver{repo: repo, cache: cache.NewTTL(ttl)}
+}
+
+// Field adalah satu nilai yang harus sesuai kosakata. Jika cocok, Value
+// diganti nama baku term dan Match dipanggil dengan term tersebut.
+type Field struct {
+	Name  string
+	Types []string
+	Value *string
+	Match func(model.Term)
+}
+
+// Apply memeriksa semua field dan mengembalikan satu error validasi
+// untuk field yang tidak cocok. Nilai kosong dilewati. Jenis kosakata
+// yang belum berisi term sama sekali belum diberlakukan.
+func (r *Resolver) Apply(ctx context.Context, fields ...Field) error {
+	var errs []apperror.FieldError
+	for _, f := range fields {
+		if strings.TrimSpace(*f.Value) == "" {
+			continue
+		}
+		terms, err := r.Terms(ctx, f.Types...)
+		if err != nil {
+			return err
+		}
+		if len(terms) == 0 {
+			continue
+		}
+
+		if term, ok := Find(terms, *f.Value); ok {
+			*f.Value = term.Name
+			if f.Match != nil {
+				f.Match(term)
+			}
+			continue
+		}
+
+		msg := fmt.Sprintf("is not a known %s", strings.Join(f.Types, " or "))
+		if names := Suggest(terms, *f.Value, 3); len(names) > 0 {
+			quoted := make([]string, len(names))
+			for i, n := range names {
+				quoted[i] = strconv.Quote(n)
+			}
+			msg += fmt.Sprintf("; did you mean %s?", strings.Join(quoted, ", "))
+		}
+		errs = append(errs, apperror.FieldError{Field: f.Name, Code: "vocabulary", Message: msg})
+	}
+	if len(errs) > 0 {
+		return apperror.Validation("validation_failed", "Request validation failed", errs...)
+	}
+	return nil
+}
+
+// Terms mengembalikan gabungan term dari jenis yang diminta.
+func (r *Resolver) Terms(ctx context.Context, types ...string) ([]model.Term, error) {
+	var all []model.Term
+	for _, typ := range types {
+		if cached, ok := r.cache.Get(typ); ok {
+			all = append(all, cached.([]model.Term)...)
+			continue
+		}
+		terms, err := r.repo.List(ctx, typ, "", "")
+		if err != nil {
+			return nil, err
+		}
+		r.cache.Set(typ, terms)
+		all = append(all, terms...)
+	}
+	return all, nil
+}
+
+// Invalidate membuang cache setelah kosakata berubah.
+func (r *Resolver) Invalidate() {
+	r.cache.Clear()
+}
+
+// Find mencari term yang kode, nama atau aliasnya sama dengan input.
+func Find(terms []model.Term, input string) (model.Term, bool) {
+	for _, t := range terms {
+		if t.Matches(input) {
+			return t, true
+		}
+	}
+	return model.Term{}, false
+}
+
+// Closest mengembalikan term yang paling mirip dengan input jika skornya
+// minimal min dan tidak seri dengan term lain.
+func Closest(terms []model.Term, input string, min float64) (model.Term, bool) {
+	scored := rank(terms, input)
+	if len(scored) == 0 || scored[0].score < min {
+		return model.Term{}, false
+	}
+	if len(scored) > 1 && scored[1].score == scored[0].score {
+		return model.Term{}, false
+	}
+	return scored[0].term, true
+}
+
+// Suggest mengembalikan hingga n nama term yang mirip dengan input.
+func Suggest(terms []model.Term, input string, n int) []string {
+	var names []string
+	for _, s := range rank(terms, input) {
+		if s.score < minSuggestScore || len(names) == n {
+			break
+		}
+		names = append(names, s.term.Name)
+	}
+	return names
+}
+
+type scoredTerm struct {
+	term  model.Term
+	score float64
+}
+
+func rank(terms []model.Term, input string) []scoredTerm {
+	key := fuzzy.Fold(input)
+	scored := make([]scoredTerm, 0, len(terms))
+	for _, t := range terms {
+		best := fuzzy.Score(key, t.NameKey)
+		for _, k := range t.AliasKeys {
+			best = max(best, fuzzy.Score(key, k))
+		}
+		scored = append(scored, scoredTerm{t, best})
+	}
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].score > scored[j].score })
+	return scored
+}
+
+// Jurusan mencocokkan jurusan alumni dengan program studi dan mengisi
+// kode jurusan serta fakultas.
+func Jurusan(a *model.Alumni) Field {
+	a.KodeJurusan, a.Fakultas = "", ""
+	return Field{Name: "jurusan", Types: []string{model.VocabJurusan}, Value: &a.Jurusan, Match: func(t model.Term) {
+		a.KodeJurusan, a.Fakultas = t.Code, t.Faculty
+	}}
+}
+
+// Industri mencocokkan bidang industri pekerjaan dengan kategori KBLI.
+func Industri(p *model.Pekerjaan) Field {
+	p.KodeIndustri = ""
+	return Field{Name: "bidang_industri", Types: []string{model.VocabIndustri}, Value: &p.Bidang_Industri, Match: func(t model.Term) {
+		p.KodeIndustri = t.Code
+	}}
+}
+
+// Lokasi mencocokkan lokasi kerja dengan kota lalu provinsi.
+func Lokasi(p *model.Pekerjaan) Field {
+	p.KodeLokasi = ""
+	return Field{Name: "lokasi_kerja", Types: []string{model.VocabKota, model.VocabProvinsi}, Value: &p.Lokasi_kerja, Match: func(t model.Term) {
+		p.KodeLokasi = t.Code
+	}}
+}
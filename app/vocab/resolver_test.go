@@ -0,0 +1,270 @@
+package vocab
+
+import (
+	"Mango/app/apperror"
+	model "Mango/app/Model"
+	"Mango/app/repository"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func withKeys(typ string, terms ...model.Term) []model.Term {
+	out := make([]model.Term, len(terms))
+	for i, t := range terms {
+		t.Type = typ
+		t.SetKeys()
+		out[i] = t
+	}
+	return out
+}
+
+var (
+	testJurusan = withKeys(model.VocabJurusan,
+		model.Term{Code: "IF", Name: "Teknik Informatika", Faculty: "Fakultas Teknik", Aliases: []string{"Informatika", "TI"}},
+		model.Term{Code: "SI", Name: "Sistem Informasi", Faculty: "Fakultas Teknik"},
+		model.Term{Code: "MN", Name: "Manajemen", Faculty: "Fakultas Ekonomi"},
+	)
+	testKota = withKeys(model.VocabKota,
+		model.Term{Code: "3273", Name: "Kota Bandung", Parent: "32", Aliases: []string{"Bandung"}},
+		model.Term{Code: "3171", Name: "Kota Jakarta Selatan", Parent: "31", Aliases: []string{"Jakarta Selatan", "Jaksel"}},
+	)
+	testProvinsi = withKeys(model.VocabProvinsi, provinces...)
+)
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"IF", "IF", true},
+		{"if", "IF", true},
+		{" Teknik Informatika ", "IF", true},
+		{"teknik-informatika", "IF", true},
+		{"informatika", "IF", true},
+		{"Sistem Informasi", "SI", true},
+		{"Teknik Informatik", "", false},
+		{"Kedokteran", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Find(testJurusan, tt.input)
+		if ok != tt.ok || got.Code != tt.want {
+			t.Errorf("Find(%q) = %q, %v, want %q, %v", tt.input, got.Code, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestClosest(t *testing.T) {
+	ambiguous := withKeys(model.VocabJurusan,
+		model.Term{Code: "A", Name: "Teknik Sipil"},
+		model.Term{Code: "B", Name: "Teknik Mesin"},
+	)
+
+	tests := []struct {
+		name  string
+		terms []model.Term
+		input string
+		min   float64
+		want  string
+		ok    bool
+	}{
+		{"typo", testJurusan, "Teknik Informatka", minMapScore, "IF", true},
+		{"typo in single word", testJurusan, "Manajmen", minMapScore, "MN", true},
+		{"below minimum", testJurusan, "Teknik Elektro", minMapScore, "", false},
+		{"tie", ambiguous, "Teknik", 0.5, "", false},
+		{"no terms", nil, "Teknik", 0.5, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Closest(tt.terms, tt.input, tt.min)
+			if ok != tt.ok || got.Code != tt.want {
+				t.Errorf("Closest(%q) = %q, %v, want %q, %v", tt.input, got.Code, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		input string
+		n     int
+		want  []string
+	}{
+		{"Sistem Informatika", 3, []string{"Sistem Informasi", "Teknik Informatika"}},
+		{"Sistem Informatika", 1, []string{"Sistem Informasi"}},
+		{"Kedokteran Gigi", 3, nil},
+	}
+	for _, tt := range tests {
+		if got := Suggest(testJurusan, tt.input, tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Suggest(%q, %d) = %q, want %q", tt.input, tt.n, got, tt.want)
+		}
+	}
+}
+
+// cachedResolver mengembalikan Resolver yang cache-nya sudah terisi
+// sehingga tidak perlu membaca koleksi.
+func cachedResolver(byType map[string][]model.Term) *Resolver {
+	r := NewResolver(nil, time.Minute)
+	for typ, terms := range byType {
+		r.cache.Set(typ, terms)
+	}
+	return r
+}
+
+func TestApplyJurusan(t *testing.T) {
+	r := cachedResolver(map[string][]model.Term{model.VocabJurusan: testJurusan})
+
+	tests := []struct {
+		name     string
+		jurusan  string
+		want     string
+		kode     string
+		fakultas string
+		errMsg   string
+	}{
+		{name: "alias", jurusan: "informatika", want: "Teknik Informatika", kode: "IF", fakultas: "Fakultas Teknik"},
+		{name: "code", jurusan: "mn", want: "Manajemen", kode: "MN", fakultas: "Fakultas Ekonomi"},
+		{name: "empty skipped", jurusan: " ", want: " "},
+		{name: "unknown with suggestion", jurusan: "Sistim Informasi", want: "Sistim Informasi",
+			errMsg: `is not a known jurusan; did you mean "Sistem Informasi"`},
+		{name: "unknown without suggestion", jurusan: "Kedokteran", want: "Kedokteran",
+			errMsg: "is not a known jurusan"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// kode lama harus dibersihkan bila jurusan berubah
+			a := &model.Alumni{Jurusan: tt.jurusan, KodeJurusan: "OLD", Fakultas: "Old"}
+			err := r.Apply(context.Background(), Jurusan(a))
+
+			if tt.errMsg != "" {
+				appErr := apperror.From(err)
+				if err == nil || len(appErr.Fields) != 1 || appErr.Fields[0].Field != "jurusan" ||
+					!strings.HasPrefix(appErr.Fields[0].Message, tt.errMsg) {
+					t.Fatalf("Apply error = %v, want jurusan %q", err, tt.errMsg)
+				}
+			} else if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			if a.Jurusan != tt.want || a.KodeJurusan != tt.kode || a.Fakultas != tt.fakultas {
+				t.Errorf("alumni = %q %q %q, want %q %q %q", a.Jurusan, a.KodeJurusan, a.Fakultas, tt.want, tt.kode, tt.fakultas)
+			}
+		})
+	}
+}
+
+func TestApplyPekerjaan(t *testing.T) {
+	r := cachedResolver(map[string][]model.Term{
+		model.VocabKota:     testKota,
+		model.VocabProvinsi: testProvinsi,
+		model.VocabIndustri: {},
+	})
+
+	tests := []struct {
+		name     string
+		lokasi   string
+		industri string
+		want     string
+		kode     string
+		fields   []string
+	}{
+		{name: "kota", lokasi: "bandung", want: "Kota Bandung", kode: "3273"},
+		{name: "provinsi fallback", lokasi: "Jabar", want: "Jawa Barat", kode: "32"},
+		{name: "kota before provinsi", lokasi: "Jaksel", want: "Kota Jakarta Selatan", kode: "3171"},
+		{name: "empty industri vocabulary not enforced", lokasi: "Jakarta", industri: "Apa saja", want: "DKI Jakarta", kode: "31"},
+		{name: "unknown", lokasi: "Atlantis", want: "Atlantis", fields: []string{"lokasi_kerja"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &model.Pekerjaan{Lokasi_kerja: tt.lokasi, Bidang_Industri: tt.industri, KodeLokasi: "OLD"}
+			err := r.Apply(context.Background(), Industri(p), Lokasi(p))
+
+			var fields []string
+			if err != nil {
+				for _, f := range apperror.From(err).Fields {
+					fields = append(fields, f.Field)
+				}
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Fatalf("Apply error fields = %v, want %v (err %v)", fields, tt.fields, err)
+			}
+			if p.Lokasi_kerja != tt.want || p.KodeLokasi != tt.kode {
+				t.Errorf("lokasi = %q %q, want %q %q", p.Lokasi_kerja, p.KodeLokasi, tt.want, tt.kode)
+			}
+			if p.Bidang_Industri != tt.industri || p.KodeIndustri != "" {
+				t.Errorf("industri = %q %q, want %q unchanged", p.Bidang_Industri, p.KodeIndustri, tt.industri)
+			}
+		})
+	}
+}
+
+func TestApplyReportsAllFields(t *testing.T) {
+	r := cachedResolver(map[string][]model.Term{
+		model.VocabKota:     testKota,
+		model.VocabProvinsi: testProvinsi,
+		model.VocabIndustri: withKeys(model.VocabIndustri, kbliSections...),
+	})
+	p := &model.Pekerjaan{Lokasi_kerja: "Atlantis", Bidang_Industri: "Sihir"}
+
+	err := r.Apply(context.Background(), Industri(p), Lokasi(p))
+	appErr := apperror.From(err)
+	if err == nil || appErr.Code != "validation_failed" || len(appErr.Fields) != 2 {
+		t.Fatalf("Apply error = %v, want validation_failed on two fields", err)
+	}
+	if appErr.Fields[1].Message != "is not a known kota or provinsi" {
+		t.Errorf("lokasi message = %q", appErr.Fields[1].Message)
+	}
+}
+
+func TestTermsCache(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("reads each type once", func(mt *mtest.T) {
+		doc := bson.D{{Key: "type", Value: model.VocabJurusan}, {Key: "code", Value: "IF"}, {Key: "name", Value: "Teknik Informatika"}, {Key: "name_key", Value: "teknik informatika"}}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.vocabularies", mtest.FirstBatch, doc))
+		r := NewResolver(&repository.VocabularyRepository{Col: mt.Coll}, time.Minute)
+
+		for i := 0; i < 2; i++ {
+			terms, err := r.Terms(context.Background(), model.VocabJurusan)
+			if err != nil {
+				mt.Fatalf("Terms #%d: %v", i+1, err)
+			}
+			if len(terms) != 1 || terms[0].Code != "IF" {
+				mt.Fatalf("Terms #%d = %v, want IF", i+1, terms)
+			}
+		}
+		if n := len(mt.GetAllStartedEvents()); n != 1 {
+			mt.Errorf("sent %d commands, want 1", n)
+		}
+
+		r.Invalidate()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.vocabularies", mtest.FirstBatch))
+		terms, err := r.Terms(context.Background(), model.VocabJurusan)
+		if err != nil || len(terms) != 0 {
+			mt.Errorf("Terms after Invalidate = %v, %v, want re-read empty list", terms, err)
+		}
+	})
+}
+
+func TestSeedTermsUnambiguous(t *testing.T) {
+	for typ, terms := range map[string][]model.Term{
+		model.VocabIndustri: kbliSections,
+		model.VocabProvinsi: provinces,
+	} {
+		seen := map[string]string{}
+		for _, term := range withKeys(typ, terms...) {
+			for _, key := range append([]string{"code:" + term.Code, term.NameKey}, term.AliasKeys...) {
+				if other, ok := seen[key]; ok {
+					t.Errorf("%s: %q used by %s and %s", typ, key, other, term.Code)
+				}
+				seen[key] = term.Code
+			}
+		}
+	}
+}
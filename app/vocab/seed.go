@@ -0,0 +1,95 @@
+package vocab
+
+import (
+	model "Mango/app/Model"
+	"Mango/app/repository"
+	"context"
+)
+
+// kbliSections adalah 21 kategori (seksi) KBLI 2020 beserta alias yang
+// sering ditulis alumni.
+var kbliSections = []model.Term{
+	{Code: "A", Name: "Pertanian, Kehutanan dan Perikanan", Aliases: []string{"Pertanian", "Perkebunan", "Perikanan", "Agribisnis"}},
+	{Code: "B", Name: "Pertambangan dan Penggalian", Aliases: []string{"Pertambangan", "Tambang", "Mining", "Minyak dan Gas", "Migas"}},
+	{Code: "C", Name: "Industri Pengolahan", Aliases: []string{"Manufaktur", "Manufacturing", "Pabrik", "FMCG"}},
+	{Code: "D", Name: "Pengadaan Listrik, Gas, Uap/Air Panas dan Udara Dingin", Aliases: []string{"Energi", "Listrik", "Energy"}},
+	{Code: "E", Name: "Pengelolaan Air, Pengelolaan Air Limbah, Pengelolaan dan Daur Ulang Sampah, dan Aktivitas Remediasi", Aliases: []string{"Pengelolaan Air", "Pengelolaan Sampah", "Limbah"}},
+	{Code: "F", Name: "Konstruksi", Aliases: []string{"Construction", "Kontraktor"}},
+	{Code: "G", Name: "Perdagangan Besar dan Eceran; Reparasi dan Perawatan Mobil dan Sepeda Motor", Aliases: []string{"Perdagangan", "Retail", "Ritel", "E-Commerce", "Otomotif"}},
+	{Code: "H", Name: "Pengangkutan dan Pergudangan", Aliases: []string{"Transportasi", "Logistik", "Logistics", "Ekspedisi"}},
+	{Code: "I", Name: "Penyediaan Akomodasi dan Penyediaan Makan Minum", Aliases: []string{"Perhotelan", "Hotel", "Restoran", "Kuliner", "F&B"}},
+	{Code: "J", Name: "Informasi dan Komunikasi", Aliases: []string{"Teknologi Informasi", "IT", "Software", "Telekomunikasi", "Media", "Startup"}},
+	{Code: "K", Name: "Aktivitas Keuangan dan Asuransi", Aliases: []string{"Perbankan", "Bank", "Keuangan", "Asuransi", "Finance", "Fintech"}},
+	{Code: "L", Name: "Real Estat", Aliases: []string{"Properti", "Real Estate"}},
+	{Code: "M", Name: "Aktivitas Profesional, Ilmiah dan Teknis", Aliases: []string{"Konsultan", "Consulting", "Riset", "Hukum", "Akuntansi"}},
+	{Code: "N", Name: "Aktivitas Penyewaan dan Sewa Guna Usaha Tanpa Hak Opsi, Ketenagakerjaan, Agen Perjalanan dan Penunjang Usaha Lainnya", Aliases: []string{"Outsourcing", "Agen Perjalanan", "Travel"}},
+	{Code: "O", Name: "Administrasi Pemerintahan, Pertahanan dan Jaminan Sosial Wajib", Aliases: []string{"Pemerintahan", "Instansi Pemerintah", "PNS", "ASN", "TNI", "Polri"}},
+	{Code: "P", Name: "Pendidikan", Aliases: []string{"Education", "Sekolah", "Universitas"}},
+	{Code: "Q", Name: "Aktivitas Kesehatan Manusia dan Aktivitas Sosial", Aliases: []string{"Kesehatan", "Rumah Sakit", "Healthcare", "Klinik"}},
+	{Code: "R", Name: "Kesenian, Hiburan dan Rekreasi", Aliases: []string{"Hiburan", "Entertainment", "Seni", "Olahraga"}},
+	{Code: "S", Name: "Aktivitas Jasa Lainnya", Aliases: []string{"Jasa", "Organisasi", "LSM", "NGO"}},
+	{Code: "T", Name: "Aktivitas Rumah Tangga sebagai Pemberi Kerja; Aktivitas yang Menghasilkan Barang dan Jasa oleh Rumah Tangga yang Digunakan untuk Memenuhi Kebutuhan Sendiri", Aliases: []string{"Rumah Tangga"}},
+	{Code: "U", Name: "Aktivitas Badan Internasional dan Badan Ekstra Internasional Lainnya", Aliases: []string{"Organisasi Internasional", "Badan Internasional"}},
+}
+
+// provinces memakai kode wilayah Kemendagri (38 provinsi). Kode "LN"
+// bukan kode resmi dan dipakai untuk pekerjaan di luar negeri.
+var provinces = []model.Term{
+	{Code: "11", Name: "Aceh", Aliases: []string{"Nanggroe Aceh Darussalam", "NAD"}},
+	{Code: "12", Name: "Sumatera Utara", Aliases: []string{"Sumut"}},
+	{Code: "13", Name: "Sumatera Barat", Aliases: []string{"Sumbar"}},
+	{Code: "14", Name: "Riau"},
+	{Code: "15", Name: "Jambi"},
+	{Code: "16", Name: "Sumatera Selatan", Aliases: []string{"Sumsel"}},
+	{Code: "17", Name: "Bengkulu"},
+	{Code: "18", Name: "Lampung"},
+	{Code: "19", Name: "Kepulauan Bangka Belitung", Aliases: []string{"Babel", "Bangka Belitung"}},
+	{Code: "21", Name: "Kepulauan Riau", Aliases: []string{"Kepri"}},
+	{Code: "31", Name: "DKI Jakarta", Aliases: []string{"Jakarta"}},
+	{Code: "32", Name: "Jawa Barat", Aliases: []string{"Jabar"}},
+	{Code: "33", Name: "Jawa Tengah", Aliases: []string{"Jateng"}},
+	{Code: "34", Name: "DI Yogyakarta", Aliases: []string{"Yogyakarta", "Jogja", "Jogjakarta", "DIY"}},
+	{Code: "35", Name: "Jawa Timur", Aliases: []string{"Jatim"}},
+	{Code: "36", Name: "Banten"},
+	{Code: "51", Name: "Bali"},
+	{Code: "52", Name: "Nusa Tenggara Barat", Aliases: []string{"NTB"}},
+	{Code: "53", Name: "Nusa Tenggara Timur", Aliases: []string{"NTT"}},
+	{Code: "61", Name: "Kalimantan Barat", Aliases: []string{"Kalbar"}},
+	{Code: "62", Name: "Kalimantan Tengah", Aliases: []string{"Kalteng"}},
+	{Code: "63", Name: "Kalimantan Selatan", Aliases: []string{"Kalsel"}},
+	{Code: "64", Name: "Kalimantan Timur", Aliases: []string{"Kaltim"}},
+	{Code: "65", Name: "Kalimantan Utara", Aliases: []string{"Kaltara"}},
+	{Code: "71", Name: "Sulawesi Utara", Aliases: []string{"Sulut"}},
+	{Code: "72", Name: "Sulawesi Tengah", Aliases: []string{"Sulteng"}},
+	{Code: "73", Name: "Sulawesi Selatan", Aliases: []string{"Sulsel"}},
+	{Code: "74", Name: "Sulawesi Tenggara", Aliases: []string{"Sultra"}},
+	{Code: "75", Name: "Gorontalo"},
+	{Code: "76", Name: "Sulawesi Barat", Aliases: []string{"Sulbar"}},
+	{Code: "81", Name: "Maluku"},
+	{Code: "82", Name: "Maluku Utara", Aliases: []string{"Malut"}},
+	{Code: "91", Name: "Papua"},
+	{Code: "92", Name: "Papua Barat"},
+	{Code: "93", Name: "Papua Selatan"},
+	{Code: "94", Name: "Papua Tengah"},
+	{Code: "95", Name: "Papua Pegunungan"},
+	{Code: "96", Name: "Papua Barat Daya"},
+	{Code: "LN", Name: "Luar Negeri", Aliases: []string{"Overseas", "Abroad"}},
+}
+
+// Seed mengisi kategori KBLI dan provinsi. Term yang sudah ada
+// diperbarui berdasarkan kode sehingga aman dijalankan ulang. Jurusan dan
+// kota diisi admin karena berbeda per kampus.
+func Seed(ctx context.Context, repo *repository.VocabularyRepository) error {
+	for typ, terms := range map[string][]model.Term{
+		model.VocabIndustri: kbliSections,
+		model.VocabProvinsi: provinces,
+	} {
+		for _, t := range terms {
+			t.Type = typ
+			if err := repo.Upsert(ctx, &t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,607 @@
+package main
+
+import (
+	"Mango/app/apperror"
+	"Mango/app/consistency"
+	"Mango/app/crypt"
+	"Mango/app/importer"
+	"Mango/app/mail"
+	model "Mango/app/Model"
+	"Mango/app/oidc/oidctest"
+	"Mango/app/repository"
+	"Mango/app/service"
+	"Mango/app/vocab"
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// runEnsureIndexes menangani `ensure-indexes`.
+func runEnsureIndexes(a *application, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := repository.EnsureIndexes(ctx, a.indexedRepositories()...); err != nil {
+		return err
+	}
+	fmt.Println("✅ Indexes are up to date")
+	return nil
+}
+
+// runCreateAdmin menangani `create-admin`. Password disimpan sebagai
+// hash bcrypt; lihat choosePassword.
+func runCreateAdmin(a *application, args []string) error {
+	fs := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	username := fs.String("username", "", "username admin (wajib)")
+	email := fs.String("email", "", "email admin (wajib)")
+	password := fs.String("password", "", "password; dibuat acak jika kosong")
+	passwordStdin := fs.Bool("password-stdin", false, "baca password dari stdin")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if *username == "" || *email == "" {
+		fs.Usage()
+		return errUsage
+	}
+
+	pw, generated, err := choosePassword(*password, *passwordStdin, os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	user := model.User{
+		ID:        primitive.NewObjectID(),
+		Username:  *username,
+		Email:     model.Secret(*email),
+		Password:  pw,
+		Role:      "admin",
+		CreatedAt: time.Now(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := a.userRepo.Create(ctx, &user); err != nil {
+		return describe(err)
+	}
+
+	fmt.Printf("✅ Admin %q created (id %s)\n", user.Username, user.ID.Hex())
+	if generated {
+		fmt.Printf("🔑 Password: %s\n", pw)
+	}
+	return nil
+}
+
+// runResetPassword menangani `reset-password`. Password disimpan sebagai
+// hash bcrypt; lihat choosePassword.
+func runResetPassword(a *application, args []string) error {
+	fs := flag.NewFlagSet("reset-password", flag.ContinueOnError)
+	username := fs.String("username", "", "username (wajib)")
+	password := fs.String("password", "", "password baru; dibuat acak jika kosong")
+	passwordStdin := fs.Bool("password-stdin", false, "baca password baru dari stdin")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if *username == "" {
+		fs.Usage()
+		return errUsage
+	}
+
+	pw, generated, err := choosePassword(*password, *passwordStdin, os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	user, err := a.userRepo.FindByUsername(ctx, *username)
+	if err != nil {
+		return describe(err)
+	}
+	if err := a.userRepo.UpdatePassword(ctx, user.ID, pw); err != nil {
+		return describe(err)
+	}
+
+	fmt.Printf("✅ Password for %q updated\n", user.Username)
+	if generated {
+		fmt.Printf("🔑 Password: %s\n", pw)
+	}
+	return nil
+}
+
+// runSeed menangani `seed`: mengisi beberapa alumni dan pekerjaan contoh.
+// Alumni di-upsert berdasarkan NIM sehingga aman dijalankan ulang.
+func runSeed(a *application, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	samples := []struct {
+		alumni    model.Alumni
+		pekerjaan model.Pekerjaan
+	}{
+		{
+			model.Alumni{NIM: "SEED000001", Nama: "Budi Santoso", Jurusan: "Teknik Informatika", Angkatan: 2016, Tahun_lulus: 2020, Email: "budi.santoso@example.com", No_telp: "081234567890", Alamat: "Surabaya"},
+			model.Pekerjaan{Nama_perusahaan: "Bank Mandiri", Posisi_jabatan: "Software Engineer", Bidang_Industri: "Perbankan", Lokasi_kerja: "Jakarta", Tanggal_Kerja: model.NewMonth(2020, time.August), Status: model.StatusCurrent},
+		},
+		{
+			model.Alumni{NIM: "SEED000002", Nama: "Siti Rahma", Jurusan: "Sistem Informasi", Angkatan: 2017, Tahun_lulus: 2021, Email: "siti.rahma@example.com", No_telp: "082198765432", Alamat: "Malang"},
+			model.Pekerjaan{Nama_perusahaan: "Telkom Indonesia", Posisi_jabatan: "Data Analyst", Bidang_Industri: "Telekomunikasi", Lokasi_kerja: "Bandung", Tanggal_Kerja: model.NewMonth(2021, time.October), Status: model.StatusCurrent},
+		},
+	}
+
+	for _, s := range samples {
+		alum := s.alumni
+		before, err := a.alumniRepo.UpsertByNIM(ctx, &alum, primitive.NilObjectID)
+		if err != nil {
+			return describe(err)
+		}
+		if before != nil {
+			fmt.Printf("• %s already seeded\n", alum.NIM)
+			continue
+		}
+
+		job := s.pekerjaan
+		job.AlumniID = alum.ID
+		if err := a.pekerjaanRepo.Create(ctx, &job); err != nil {
+			return describe(err)
+		}
+		fmt.Printf("✅ Seeded %s %s\n", alum.NIM, alum.Nama)
+	}
+	return nil
+}
+
+// runImport menangani `import alumni <file> [--dry-run] [--map Kolom=field]`.
+func runImport(a *application, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "validasi dan laporkan tanpa menyimpan")
+	var maps stringList
+	fs.Var(&maps, "map", "pemetaan kolom, contoh --map \"Nama Lengkap=nama\" (boleh berulang)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: import alumni <file.csv|file.xlsx> [--dry-run] [--map Kolom=field]")
+		fs.PrintDefaults()
+	}
+
+	if len(args) == 0 || args[0] != "alumni" {
+		fs.Usage()
+		return errUsage
+	}
+	// Flag boleh ditulis sebelum atau sesudah nama file
+	if err := fs.Parse(args[1:]); err != nil {
+		return errUsage
+	}
+	rest := fs.Args()
+	if len(rest) == 0 {
+		fs.Usage()
+		return errUsage
+	}
+	path := rest[0]
+	if err := fs.Parse(rest[1:]); err != nil || fs.NArg() > 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	format, err := importer.FormatFromFilename(path)
+	if err != nil {
+		return err
+	}
+	mapping, err := importer.ParseMapping(maps)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	im := importer.NewAlumniImporter(a.alumniRepo, vocab.NewResolver(a.vocabRepo, time.Minute))
+	report, err := im.Import(context.Background(), f, format, importer.Options{DryRun: *dryRun, Mapping: mapping})
+	if err != nil {
+		return err
+	}
+
+	for _, row := range report.Rows {
+		for _, fe := range row.Errors {
+			fmt.Printf("line %d (%s): %s %s\n", row.Line, row.NIM, fe.Field, fe.Message)
+		}
+	}
+	prefix := "✅"
+	if report.DryRun {
+		prefix = "[dry-run]"
+	}
+	fmt.Printf("%s Inserted %d, updated %d, failed %d\n", prefix, report.Inserted, report.Updated, report.Failed)
+	return nil
+}
+
+// stringList adalah flag.Value untuk flag yang boleh diulang.
+type stringList []string
+
+func (l *stringList) String() string     { return strings.Join(*l, ", ") }
+func (l *stringList) Set(v string) error { *l = append(*l, v); return nil }
+
+// choosePassword menentukan password create-admin dan reset-password:
+// dari flag, dari baris pertama stdin (--password-stdin, agar tidak
+// tersimpan di riwayat shell), atau acak jika keduanya kosong. Hanya
+// password acak yang boleh dicetak; generated melaporkan hal itu.
+func choosePassword(flagValue string, fromStdin bool, stdin io.Reader) (password string, generated bool, err error) {
+	switch {
+	case fromStdin && flagValue != "":
+		return "", false, errors.New("use either --password or --password-stdin")
+	case fromStdin:
+		line, err := bufio.NewReader(stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", false, fmt.Errorf("read password: %w", err)
+		}
+		password = strings.TrimRight(line, "\r\n")
+		if password == "" {
+			return "", false, errors.New("empty password on stdin")
+		}
+	case flagValue != "":
+		password = flagValue
+	default:
+		return randomPassword(), true, nil
+	}
+	if len(password) > model.MaxPasswordLength {
+		return "", false, fmt.Errorf("password must be at most %d bytes", model.MaxPasswordLength)
+	}
+	return password, false, nil
+}
+
+func randomPassword() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// describe menampilkan pesan error domain yang ramah untuk terminal.
+func describe(err error) error {
+	appErr := apperror.From(err)
+	if appErr.Err != nil {
+		return appErr.Err
+	}
+	return fmt.Errorf("%s", appErr.Message)
+}
+
+// runVocabMap menangani `vocab-map`: memetakan jurusan, bidang industri
+// dan lokasi kerja yang belum tertaut ke kosakata. Jalankan ulang setelah
+// menambah term atau alias untuk nilai yang belum cocok.
+func runVocabMap(a *application, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	reports, err := vocab.MapExisting(ctx, a.db)
+	if err != nil {
+		return err
+	}
+	for _, r := range reports {
+		fmt.Printf("✅ %s: %d mapped, %d unmatched\n", r.Field, r.Mapped, len(r.Unmatched))
+		for _, v := range r.Unmatched {
+			fmt.Printf("   • %s\n", v)
+		}
+	}
+	return nil
+}
+
+// runPurgeTrash menangani `purge-trash`: menghapus permanen alumni dan
+// pekerjaan yang sudah di tempat sampah lebih dari N hari.
+func runPurgeTrash(a *application, args []string) error {
+	fs := flag.NewFlagSet("purge-trash", flag.ContinueOnError)
+	days := fs.Int("days", envInt("TRASH_RETENTION_DAYS", 30), "umur minimal di tempat sampah (hari)")
+	dryRun := fs.Bool("dry-run", false, "hanya hitung, tidak menghapus")
+	if err := fs.Parse(args); err != nil || *days < 0 {
+		return errUsage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	trash := service.NewTrashService(a.alumniRepo, a.pekerjaanRepo, nil)
+	report, err := trash.Purge(ctx, time.Duration(*days)*24*time.Hour, *dryRun)
+	if err != nil {
+		return describe(err)
+	}
+
+	prefix := "✅ Purged"
+	if *dryRun {
+		prefix = "[dry-run] Would purge"
+	}
+	fmt.Printf("%s %d alumni, %d pekerjaan, %d users and %d uploads\n", prefix, report.Alumni, report.Pekerjaan, report.Users, report.Uploads)
+	return nil
+}
+
+// runConsistencyCheck menangani `consistency-check`: melaporkan pekerjaan,
+// tautan user, upload dan file yang yatim, serta username dan NIM ganda
+// yang mencegah index unik dibuat. Dengan --fix temuan yatim diperbaiki;
+// data ganda harus diselesaikan admin.
+func runConsistencyCheck(a *application, args []string) error {
+	fs := flag.NewFlagSet("consistency-check", flag.ContinueOnError)
+	fix := fs.Bool("fix", false, "perbaiki temuan (pekerjaan yatim dipindah ke tempat sampah)")
+	uploadDir := fs.String("uploads", "uploads", "direktori file upload")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	issues, err := consistency.Check(ctx, a.db, *uploadDir, *fix)
+	for _, issue := range issues {
+		status := ""
+		if issue.Fixed {
+			status = " (fixed)"
+		}
+		id := ""
+		if !issue.ID.IsZero() {
+			id = issue.ID.Hex() + " "
+		}
+		fmt.Printf("• %s: %s→ %s%s\n", issue.Kind, id, issue.Ref, status)
+	}
+	if err != nil {
+		return err
+	}
+
+	dup := 0
+	for _, issue := range issues {
+		if issue.Kind == consistency.DuplicateUser || issue.Kind == consistency.DuplicateNIM {
+			dup++
+		}
+	}
+	switch {
+	case len(issues) == 0:
+		fmt.Println("✅ No inconsistencies found")
+	case !*fix && len(issues) > dup:
+		fmt.Printf("⚠️  %d issue(s) found; run with --fix to repair\n", len(issues)-dup)
+	}
+	if dup > 0 {
+		fmt.Printf("⚠️  %d document(s) share a username or NIM; rename or merge them, then run `ensure-indexes`\n", dup)
+	}
+	return nil
+}
+
+// runKeyring menangani `keyring init`: membuat keyring untuk deployment
+// baru. Ditolak jika file sudah ada atau database sudah berisi data yang
+// dienkripsi dengan keyring lain; keyring yang hilang harus dipulihkan
+// dari backup.
+func runKeyring(a *application, args []string) error {
+	if len(args) != 1 || args[0] != "init" {
+		fmt.Fprintln(os.Stderr, "usage: keyring init")
+		return errUsage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	n, err := a.userRepo.Col.CountDocuments(ctx, bson.M{"email_bidx": bson.M{"$exists": true}}, options.Count().SetLimit(1))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return fmt.Errorf("database %s already has encrypted data; restore its keyring to %s instead", a.db.Name(), a.keyringPath)
+	}
+
+	keyring, err := crypt.Create(a.keyringPath)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("🔑 Created keyring %s (active key %s)\n", a.keyringPath, keyring.Active)
+	fmt.Println("⚠️  Back it up separately from the database; encrypted fields cannot be read without it")
+	return nil
+}
+
+// encryptedCollections adalah koleksi yang menyimpan nilai terenkripsi.
+var encryptedCollections = []string{"alumni", "Users", "pekerjaan_alumni", "alumni_revisions", "email_outbox"}
+
+// runRotateKeys menangani `rotate-keys`: menambah key baru ke keyring,
+// lalu membungkus ulang data key semua nilai terenkripsi dengan key itu.
+// Isi terenkripsinya tidak berubah. Jika terputus, jalankan ulang dengan
+// --rewrap-only.
+func runRotateKeys(a *application, args []string) error {
+	fs := flag.NewFlagSet("rotate-keys", flag.ContinueOnError)
+	rewrapOnly := fs.Bool("rewrap-only", false, "jangan buat key baru, hanya bungkus ulang ke key aktif")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+
+	keyring, err := crypt.Current()
+	if err != nil {
+		return err
+	}
+	if !*rewrapOnly {
+		id, err := keyring.Rotate()
+		if err != nil {
+			return err
+		}
+		if err := keyring.Save(a.keyringPath); err != nil {
+			return fmt.Errorf("save keyring: %w", err)
+		}
+		fmt.Printf("🔑 Active key is now %s\n", id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	for _, name := range encryptedCollections {
+		n, err := rewrapCollection(ctx, a.db.Collection(name))
+		if err != nil {
+			return fmt.Errorf("rewrap %s: %w", name, err)
+		}
+		fmt.Printf("• %s: %d document(s) rewrapped\n", name, n)
+	}
+	fmt.Printf("✅ All values use key %s; older keys can be removed from %s after backups taken with them have expired\n", keyring.Active, a.keyringPath)
+	return nil
+}
+
+// rewrapCollection membungkus ulang field terenkripsi setiap dokumen.
+// Field hanya ditimpa jika nilainya belum berubah sejak dibaca.
+func rewrapCollection(ctx context.Context, col *mongo.Collection) (int, error) {
+	cursor, err := col.Find(ctx, bson.M{})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	n := 0
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return n, err
+		}
+		filter := bson.M{"_id": doc["_id"]}
+		set := bson.M{}
+		for key, value := range doc {
+			field := bson.M{key: value}
+			changed, err := crypt.RewrapDoc(field)
+			if err != nil {
+				return n, fmt.Errorf("%v: %w", doc["_id"], err)
+			}
+			if changed {
+				filter[key] = cursor.Current.Lookup(key)
+				set[key] = field[key]
+			}
+		}
+		if len(set) == 0 {
+			continue
+		}
+		if _, err := col.UpdateOne(ctx, filter, bson.M{"$set": set}); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, cursor.Err()
+}
+
+// runSendOutbox menangani `send-outbox`: mengirim sekali semua email yang
+// antre di outbox, misalnya untuk mencoba konfigurasi SMTP ke SMTP sink
+// lokal. Dengan --retry-failed email yang gagal diantrekan ulang dulu.
+func runSendOutbox(a *application, args []string) error {
+	fs := flag.NewFlagSet("send-outbox", flag.ContinueOnError)
+	retry := fs.Bool("retry-failed", false, "antrekan ulang email berstatus failed sebelum mengirim")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+
+	cfg, err := mail.FromEnv()
+	if err != nil {
+		return err
+	}
+	if cfg.Host == "" {
+		return fmt.Errorf("SMTP_HOST is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	if *retry {
+		n, err := a.outboxRepo.Retry(ctx)
+		if err != nil {
+			return describe(err)
+		}
+		fmt.Printf("🔁 Requeued %d failed email(s)\n", n)
+	}
+
+	sent, failed, err := mail.NewDispatcher(a.outboxRepo, mail.NewSMTPSender(cfg)).Drain(ctx)
+	if err != nil {
+		return describe(err)
+	}
+	fmt.Printf("✅ Sent %d email(s), %d failed\n", sent, failed)
+	return nil
+}
+
+// runRotateJWTKeys menangani `rotate-jwt-keys`: membuat key tanda tangan
+// JWT baru sekarang (dipakai setelah JWT_KEY_PUBLISH_DELAY) dan menghapus
+// key yang token terakhirnya sudah kedaluwarsa.
+func runRotateJWTKeys(a *application, args []string) error {
+	fs := flag.NewFlagSet("rotate-jwt-keys", flag.ContinueOnError)
+	alg := fs.String("alg", jwtAlg(), "algoritma key baru: RS256 atau ES256")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+
+	keys, err := a.loadJWTKeys()
+	if err != nil {
+		return err
+	}
+	created, err := keys.Generate(*alg)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("🔑 Created %s key %s in %s; it signs tokens after %s\n", created.Alg, created.ID, a.jwtKeyDir, keys.PublishDelay)
+
+	_, removed, err := keys.Rotate(*alg, 0, service.AccessTokenTTL)
+	if err != nil {
+		return err
+	}
+	if len(removed) > 0 {
+		fmt.Printf("🗑️  Removed retired keys %v\n", removed)
+	}
+	return nil
+}
+
+// runMockOIDC menangani `mock-oidc`: identity provider OIDC tiruan untuk
+// menguji SSO secara lokal. Arahkan OIDC_ISSUER ke --issuer dan
+// OIDC_CLIENT_ID ke --client-id. User contoh cocok dengan data `seed`
+// (lewat NIM dan email); --users memuat daftar user dari file JSON.
+func runMockOIDC(a *application, args []string) error {
+	fs := flag.NewFlagSet("mock-oidc", flag.ContinueOnError)
+	addr := fs.String("addr", ":9000", "alamat listen")
+	issuer := fs.String("issuer", "", "issuer URL (default http://localhost<addr>)")
+	clientID := fs.String("client-id", "mango", "client_id yang diterima")
+	clientSecret := fs.String("client-secret", "", "client secret; kosong untuk client publik")
+	usersFile := fs.String("users", "", "file JSON berisi daftar user (sub, email, email_verified, name, preferred_username, nim, groups)")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if *issuer == "" {
+		*issuer = "http://localhost" + *addr
+	}
+
+	users := []oidctest.Identity{
+		{Subject: "mock-admin", Email: "admin@example.com", EmailVerified: true, Name: "Admin Fakultas", PreferredUsername: "admin.fakultas", Groups: []string{"mango-admin"}},
+		{Subject: "mock-budi", Email: "budi.santoso@example.com", EmailVerified: true, Name: "Budi Santoso", PreferredUsername: "budi.santoso", NIM: "SEED000001", Groups: []string{"alumni"}},
+		{Subject: "mock-siti", Email: "siti.rahma@example.com", EmailVerified: true, Name: "Siti Rahma", PreferredUsername: "siti.rahma", Groups: []string{"alumni"}},
+	}
+	if *usersFile != "" {
+		data, err := os.ReadFile(*usersFile)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &users); err != nil {
+			return fmt.Errorf("%s: %w", *usersFile, err)
+		}
+	}
+
+	keyDir, err := os.MkdirTemp("", "mock-oidc-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(keyDir)
+
+	provider, err := oidctest.New(*issuer, *clientID, *clientSecret, keyDir)
+	if err != nil {
+		return err
+	}
+	provider.Users = users
+
+	fmt.Printf("🔑 Mock OIDC provider at %s (client_id %q)\n", *issuer, *clientID)
+	fmt.Printf("   OIDC_ISSUER=%s OIDC_CLIENT_ID=%s OIDC_GROUP_ROLES=mango-admin=admin\n", *issuer, *clientID)
+	return http.ListenAndServe(*addr, provider)
+}
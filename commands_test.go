@@ -0,0 +1,49 @@
+package main
+
+import (
+	model "Mango/app/Model"
+	"strings"
+	"testing"
+)
+
+func TestChoosePassword(t *testing.T) {
+	tests := []struct {
+		name      string
+		flag      string
+		fromStdin bool
+		stdin     string
+		want      string // kosong untuk password acak
+		generated bool
+		ok        bool
+	}{
+		{"flag", "rahasia123", false, "", "rahasia123", false, true},
+		{"stdin", "", true, "rahasia123\n", "rahasia123", false, true},
+		{"stdin without newline", "", true, "rahasia123", "rahasia123", false, true},
+		{"stdin crlf", "", true, "rahasia123\r\nignored\n", "rahasia123", false, true},
+		{"generated", "", false, "", "", true, true},
+		{"both", "rahasia123", true, "lain\n", "", false, false},
+		{"empty stdin", "", true, "\n", "", false, false},
+		{"too long", strings.Repeat("a", model.MaxPasswordLength+1), false, "", "", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, generated, err := choosePassword(tt.flag, tt.fromStdin, strings.NewReader(tt.stdin))
+			if ok := err == nil; ok != tt.ok {
+				t.Fatalf("err = %v, want ok %v", err, tt.ok)
+			}
+			if !tt.ok {
+				return
+			}
+			if generated != tt.generated {
+				t.Errorf("generated = %v, want %v", generated, tt.generated)
+			}
+			if tt.generated {
+				if len(got) < 16 {
+					t.Errorf("generated password %q is too short", got)
+				}
+			} else if got != tt.want {
+				t.Errorf("password = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -15,113 +15,82 @@
 package main
 
 import (
-	"Mango/app/repository"
-	"Mango/app/service"
-	"Mango/middleware"
-	"Mango/routes"
-	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
-	"time"
+	"sort"
 
 	_ "Mango/docs"
-
-	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func main() {
-	// 🔹 Load .env
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("❌ Error loading .env file")
-	}
+// command adalah satu subcommand dari binary.
+type command struct {
+	usage string
+	run   func(a *application, args []string) error
+}
 
-	// 🔹 Ambil variabel dari .env
-	mongoURI := os.Getenv("MONGO_URI")
-	dbName := os.Getenv("MONGO_DB")
-	port := os.Getenv("SERVER_PORT")
+var errUsage = errors.New("invalid usage")
+
+var commands = map[string]command{
+	"serve":             {"serve                                  jalankan HTTP server (default)", runServe},
+	"migrate":           {"migrate [up|status] [--dry-run]        jalankan migration skema", runMigrate},
+	"ensure-indexes":    {"ensure-indexes                         buat index semua koleksi", runEnsureIndexes},
+	"create-admin":      {"create-admin --username U --email E    buat user dengan role admin", runCreateAdmin},
+	"reset-password":    {"reset-password --username U            ganti password user", runResetPassword},
+	"seed":              {"seed                                   isi data contoh untuk development", runSeed},
+	"import":            {"import alumni <file> [--dry-run]       impor alumni dari CSV/XLSX", runImport},
+	"vocab-map":         {"vocab-map                              petakan teks bebas ke kosakata referensi", runVocabMap},
+	"purge-trash":       {"purge-trash [--days N] [--dry-run]     hapus permanen isi tempat sampah", runPurgeTrash},
+	"consistency-check": {"consistency-check [--fix]              laporkan data yatim dan username/NIM ganda", runConsistencyCheck},
+	"rotate-keys":       {"rotate-keys [--rewrap-only]            rotasi key enkripsi field dan bungkus ulang data", runRotateKeys},
+	"rotate-jwt-keys":   {"rotate-jwt-keys [--alg RS256|ES256]    buat key tanda tangan JWT baru", runRotateJWTKeys},
+	"send-outbox":       {"send-outbox [--retry-failed]           kirim email yang antre di outbox", runSendOutbox},
+	"mock-oidc":         {"mock-oidc [--addr :9000]               identity provider OIDC tiruan untuk uji SSO", runMockOIDC},
+	"keyring":           {"keyring init                           buat keyring enkripsi field untuk deployment baru", runKeyring},
+}
 
-	if mongoURI == "" || dbName == "" {
-		log.Fatal("❌ MONGO_URI or MONGO_DB not found in .env")
+func main() {
+	name, args := "serve", []string(nil)
+	if len(os.Args) > 1 {
+		name, args = os.Args[1], os.Args[2:]
 	}
 
-	// 🔹 Koneksi ke MongoDB
-	clientOptions := options.Client().ApplyURI(mongoURI)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	client, err := mongo.Connect(ctx, clientOptions)
-	if err != nil {
-		log.Fatal("❌ MongoDB connection error:", err)
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage()
+		return
 	}
 
-	// 🔹 Tes koneksi
-	if err := client.Ping(ctx, nil); err != nil {
-		log.Fatal("❌ Cannot connect to MongoDB:", err)
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		printUsage()
+		os.Exit(2)
 	}
-	fmt.Println("✅ Connected to MongoDB!")
-
-	// 🔹 Inisialisasi database dan repository
-	db := client.Database(dbName)
-	userRepo := repository.NewUserRepository(db)
-	alumniRepo := repository.NewAlumniRepository(db)
-	pekerjaanRepo := repository.NewPekerjaanRepository(db)
-	uploadRepo := repository.NewUploadRepository(db)
-
-	// 🔹 Inisialisasi service
-	authService := service.NewAuthService(userRepo)
-	alumniService := service.NewAlumniService(alumniRepo)
-	pekerjaanService := service.NewPekerjaanService(pekerjaanRepo)
-	// FileService := service.FileService(FileRepo)
-	uploadService := service.NewFileservice(uploadRepo)
 
-	// 🔹 Setup router Gin
-	router := gin.Default()
-	router.Use(gin.Logger(), gin.Recovery())
-
-	// =============================
-	// 🔹 EndPoint Swagger
-	// =============================
-
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	fmt.Println("📘 Swagger UI available at: http://localhost:" + port + "/swagger/index.html")
-
-	// =============================
-	// 🔹 ROUTING SECTION
-	// =============================
-
-	// 1️⃣ Public routes (tanpa middleware)
-	public := router.Group("/auth")
-	{
-		routes.AuthRoutes(public, authService)
-	}
-
-	// 2️⃣ Protected routes (dengan middleware)
-	api := router.Group("/api")
-	api.Use(middleware.AuthMiddleware(userRepo))
-	{
-		routes.AlumniRoutes(api, alumniService)
-		routes.PekerjaanRoutes(api, pekerjaanService)
+	app := bootstrap(name != "keyring")
+	err := cmd.run(app, args)
+	app.close()
 
+	if err != nil {
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		log.Fatalf("❌ %s: %v", name, err)
 	}
+}
 
-	// buat router untuk fitur uploads
-	auth := router.Group("/uploads")
-	auth.Use(middleware.AuthMiddleware(userRepo))
-	{
-		routes.FileRoutes(auth, uploadService, userRepo)
+func printUsage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	// 🔹 Jalankan server
-	if port == "" {
-		port = "3000"
+	fmt.Println("Usage: Mango <command> [arguments]")
+	fmt.Println()
+	fmt.Println("Commands:")
+	for _, name := range names {
+		fmt.Println("  " + commands[name].usage)
 	}
-	fmt.Printf("🚀 Server running on port %s\n", port)
-	router.Run(":" + port)
-
 }
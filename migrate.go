@@ -0,0 +1,90 @@
+package main
+
+import (
+	"Mango/app/migration"
+	"context"
+	"flag"
+	"fmt"
+	"time"
+)
+
+// runMigrate menangani `migrate [up|status] [--dry-run]`.
+func runMigrate(a *application, args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "tampilkan migration yang akan dijalankan tanpa menerapkannya")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: migrate [up|status] [--dry-run]")
+		fs.PrintDefaults()
+	}
+
+	action := "up"
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		action, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	runner, err := migration.NewRunner(a.db, migration.All())
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch action {
+	case "status":
+		statuses, err := runner.Status(ctx)
+		if err != nil {
+			return fmt.Errorf("read migration status: %w", err)
+		}
+		for _, st := range statuses {
+			state := "pending"
+			if st.Applied != nil {
+				state = "applied " + st.Applied.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d  %-45s %s\n", st.Migration.Version, st.Migration.Name, state)
+		}
+		return nil
+
+	case "up":
+		if *dryRun {
+			return migrateDryRun(ctx, a, runner)
+		}
+
+		done, err := runner.Up(ctx, func(format string, args ...any) {
+			fmt.Printf(format+"\n", args...)
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ Applied %d migration(s)\n", len(done))
+		return nil
+
+	default:
+		fs.Usage()
+		return errUsage
+	}
+}
+
+func migrateDryRun(ctx context.Context, a *application, runner *migration.Runner) error {
+	pending, err := runner.Pending(ctx)
+	if err != nil {
+		return fmt.Errorf("read migration status: %w", err)
+	}
+	if len(pending) == 0 {
+		fmt.Println("✅ No pending migrations")
+		return nil
+	}
+	for _, m := range pending {
+		line := fmt.Sprintf("%04d  %s", m.Version, m.Name)
+		if m.Affected != nil {
+			n, err := m.Affected(ctx, a.db)
+			if err != nil {
+				return err
+			}
+			line += fmt.Sprintf(" (%d documents)", n)
+		}
+		fmt.Println("[dry-run] " + line)
+	}
+	return nil
+}
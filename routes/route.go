@@ -10,9 +10,49 @@ import (
 
 //    r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-func AuthRoutes(r *gin.RouterGroup, authService *service.AuthService) {
-	r.POST("/register", authService.Register)
-	r.POST("/login", authService.Login)
+// AuthRoutes memasang endpoint publik auth. loginLimit, registerLimit dan
+// emailLimit adalah middleware rate limit per IP; emailLimit membatasi
+// endpoint yang mengirim email.
+func AuthRoutes(r *gin.RouterGroup, authService *service.AuthService, loginLimit, registerLimit, emailLimit gin.HandlerFunc) {
+	r.POST("/register", registerLimit, authService.Register)
+	r.POST("/login", loginLimit, authService.Login)
+	r.POST("/verify-email", loginLimit, authService.VerifyEmail)
+	r.POST("/forgot-password", emailLimit, authService.ForgotPassword)
+	r.POST("/reset-password", loginLimit, authService.ResetPassword)
+
+	// 🔹 Langkah kedua login untuk user dengan TOTP
+	r.POST("/mfa/verify", loginLimit, authService.VerifyMFA)
+	r.POST("/mfa/enroll", loginLimit, authService.ChallengeEnrollMFA)
+	r.POST("/mfa/confirm", loginLimit, authService.ChallengeConfirmMFA)
+
+	// 🔹 Single sign-on lewat identity provider universitas (OIDC)
+	r.GET("/oidc/login", loginLimit, authService.SSOLogin)
+	r.GET("/oidc/callback", loginLimit, authService.SSOCallback)
+}
+
+// WellKnownRoutes memasang endpoint /.well-known publik.
+func WellKnownRoutes(r *gin.RouterGroup, authService *service.AuthService) {
+	// 🔹 Public key JWT untuk aplikasi lain (portal fakultas, career center)
+	r.GET("/jwks.json", authService.JWKS)
+}
+
+func UserRoutes(r *gin.RouterGroup, authService *service.AuthService, emailLimit gin.HandlerFunc) {
+	// 🔹 User yang login bisa meminta ulang email verifikasi
+	r.POST("/me/verify-email", emailLimit, authService.ResendVerification)
+
+	// 🔹 Setiap user bisa mengaktifkan TOTP untuk akunnya sendiri
+	mfa := r.Group("/me/mfa")
+	{
+		mfa.GET("", authService.MFAStatus)
+		mfa.DELETE("", authService.DisableMFA)
+		mfa.POST("/enroll", authService.EnrollMFA)
+		mfa.POST("/confirm", authService.ConfirmMFA)
+		mfa.POST("/recovery-codes", authService.RegenerateRecoveryCodes)
+	}
+
+	// 🔹 Membuka akun yang terkunci dan mereset TOTP hanya untuk admin
+	r.POST("/users/:id/unlock", middleware.RoleMiddleware("admin"), authService.UnlockUser)
+	r.POST("/users/:id/mfa/reset", middleware.RoleMiddleware("admin"), authService.ResetUserMFA)
 }
 
 func AlumniRoutes(r *gin.RouterGroup, alumniService *service.AlumniService) {
@@ -20,14 +60,115 @@ func AlumniRoutes(r *gin.RouterGroup, alumniService *service.AlumniService) {
 	{
 		// 🔹 Semua user yang login bisa melihat daftar alumni
 		alumni.GET("/", alumniService.GetAllAlumni)
+		alumni.GET("/search", alumniService.SearchAlumni)
 
 		// 🔹 Hanya admin yang bisa menambah, update, dan hapus data alumni
 		alumni.POST("/", middleware.RoleMiddleware("admin"), alumniService.CreateAlumni)
 		alumni.PUT("/:id", middleware.RoleMiddleware("admin"), alumniService.UpdateAlumni)
 		alumni.DELETE("/:id", middleware.RoleMiddleware("admin"), alumniService.DeleteAlumni)
+		alumni.POST("/:id/revert", middleware.RoleMiddleware("admin"), alumniService.RevertAlumni)
 
 		// 🔹 Detail alumni bisa dilihat siapa saja yang login
 		alumni.GET("/:id", alumniService.GetAlumniByID)
+		alumni.GET("/:id/history", middleware.RoleMiddleware("admin"), alumniService.GetAlumniHistory)
+	}
+}
+
+func ImportRoutes(r *gin.RouterGroup, importService *service.ImportService) {
+	// 🔹 Impor daftar wisudawan hanya untuk admin
+	r.POST("/alumni/import", middleware.RoleMiddleware("admin"), importService.ImportAlumni)
+}
+
+func ExportRoutes(r *gin.RouterGroup, exportService *service.ExportService) {
+	export := r.Group("/export")
+	export.Use(middleware.RoleMiddleware("admin"))
+	{
+		// 🔹 Export untuk laporan akreditasi (hanya admin)
+		export.GET("/alumni", exportService.ExportAlumni)
+		export.GET("/pekerjaan", exportService.ExportPekerjaan)
+		export.GET("/alumni-pekerjaan", exportService.ExportAlumniPekerjaan)
+	}
+}
+
+func StatsRoutes(r *gin.RouterGroup, statsService *service.StatsService) {
+	stats := r.Group("/stats")
+	{
+		// 🔹 Statistik agregat tracer study bisa dilihat semua user yang login
+		stats.GET("/employment", statsService.EmploymentRate)
+		stats.GET("/industri", statsService.IndustryDistribution)
+		stats.GET("/lokasi", statsService.LocationDistribution)
+		stats.GET("/waiting-time", statsService.WaitingTime)
+		stats.GET("/salary", statsService.SalaryDistribution)
+		stats.GET("/top-employers", statsService.TopEmployers)
+	}
+}
+
+func TrashRoutes(r *gin.RouterGroup, trashService *service.TrashService) {
+	// 🔹 Tempat sampah hanya untuk admin
+	trash := r.Group("/trash", middleware.RoleMiddleware("admin"))
+	{
+		trash.GET("/alumni", trashService.ListAlumni)
+		trash.GET("/pekerjaan", trashService.ListPekerjaan)
+		trash.POST("/alumni/:id/restore", trashService.RestoreAlumni)
+		trash.POST("/pekerjaan/:id/restore", trashService.RestorePekerjaan)
+	}
+}
+
+func PrivacyRoutes(r *gin.RouterGroup, privacyService *service.PrivacyService) {
+	// 🔹 Setiap user bisa mengunduh, mengatur dan meminta penghapusan datanya sendiri
+	me := r.Group("/me")
+	{
+		me.GET("/data-export", privacyService.ExportMyData)
+		me.GET("/privacy", privacyService.GetMyPrivacy)
+		me.PUT("/privacy", privacyService.UpdateMyPrivacy)
+		me.GET("/erasure-requests", privacyService.MyErasureRequests)
+		me.POST("/erasure-requests", privacyService.RequestErasure)
+	}
+
+	// 🔹 Admin memproses permintaan penghapusan, pengaturan privasi alumni
+	// dan tautan akun ke alumni
+	privacy := r.Group("/privacy", middleware.RoleMiddleware("admin"))
+	{
+		privacy.PUT("/users/:id/alumni", privacyService.LinkUserAlumni)
+		privacy.GET("/alumni/:id/export", privacyService.ExportAlumniData)
+		privacy.PUT("/alumni/:id/settings", privacyService.UpdateAlumniPrivacy)
+		privacy.GET("/erasure-requests", privacyService.ListErasureRequests)
+		privacy.POST("/erasure-requests/:id/approve", privacyService.ApproveErasure)
+		privacy.POST("/erasure-requests/:id/reject", privacyService.RejectErasure)
+	}
+}
+
+func AuditRoutes(r *gin.RouterGroup, auditService *service.AuditService) {
+	// 🔹 Audit log hanya untuk admin
+	r.GET("/audit", middleware.RoleMiddleware("admin"), auditService.ListAudit)
+}
+
+func CompanyRoutes(r *gin.RouterGroup, companyService *service.CompanyService) {
+	companies := r.Group("/companies")
+	{
+		// 🔹 Semua user bisa mencari perusahaan (dipakai saat mengisi pekerjaan)
+		companies.GET("/", companyService.ListCompanies)
+		companies.GET("/suggest", companyService.SuggestCompanies)
+		companies.GET("/:id", companyService.GetCompany)
+
+		// 🔹 Hanya admin yang mengelola direktori perusahaan
+		companies.POST("/", middleware.RoleMiddleware("admin"), companyService.CreateCompany)
+		companies.PUT("/:id", middleware.RoleMiddleware("admin"), companyService.UpdateCompany)
+		companies.DELETE("/:id", middleware.RoleMiddleware("admin"), companyService.DeleteCompany)
+		companies.POST("/:id/merge", middleware.RoleMiddleware("admin"), companyService.MergeCompanies)
+	}
+}
+
+func VocabularyRoutes(r *gin.RouterGroup, vocabularyService *service.VocabularyService) {
+	vocabularies := r.Group("/vocabularies")
+	{
+		// 🔹 Semua user bisa membaca kosakata untuk dropdown
+		vocabularies.GET("/:type", vocabularyService.ListTerms)
+
+		// 🔹 Hanya admin yang mengelola kosakata
+		vocabularies.POST("/:type", middleware.RoleMiddleware("admin"), vocabularyService.CreateTerm)
+		vocabularies.PUT("/:type/:id", middleware.RoleMiddleware("admin"), vocabularyService.UpdateTerm)
+		vocabularies.DELETE("/:type/:id", middleware.RoleMiddleware("admin"), vocabularyService.DeleteTerm)
 	}
 }
 
@@ -45,6 +186,9 @@ func PekerjaanRoutes(r *gin.RouterGroup, pekerjaanService *service.PekerjaanServ
 		pekerjaan.PUT("/:id", middleware.RoleMiddleware("admin"), pekerjaanService.UpdatePekerjaan)
 		pekerjaan.DELETE("/:id", middleware.RoleMiddleware("admin"), pekerjaanService.DeletePekerjaan)
 	}
+
+	// 🔹 Pekerjaan saat ini dihitung dari riwayat pekerjaan alumni
+	r.GET("/alumni/:id/current-job", pekerjaanService.GetCurrentJob)
 }
 
 func FileRoutes(r *gin.RouterGroup, Fileservice *service.FileService, userRepo *repository.UserRepository) {
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"Mango/app/audit"
+	"Mango/app/importer"
+	"Mango/app/jwtkeys"
+	"Mango/app/mail"
+	model "Mango/app/Model"
+	"Mango/app/migration"
+	"Mango/app/oidc"
+	"Mango/app/ratelimit"
+	"Mango/app/repository"
+	"Mango/app/salary"
+	"Mango/app/service"
+	"Mango/app/vocab"
+	"Mango/middleware"
+	"Mango/routes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+// runServe menjalankan HTTP server (subcommand default).
+func runServe(a *application, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if runner, err := migration.NewRunner(a.db, migration.All()); err == nil {
+		if pending, err := runner.Pending(ctx); err == nil && len(pending) > 0 {
+			log.Printf("⚠️  %d pending migration(s); run `migrate up`", len(pending))
+		}
+	}
+
+	// 🔹 Pastikan index tersedia (set MONGO_ENSURE_INDEXES=false untuk melewati).
+	// Index yang gagal (biasanya karena data ganda) dilaporkan tanpa menghentikan server.
+	if os.Getenv("MONGO_ENSURE_INDEXES") != "false" {
+		if err := repository.EnsureIndexes(ctx, a.indexedRepositories()...); err != nil {
+			log.Printf("⚠️  Some indexes could not be built; run `consistency-check` to find duplicates, then `ensure-indexes`:\n%v", err)
+		}
+	}
+
+	salaryCfg, err := salary.FromEnv()
+	if err != nil {
+		return err
+	}
+
+	// 🔹 Kosakata referensi di-cache sebentar; perubahan lewat API langsung membersihkan cache
+	resolver := vocab.NewResolver(a.vocabRepo, time.Minute)
+
+	// 🔹 Policy default untuk data yang bergantung saat alumni dihapus
+	deletePolicy := os.Getenv("ALUMNI_DELETE_POLICY")
+	if deletePolicy == "" {
+		deletePolicy = model.DeleteCascade
+	}
+	if !slices.Contains(model.DeletePolicies, deletePolicy) {
+		return fmt.Errorf("invalid ALUMNI_DELETE_POLICY %q, want one of %v", deletePolicy, model.DeletePolicies)
+	}
+
+	// 🔹 Key tanda tangan JWT; dibuat otomatis jika direktori masih kosong
+	if !slices.Contains(jwtkeys.Algorithms, jwtAlg()) {
+		return fmt.Errorf("invalid JWT_ALG %q, want one of %v", jwtAlg(), jwtkeys.Algorithms)
+	}
+	jwtKeys, err := a.loadJWTKeys()
+	if err != nil {
+		return err
+	}
+	if len(jwtKeys.Keys()) == 0 {
+		k, err := jwtKeys.Generate(jwtAlg())
+		if err != nil {
+			return fmt.Errorf("generate JWT key: %w", err)
+		}
+		log.Printf("⚠️  Created JWT signing key %s (%s) in %s", k.ID, k.Alg, a.jwtKeyDir)
+	}
+	switch {
+	case len(jwtKeys.LegacySecret) > 0 && time.Now().Before(jwtKeys.LegacyUntil):
+		log.Printf("⚠️  HS256 tokens without kid are accepted until %s; unset JWT_SECRET and JWT_LEGACY_UNTIL afterwards", jwtKeys.LegacyUntil.Format(time.RFC3339))
+	case len(jwtKeys.LegacySecret) > 0:
+		log.Printf("⚠️  JWT_LEGACY_UNTIL has passed; HS256 tokens are rejected, unset JWT_SECRET and JWT_LEGACY_UNTIL")
+	case os.Getenv("JWT_SECRET") != "":
+		log.Printf("⚠️  JWT_SECRET is ignored without JWT_LEGACY_UNTIL; HS256 tokens are rejected")
+	}
+	jwtkeys.Use(jwtKeys)
+	if every := envDuration("JWT_ROTATE_EVERY", 30*24*time.Hour); every > 0 {
+		go rotateJWTKeys(jwtKeys, every, envDuration("JWT_KEY_CHECK_INTERVAL", 10*time.Minute))
+	}
+
+	// 🔹 Setiap perubahan data dicatat ke audit_log
+	recorder := audit.NewRecorder(a.auditRepo)
+
+	// 🔹 Rate limit endpoint auth; pakai RATE_LIMIT_STORE=mongo jika ada beberapa replica
+	var limiter ratelimit.Store = ratelimit.NewMemoryStore()
+	switch store := os.Getenv("RATE_LIMIT_STORE"); store {
+	case "", "memory":
+	case "mongo":
+		limiter = a.rateLimits
+	default:
+		return fmt.Errorf("invalid RATE_LIMIT_STORE %q, want memory or mongo", store)
+	}
+	// 🔹 Role yang wajib TOTP (MFA_REQUIRED_ROLES dipisah koma, "none" untuk tidak ada)
+	mfaRoles := []string{"admin"}
+	if v := os.Getenv("MFA_REQUIRED_ROLES"); v == "none" {
+		mfaRoles = nil
+	} else if v != "" {
+		mfaRoles = strings.Split(v, ",")
+	}
+	mfaIssuer := os.Getenv("MFA_ISSUER")
+	if mfaIssuer == "" {
+		mfaIssuer = "Mango"
+	}
+	protection := service.LoginProtection{
+		Store:   limiter,
+		PerUser: envLimit("RATE_LIMIT_LOGIN_USER", "10/15m"),
+		Lockout: model.LockoutPolicy{
+			Threshold: envInt("LOCKOUT_THRESHOLD", 5),
+			Base:      envDuration("LOCKOUT_BASE", time.Minute),
+			Max:       envDuration("LOCKOUT_MAX", 24*time.Hour),
+		},
+		MFA: service.MFAPolicy{RequiredRoles: mfaRoles, Issuer: mfaIssuer},
+	}
+
+	port := a.port
+	if port == "" {
+		port = "3000"
+	}
+
+	// 🔹 Email verifikasi dan reset password diantrekan di email_outbox lalu dikirim lewat SMTP
+	smtpCfg, err := mail.FromEnv()
+	if err != nil {
+		return err
+	}
+	locale := os.Getenv("MAIL_DEFAULT_LOCALE")
+	if locale != "" && !slices.Contains(mail.Locales, locale) {
+		return fmt.Errorf("invalid MAIL_DEFAULT_LOCALE %q, want one of %v", locale, mail.Locales)
+	}
+	baseURL := os.Getenv("APP_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:" + port
+	}
+	accountMail := service.AccountMail{
+		Tokens:    a.tokenRepo,
+		Outbox:    mail.NewOutbox(a.outboxRepo, locale),
+		BaseURL:   baseURL,
+		VerifyTTL: envDuration("VERIFY_EMAIL_TTL", 48*time.Hour),
+		ResetTTL:  envDuration("PASSWORD_RESET_TTL", time.Hour),
+	}
+
+	// 🔹 Single sign-on OIDC (mati jika OIDC_ISSUER kosong; login lokal tetap tersedia)
+	oidcCfg, err := oidc.FromEnv("http://localhost:" + port + "/auth/oidc/callback")
+	if err != nil {
+		return err
+	}
+	groupRoles, err := parseGroupRoles(os.Getenv("OIDC_GROUP_ROLES"))
+	if err != nil {
+		return err
+	}
+	sso := service.SingleSignOn{
+		Alumni:      a.alumniRepo,
+		NIMClaim:    "nim",
+		GroupsClaim: "groups",
+		GroupRoles:  groupRoles,
+		DefaultRole: "user",
+		FrontendURL: os.Getenv("OIDC_FRONTEND_URL"),
+	}
+	if v := os.Getenv("OIDC_NIM_CLAIM"); v != "" {
+		sso.NIMClaim = v
+	}
+	if v := os.Getenv("OIDC_GROUPS_CLAIM"); v != "" {
+		sso.GroupsClaim = v
+	}
+	if oidcCfg.Enabled() {
+		sso.Provider = oidc.NewProvider(oidcCfg)
+		log.Printf("🔑 Single sign-on with %s, callback %s", oidcCfg.Issuer, oidcCfg.RedirectURL)
+	}
+
+	// 🔹 Inisialisasi service
+	authService := service.NewAuthService(a.userRepo, recorder, protection, accountMail, sso)
+	alumniService := service.NewAlumniService(a.alumniRepo, resolver, deletePolicy, recorder)
+	pekerjaanService := service.NewPekerjaanService(a.pekerjaanRepo, a.companyRepo, resolver, salaryCfg, recorder)
+	vocabularyService := service.NewVocabularyService(a.vocabRepo, resolver, recorder)
+	trashService := service.NewTrashService(a.alumniRepo, a.pekerjaanRepo, recorder)
+	companyService := service.NewCompanyService(a.companyRepo, a.pekerjaanRepo, recorder)
+	uploadService := service.NewFileservice(a.uploadRepo, recorder)
+	importService := service.NewImportService(importer.NewAlumniImporter(a.alumniRepo, resolver), recorder)
+	auditService := service.NewAuditService(a.auditRepo)
+	privacyService := service.NewPrivacyService(a.alumniRepo, a.userRepo, a.erasureRepo, recorder)
+	exportService := service.NewExportService(a.alumniRepo, a.pekerjaanRepo)
+	statsService := service.NewStatsService(repository.NewStatsRepository(a.db), envDuration("STATS_CACHE_TTL", 5*time.Minute), salaryCfg)
+
+	// 🔹 Kosongkan tempat sampah berkala (TRASH_RETENTION_DAYS=0 untuk mematikan)
+	if days := envInt("TRASH_RETENTION_DAYS", 30); days > 0 {
+		go purgeTrash(trashService, time.Duration(days)*24*time.Hour, envDuration("TRASH_PURGE_INTERVAL", 24*time.Hour))
+	}
+
+	// 🔹 Hapus audit log lama berkala (AUDIT_RETENTION_DAYS=0 untuk menyimpan selamanya)
+	if days := envInt("AUDIT_RETENTION_DAYS", 365); days > 0 {
+		go purgeAudit(a.auditRepo, time.Duration(days)*24*time.Hour, envDuration("AUDIT_PURGE_INTERVAL", 24*time.Hour))
+	}
+
+	// 🔹 Kirim email di outbox berkala (tanpa SMTP_HOST email hanya menunggu di outbox)
+	if smtpCfg.Host == "" {
+		log.Printf("⚠️  SMTP_HOST is not set; emails stay in the outbox until it is configured")
+	} else {
+		go sendOutbox(mail.NewDispatcher(a.outboxRepo, mail.NewSMTPSender(smtpCfg)), envDuration("MAIL_POLL_INTERVAL", 10*time.Second))
+	}
+
+	// 🔹 Setup router Gin
+	router := gin.New()
+	router.HandleMethodNotAllowed = true
+	router.Use(gin.Logger(), middleware.Recovery(), middleware.RequestID(), middleware.ErrorHandler())
+	router.NoRoute(middleware.NoRoute())
+	router.NoMethod(middleware.NoMethod())
+
+	// 🔹 IP client hanya dibaca dari X-Forwarded-For jika dikirim proxy tepercaya (TRUSTED_PROXIES, dipisah koma)
+	var trusted []string
+	if v := os.Getenv("TRUSTED_PROXIES"); v != "" {
+		trusted = strings.Split(v, ",")
+	}
+	if err := router.SetTrustedProxies(trusted); err != nil {
+		return fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+
+	// =============================
+	// 🔹 EndPoint Swagger
+	// =============================
+
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	fmt.Println("📘 Swagger UI available at: http://localhost:" + port + "/swagger/index.html")
+
+	// =============================
+	// 🔹 ROUTING SECTION
+	// =============================
+
+	emailLimit := middleware.RateLimit(limiter, "email", envLimit("RATE_LIMIT_EMAIL_IP", "5/1h"))
+
+	wellKnown := router.Group("/.well-known")
+	{
+		routes.WellKnownRoutes(wellKnown, authService)
+	}
+
+	// 1️⃣ Public routes (tanpa middleware)
+	public := router.Group("/auth")
+	{
+		routes.AuthRoutes(public, authService,
+			middleware.RateLimit(limiter, "login", envLimit("RATE_LIMIT_LOGIN_IP", "20/1m")),
+			middleware.RateLimit(limiter, "register", envLimit("RATE_LIMIT_REGISTER_IP", "5/1h")),
+			emailLimit,
+		)
+	}
+
+	// 2️⃣ Protected routes (dengan middleware)
+	api := router.Group("/api")
+	api.Use(middleware.AuthMiddleware(a.userRepo))
+	{
+		routes.AlumniRoutes(api, alumniService)
+		routes.ImportRoutes(api, importService)
+		routes.ExportRoutes(api, exportService)
+		routes.StatsRoutes(api, statsService)
+		routes.PekerjaanRoutes(api, pekerjaanService)
+		routes.CompanyRoutes(api, companyService)
+		routes.VocabularyRoutes(api, vocabularyService)
+		routes.TrashRoutes(api, trashService)
+		routes.AuditRoutes(api, auditService)
+		routes.PrivacyRoutes(api, privacyService)
+		routes.UserRoutes(api, authService, emailLimit)
+	}
+
+	// buat router untuk fitur uploads
+	uploads := router.Group("/uploads")
+	uploads.Use(middleware.AuthMiddleware(a.userRepo))
+	{
+		routes.FileRoutes(uploads, uploadService, a.userRepo)
+	}
+
+	// 🔹 Jalankan server
+	fmt.Printf("🚀 Server running on port %s\n", port)
+	return router.Run(":" + port)
+}
+
+// parseGroupRoles membaca OIDC_GROUP_ROLES berbentuk
+// "grup=role,grup=role" (misalnya "staff-admin=admin"). Urutannya
+// menentukan prioritas jika user punya beberapa grup.
+func parseGroupRoles(s string) ([]service.GroupRole, error) {
+	var mappings []service.GroupRole
+	for _, pair := range strings.Split(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		group, role, ok := strings.Cut(pair, "=")
+		group, role = strings.TrimSpace(group), strings.TrimSpace(role)
+		if !ok || group == "" || (role != "admin" && role != "user") {
+			return nil, fmt.Errorf("invalid OIDC_GROUP_ROLES entry %q, want group=admin or group=user", pair)
+		}
+		mappings = append(mappings, service.GroupRole{Group: group, Role: role})
+	}
+	return mappings, nil
+}
+
+// purgeTrash menghapus permanen isi tempat sampah yang lebih lama dari
+// retention setiap interval, dimulai saat server berjalan.
+func purgeTrash(trash *service.TrashService, retention, interval time.Duration) {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		report, err := trash.Purge(ctx, retention, false)
+		cancel()
+		if err != nil {
+			log.Printf("❌ Trash purge failed: %v", err)
+		} else if report.Alumni > 0 || report.Pekerjaan > 0 {
+			log.Printf("🗑️  Purged %d alumni, %d pekerjaan, %d users and %d uploads from trash", report.Alumni, report.Pekerjaan, report.Users, report.Uploads)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// sendOutbox mengirim email yang antre di outbox setiap interval.
+func sendOutbox(d *mail.Dispatcher, interval time.Duration) {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		sent, failed, err := d.Drain(ctx)
+		cancel()
+		if err != nil {
+			log.Printf("❌ Sending outbox failed: %v", err)
+		} else if sent > 0 || failed > 0 {
+			log.Printf("📧 Sent %d email(s), %d failed", sent, failed)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// rotateJWTKeys setiap interval membaca ulang direktori key (key baru dari
+// replica lain), membuat key baru jika key terbaru sudah berumur every,
+// dan menghapus key yang token terakhirnya sudah kedaluwarsa.
+func rotateJWTKeys(keys *jwtkeys.Set, every, interval time.Duration) {
+	for {
+		created, removed, err := keys.Rotate(jwtAlg(), every, service.AccessTokenTTL)
+		if err != nil {
+			log.Printf("❌ JWT key rotation failed: %v", err)
+		}
+		if created != nil {
+			log.Printf("🔑 Created JWT signing key %s; it signs tokens after %s", created.ID, keys.PublishDelay)
+		}
+		if len(removed) > 0 {
+			log.Printf("🗑️  Removed retired JWT keys %v", removed)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// purgeAudit menghapus entri audit log yang lebih lama dari retention
+// setiap interval.
+func purgeAudit(repo *repository.AuditRepository, retention, interval time.Duration) {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		n, err := repo.Purge(ctx, time.Now().Add(-retention))
+		cancel()
+		if err != nil {
+			log.Printf("❌ Audit purge failed: %v", err)
+		} else if n > 0 {
+			log.Printf("🗑️  Purged %d audit log entries", n)
+		}
+		time.Sleep(interval)
+	}
+}